@@ -0,0 +1,192 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.31.1
+// source: controller_status/controller_status.proto
+
+// Controller status package defines protobuf serialization of the ControllerStatus resource.
+
+package controller_status
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ControllerStatusSpec is the protobuf serialization of the ControllerStatus resource.
+type ControllerStatusSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// State of the controller.
+	State string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	// Moment of the last successful reconcile.
+	LastReconcile *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_reconcile,json=lastReconcile,proto3" json:"last_reconcile,omitempty"`
+	// Last error returned by the controller.
+	LastError string `protobuf:"bytes,3,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// Number of controller restarts.
+	Restarts uint64 `protobuf:"varint,4,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	// Length of the QController queue.
+	QueueLength int64 `protobuf:"varint,5,opt,name=queue_length,json=queueLength,proto3" json:"queue_length,omitempty"`
+	// Moment until which the controller is in the backoff.
+	BackoffUntil *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=backoff_until,json=backoffUntil,proto3" json:"backoff_until,omitempty"`
+	// Number of failed QController reconciles.
+	Failures      uint64 `protobuf:"varint,7,opt,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControllerStatusSpec) Reset() {
+	*x = ControllerStatusSpec{}
+	mi := &file_controller_status_controller_status_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControllerStatusSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControllerStatusSpec) ProtoMessage() {}
+
+func (x *ControllerStatusSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_status_controller_status_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControllerStatusSpec.ProtoReflect.Descriptor instead.
+func (*ControllerStatusSpec) Descriptor() ([]byte, []int) {
+	return file_controller_status_controller_status_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ControllerStatusSpec) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ControllerStatusSpec) GetLastReconcile() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReconcile
+	}
+	return nil
+}
+
+func (x *ControllerStatusSpec) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ControllerStatusSpec) GetRestarts() uint64 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *ControllerStatusSpec) GetQueueLength() int64 {
+	if x != nil {
+		return x.QueueLength
+	}
+	return 0
+}
+
+func (x *ControllerStatusSpec) GetBackoffUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BackoffUntil
+	}
+	return nil
+}
+
+func (x *ControllerStatusSpec) GetFailures() uint64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+var File_controller_status_controller_status_proto protoreflect.FileDescriptor
+
+const file_controller_status_controller_status_proto_rawDesc = "" +
+	"\n" +
+	")controller_status/controller_status.proto\x12\x1ecosi.resource.controllerstatus\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaa\x02\n" +
+	"\x14ControllerStatusSpec\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12A\n" +
+	"\x0elast_reconcile\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rlastReconcile\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x03 \x01(\tR\tlastError\x12\x1a\n" +
+	"\brestarts\x18\x04 \x01(\x04R\brestarts\x12!\n" +
+	"\fqueue_length\x18\x05 \x01(\x03R\vqueueLength\x12?\n" +
+	"\rbackoff_until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fbackoffUntil\x12\x1a\n" +
+	"\bfailures\x18\a \x01(\x04R\bfailuresB7Z5github.com/cosi-project/runtime/api/controller_statusb\x06proto3"
+
+var (
+	file_controller_status_controller_status_proto_rawDescOnce sync.Once
+	file_controller_status_controller_status_proto_rawDescData []byte
+)
+
+func file_controller_status_controller_status_proto_rawDescGZIP() []byte {
+	file_controller_status_controller_status_proto_rawDescOnce.Do(func() {
+		file_controller_status_controller_status_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_controller_status_controller_status_proto_rawDesc), len(file_controller_status_controller_status_proto_rawDesc)))
+	})
+	return file_controller_status_controller_status_proto_rawDescData
+}
+
+var file_controller_status_controller_status_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_controller_status_controller_status_proto_goTypes = []any{
+	(*ControllerStatusSpec)(nil),  // 0: cosi.resource.controllerstatus.ControllerStatusSpec
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_controller_status_controller_status_proto_depIdxs = []int32{
+	1, // 0: cosi.resource.controllerstatus.ControllerStatusSpec.last_reconcile:type_name -> google.protobuf.Timestamp
+	1, // 1: cosi.resource.controllerstatus.ControllerStatusSpec.backoff_until:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_controller_status_controller_status_proto_init() }
+func file_controller_status_controller_status_proto_init() {
+	if File_controller_status_controller_status_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_controller_status_controller_status_proto_rawDesc), len(file_controller_status_controller_status_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_controller_status_controller_status_proto_goTypes,
+		DependencyIndexes: file_controller_status_controller_status_proto_depIdxs,
+		MessageInfos:      file_controller_status_controller_status_proto_msgTypes,
+	}.Build()
+	File_controller_status_controller_status_proto = out.File
+	file_controller_status_controller_status_proto_goTypes = nil
+	file_controller_status_controller_status_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.31.1
+// source: lease/lease.proto
+
+// Lease package defines protobuf serialization of the Lease resource.
+
+package lease
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LeaseSpec is the protobuf serialization of the Lease resource.
+type LeaseSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identity of the lease holder, empty if the lease is released.
+	Holder string `protobuf:"bytes,1,opt,name=holder,proto3" json:"holder,omitempty"`
+	// Moment when the lease was acquired by the current holder.
+	Acquired *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=acquired,proto3" json:"acquired,omitempty"`
+	// Moment when the lease was last renewed.
+	Renewed *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=renewed,proto3" json:"renewed,omitempty"`
+	// Duration of the lease since the last renewal.
+	Duration *durationpb.Duration `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Number of times the lease changed holders.
+	Transitions   uint64 `protobuf:"varint,5,opt,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaseSpec) Reset() {
+	*x = LeaseSpec{}
+	mi := &file_lease_lease_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaseSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseSpec) ProtoMessage() {}
+
+func (x *LeaseSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_lease_lease_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseSpec.ProtoReflect.Descriptor instead.
+func (*LeaseSpec) Descriptor() ([]byte, []int) {
+	return file_lease_lease_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LeaseSpec) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *LeaseSpec) GetAcquired() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Acquired
+	}
+	return nil
+}
+
+func (x *LeaseSpec) GetRenewed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Renewed
+	}
+	return nil
+}
+
+func (x *LeaseSpec) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *LeaseSpec) GetTransitions() uint64 {
+	if x != nil {
+		return x.Transitions
+	}
+	return 0
+}
+
+var File_lease_lease_proto protoreflect.FileDescriptor
+
+const file_lease_lease_proto_rawDesc = "" +
+	"\n" +
+	"\x11lease/lease.proto\x12\x13cosi.resource.lease\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xea\x01\n" +
+	"\tLeaseSpec\x12\x16\n" +
+	"\x06holder\x18\x01 \x01(\tR\x06holder\x126\n" +
+	"\bacquired\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bacquired\x124\n" +
+	"\arenewed\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\arenewed\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12 \n" +
+	"\vtransitions\x18\x05 \x01(\x04R\vtransitionsB+Z)github.com/cosi-project/runtime/api/leaseb\x06proto3"
+
+var (
+	file_lease_lease_proto_rawDescOnce sync.Once
+	file_lease_lease_proto_rawDescData []byte
+)
+
+func file_lease_lease_proto_rawDescGZIP() []byte {
+	file_lease_lease_proto_rawDescOnce.Do(func() {
+		file_lease_lease_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lease_lease_proto_rawDesc), len(file_lease_lease_proto_rawDesc)))
+	})
+	return file_lease_lease_proto_rawDescData
+}
+
+var file_lease_lease_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_lease_lease_proto_goTypes = []any{
+	(*LeaseSpec)(nil),             // 0: cosi.resource.lease.LeaseSpec
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 2: google.protobuf.Duration
+}
+var file_lease_lease_proto_depIdxs = []int32{
+	1, // 0: cosi.resource.lease.LeaseSpec.acquired:type_name -> google.protobuf.Timestamp
+	1, // 1: cosi.resource.lease.LeaseSpec.renewed:type_name -> google.protobuf.Timestamp
+	2, // 2: cosi.resource.lease.LeaseSpec.duration:type_name -> google.protobuf.Duration
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_lease_lease_proto_init() }
+func file_lease_lease_proto_init() {
+	if File_lease_lease_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lease_lease_proto_rawDesc), len(file_lease_lease_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_lease_lease_proto_goTypes,
+		DependencyIndexes: file_lease_lease_proto_depIdxs,
+		MessageInfos:      file_lease_lease_proto_msgTypes,
+	}.Build()
+	File_lease_lease_proto = out.File
+	file_lease_lease_proto_goTypes = nil
+	file_lease_lease_proto_depIdxs = nil
+}
@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.31.1
+// source: lock/lock.proto
+
+// Lock package defines protobuf serialization of the Semaphore resource.
+
+package lock
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SemaphoreSpec is the protobuf serialization of the Semaphore resource.
+type SemaphoreSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of concurrent holders.
+	Capacity uint32 `protobuf:"varint,1,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// Current holders of the semaphore.
+	Holders       []*SemaphoreSpec_Holder `protobuf:"bytes,2,rep,name=holders,proto3" json:"holders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SemaphoreSpec) Reset() {
+	*x = SemaphoreSpec{}
+	mi := &file_lock_lock_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SemaphoreSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SemaphoreSpec) ProtoMessage() {}
+
+func (x *SemaphoreSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_lock_lock_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SemaphoreSpec.ProtoReflect.Descriptor instead.
+func (*SemaphoreSpec) Descriptor() ([]byte, []int) {
+	return file_lock_lock_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SemaphoreSpec) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *SemaphoreSpec) GetHolders() []*SemaphoreSpec_Holder {
+	if x != nil {
+		return x.Holders
+	}
+	return nil
+}
+
+// SemaphoreCounterSpec is the protobuf serialization of the SemaphoreCounter resource.
+type SemaphoreCounterSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Last issued fencing token.
+	LastToken uint64 `protobuf:"varint,1,opt,name=last_token,json=lastToken,proto3" json:"last_token,omitempty"`
+	// Identity of the holder the last token was issued to.
+	LastHolder string `protobuf:"bytes,2,opt,name=last_holder,json=lastHolder,proto3" json:"last_holder,omitempty"`
+	// Moment when the last token was issued.
+	Issued        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=issued,proto3" json:"issued,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SemaphoreCounterSpec) Reset() {
+	*x = SemaphoreCounterSpec{}
+	mi := &file_lock_lock_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SemaphoreCounterSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SemaphoreCounterSpec) ProtoMessage() {}
+
+func (x *SemaphoreCounterSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_lock_lock_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SemaphoreCounterSpec.ProtoReflect.Descriptor instead.
+func (*SemaphoreCounterSpec) Descriptor() ([]byte, []int) {
+	return file_lock_lock_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SemaphoreCounterSpec) GetLastToken() uint64 {
+	if x != nil {
+		return x.LastToken
+	}
+	return 0
+}
+
+func (x *SemaphoreCounterSpec) GetLastHolder() string {
+	if x != nil {
+		return x.LastHolder
+	}
+	return ""
+}
+
+func (x *SemaphoreCounterSpec) GetIssued() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Issued
+	}
+	return nil
+}
+
+type SemaphoreSpec_Holder struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identity of the holder.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Fencing token issued to the holder on acquire.
+	Token uint64 `protobuf:"varint,2,opt,name=token,proto3" json:"token,omitempty"`
+	// Moment when the semaphore was acquired.
+	Acquired *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=acquired,proto3" json:"acquired,omitempty"`
+	// Moment when the lease of the holder expires, unless it is renewed.
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SemaphoreSpec_Holder) Reset() {
+	*x = SemaphoreSpec_Holder{}
+	mi := &file_lock_lock_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SemaphoreSpec_Holder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SemaphoreSpec_Holder) ProtoMessage() {}
+
+func (x *SemaphoreSpec_Holder) ProtoReflect() protoreflect.Message {
+	mi := &file_lock_lock_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SemaphoreSpec_Holder.ProtoReflect.Descriptor instead.
+func (*SemaphoreSpec_Holder) Descriptor() ([]byte, []int) {
+	return file_lock_lock_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *SemaphoreSpec_Holder) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SemaphoreSpec_Holder) GetToken() uint64 {
+	if x != nil {
+		return x.Token
+	}
+	return 0
+}
+
+func (x *SemaphoreSpec_Holder) GetAcquired() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Acquired
+	}
+	return nil
+}
+
+func (x *SemaphoreSpec_Holder) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+var File_lock_lock_proto protoreflect.FileDescriptor
+
+const file_lock_lock_proto_rawDesc = "" +
+	"\n" +
+	"\x0flock/lock.proto\x12\x12cosi.resource.lock\x1a\x1fgoogle/protobuf/timestamp.proto\"\x94\x02\n" +
+	"\rSemaphoreSpec\x12\x1a\n" +
+	"\bcapacity\x18\x01 \x01(\rR\bcapacity\x12B\n" +
+	"\aholders\x18\x02 \x03(\v2(.cosi.resource.lock.SemaphoreSpec.HolderR\aholders\x1a\x9c\x01\n" +
+	"\x06Holder\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\x04R\x05token\x126\n" +
+	"\bacquired\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bacquired\x124\n" +
+	"\aexpires\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aexpiresJ\x04\b\x03\x10\x04\"\x8a\x01\n" +
+	"\x14SemaphoreCounterSpec\x12\x1d\n" +
+	"\n" +
+	"last_token\x18\x01 \x01(\x04R\tlastToken\x12\x1f\n" +
+	"\vlast_holder\x18\x02 \x01(\tR\n" +
+	"lastHolder\x122\n" +
+	"\x06issued\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06issuedB*Z(github.com/cosi-project/runtime/api/lockb\x06proto3"
+
+var (
+	file_lock_lock_proto_rawDescOnce sync.Once
+	file_lock_lock_proto_rawDescData []byte
+)
+
+func file_lock_lock_proto_rawDescGZIP() []byte {
+	file_lock_lock_proto_rawDescOnce.Do(func() {
+		file_lock_lock_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lock_lock_proto_rawDesc), len(file_lock_lock_proto_rawDesc)))
+	})
+	return file_lock_lock_proto_rawDescData
+}
+
+var file_lock_lock_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_lock_lock_proto_goTypes = []any{
+	(*SemaphoreSpec)(nil),         // 0: cosi.resource.lock.SemaphoreSpec
+	(*SemaphoreCounterSpec)(nil),  // 1: cosi.resource.lock.SemaphoreCounterSpec
+	(*SemaphoreSpec_Holder)(nil),  // 2: cosi.resource.lock.SemaphoreSpec.Holder
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_lock_lock_proto_depIdxs = []int32{
+	2, // 0: cosi.resource.lock.SemaphoreSpec.holders:type_name -> cosi.resource.lock.SemaphoreSpec.Holder
+	3, // 1: cosi.resource.lock.SemaphoreCounterSpec.issued:type_name -> google.protobuf.Timestamp
+	3, // 2: cosi.resource.lock.SemaphoreSpec.Holder.acquired:type_name -> google.protobuf.Timestamp
+	3, // 3: cosi.resource.lock.SemaphoreSpec.Holder.expires:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_lock_lock_proto_init() }
+func file_lock_lock_proto_init() {
+	if File_lock_lock_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lock_lock_proto_rawDesc), len(file_lock_lock_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_lock_lock_proto_goTypes,
+		DependencyIndexes: file_lock_lock_proto_depIdxs,
+		MessageInfos:      file_lock_lock_proto_msgTypes,
+	}.Build()
+	File_lock_lock_proto = out.File
+	file_lock_lock_proto_goTypes = nil
+	file_lock_lock_proto_depIdxs = nil
+}
@@ -0,0 +1,235 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.31.1
+// source: transform_definition/transform_definition.proto
+
+// Transform definition package defines protobuf serialization of the TransformDefinition resource.
+
+package transform_definition
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TransformDefinitionSpec is the protobuf serialization of the TransformDefinition resource.
+type TransformDefinitionSpec struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Type of the input resources.
+	InputType string `protobuf:"bytes,1,opt,name=input_type,json=inputType,proto3" json:"input_type,omitempty"`
+	// Type of the output resources.
+	OutputType string `protobuf:"bytes,2,opt,name=output_type,json=outputType,proto3" json:"output_type,omitempty"`
+	// Template of the output resource ID.
+	IdTemplate string `protobuf:"bytes,3,opt,name=id_template,json=idTemplate,proto3" json:"id_template,omitempty"`
+	// Labels the input resources should have to be transformed.
+	LabelSelector map[string]string `protobuf:"bytes,4,rep,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Mappings of the input fields to the output fields.
+	Fields        []*TransformFieldMapping `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransformDefinitionSpec) Reset() {
+	*x = TransformDefinitionSpec{}
+	mi := &file_transform_definition_transform_definition_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransformDefinitionSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransformDefinitionSpec) ProtoMessage() {}
+
+func (x *TransformDefinitionSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_transform_definition_transform_definition_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransformDefinitionSpec.ProtoReflect.Descriptor instead.
+func (*TransformDefinitionSpec) Descriptor() ([]byte, []int) {
+	return file_transform_definition_transform_definition_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TransformDefinitionSpec) GetInputType() string {
+	if x != nil {
+		return x.InputType
+	}
+	return ""
+}
+
+func (x *TransformDefinitionSpec) GetOutputType() string {
+	if x != nil {
+		return x.OutputType
+	}
+	return ""
+}
+
+func (x *TransformDefinitionSpec) GetIdTemplate() string {
+	if x != nil {
+		return x.IdTemplate
+	}
+	return ""
+}
+
+func (x *TransformDefinitionSpec) GetLabelSelector() map[string]string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return nil
+}
+
+func (x *TransformDefinitionSpec) GetFields() []*TransformFieldMapping {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// TransformFieldMapping maps an input field to an output field.
+type TransformFieldMapping struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Path of the input field.
+	From string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	// Path of the output field.
+	To            string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransformFieldMapping) Reset() {
+	*x = TransformFieldMapping{}
+	mi := &file_transform_definition_transform_definition_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransformFieldMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransformFieldMapping) ProtoMessage() {}
+
+func (x *TransformFieldMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_transform_definition_transform_definition_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransformFieldMapping.ProtoReflect.Descriptor instead.
+func (*TransformFieldMapping) Descriptor() ([]byte, []int) {
+	return file_transform_definition_transform_definition_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TransformFieldMapping) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *TransformFieldMapping) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+var File_transform_definition_transform_definition_proto protoreflect.FileDescriptor
+
+const file_transform_definition_transform_definition_proto_rawDesc = "" +
+	"\n" +
+	"/transform_definition/transform_definition.proto\x12!cosi.resource.transformdefinition\"\x84\x03\n" +
+	"\x17TransformDefinitionSpec\x12\x1d\n" +
+	"\n" +
+	"input_type\x18\x01 \x01(\tR\tinputType\x12\x1f\n" +
+	"\voutput_type\x18\x02 \x01(\tR\n" +
+	"outputType\x12\x1f\n" +
+	"\vid_template\x18\x03 \x01(\tR\n" +
+	"idTemplate\x12t\n" +
+	"\x0elabel_selector\x18\x04 \x03(\v2M.cosi.resource.transformdefinition.TransformDefinitionSpec.LabelSelectorEntryR\rlabelSelector\x12P\n" +
+	"\x06fields\x18\x05 \x03(\v28.cosi.resource.transformdefinition.TransformFieldMappingR\x06fields\x1a@\n" +
+	"\x12LabelSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\";\n" +
+	"\x15TransformFieldMapping\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02toB:Z8github.com/cosi-project/runtime/api/transform_definitionb\x06proto3"
+
+var (
+	file_transform_definition_transform_definition_proto_rawDescOnce sync.Once
+	file_transform_definition_transform_definition_proto_rawDescData []byte
+)
+
+func file_transform_definition_transform_definition_proto_rawDescGZIP() []byte {
+	file_transform_definition_transform_definition_proto_rawDescOnce.Do(func() {
+		file_transform_definition_transform_definition_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_transform_definition_transform_definition_proto_rawDesc), len(file_transform_definition_transform_definition_proto_rawDesc)))
+	})
+	return file_transform_definition_transform_definition_proto_rawDescData
+}
+
+var file_transform_definition_transform_definition_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_transform_definition_transform_definition_proto_goTypes = []any{
+	(*TransformDefinitionSpec)(nil), // 0: cosi.resource.transformdefinition.TransformDefinitionSpec
+	(*TransformFieldMapping)(nil),   // 1: cosi.resource.transformdefinition.TransformFieldMapping
+	nil,                             // 2: cosi.resource.transformdefinition.TransformDefinitionSpec.LabelSelectorEntry
+}
+var file_transform_definition_transform_definition_proto_depIdxs = []int32{
+	2, // 0: cosi.resource.transformdefinition.TransformDefinitionSpec.label_selector:type_name -> cosi.resource.transformdefinition.TransformDefinitionSpec.LabelSelectorEntry
+	1, // 1: cosi.resource.transformdefinition.TransformDefinitionSpec.fields:type_name -> cosi.resource.transformdefinition.TransformFieldMapping
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_transform_definition_transform_definition_proto_init() }
+func file_transform_definition_transform_definition_proto_init() {
+	if File_transform_definition_transform_definition_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_transform_definition_transform_definition_proto_rawDesc), len(file_transform_definition_transform_definition_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_transform_definition_transform_definition_proto_goTypes,
+		DependencyIndexes: file_transform_definition_transform_definition_proto_depIdxs,
+		MessageInfos:      file_transform_definition_transform_definition_proto_msgTypes,
+	}.Build()
+	File_transform_definition_transform_definition_proto = out.File
+	file_transform_definition_transform_definition_proto_goTypes = nil
+	file_transform_definition_transform_definition_proto_depIdxs = nil
+}
@@ -46,19 +46,40 @@ const (
 	LabelTerm_LT_NUMERIC LabelTerm_Operation = 6
 	// Label value is less or equal numeric.
 	LabelTerm_LTE_NUMERIC LabelTerm_Operation = 7
+	// Label value is greater.
+	LabelTerm_GT LabelTerm_Operation = 8
+	// Label value is greater or equal.
+	LabelTerm_GTE LabelTerm_Operation = 9
+	// Label value is greater than number.
+	LabelTerm_GT_NUMERIC LabelTerm_Operation = 10
+	// Label value is greater or equal numeric.
+	LabelTerm_GTE_NUMERIC LabelTerm_Operation = 11
+	// Label value is a semantic version matching all constraints (e.g. ">=1.2.0").
+	LabelTerm_SEMVER LabelTerm_Operation = 12
+	// Label value starts with the prefix.
+	LabelTerm_PREFIX LabelTerm_Operation = 13
+	// Label value matches the regular expression.
+	LabelTerm_REGEXP LabelTerm_Operation = 14
 )
 
 // Enum value maps for LabelTerm_Operation.
 var (
 	LabelTerm_Operation_name = map[int32]string{
-		0: "EXISTS",
-		1: "EQUAL",
-		2: "NOT_EXISTS",
-		3: "IN",
-		4: "LT",
-		5: "LTE",
-		6: "LT_NUMERIC",
-		7: "LTE_NUMERIC",
+		0:  "EXISTS",
+		1:  "EQUAL",
+		2:  "NOT_EXISTS",
+		3:  "IN",
+		4:  "LT",
+		5:  "LTE",
+		6:  "LT_NUMERIC",
+		7:  "LTE_NUMERIC",
+		8:  "GT",
+		9:  "GTE",
+		10: "GT_NUMERIC",
+		11: "GTE_NUMERIC",
+		12: "SEMVER",
+		13: "PREFIX",
+		14: "REGEXP",
 	}
 	LabelTerm_Operation_value = map[string]int32{
 		"EXISTS":      0,
@@ -69,6 +90,13 @@ var (
 		"LTE":         5,
 		"LT_NUMERIC":  6,
 		"LTE_NUMERIC": 7,
+		"GT":          8,
+		"GTE":         9,
+		"GT_NUMERIC":  10,
+		"GTE_NUMERIC": 11,
+		"SEMVER":      12,
+		"PREFIX":      13,
+		"REGEXP":      14,
 	}
 )
 
@@ -96,7 +124,7 @@ func (x LabelTerm_Operation) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use LabelTerm_Operation.Descriptor instead.
 func (LabelTerm_Operation) EnumDescriptor() ([]byte, []int) {
-	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{3, 0}
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{4, 0}
 }
 
 // Metadata represents resource metadata.
@@ -107,21 +135,25 @@ func (LabelTerm_Operation) EnumDescriptor() ([]byte, []int) {
 // (phase) indicates whether resource is going through tear down phase.
 // (finalizers) are attached controllers blocking teardown of the resource.
 // (labels) and (annotations) are free-form key-value pairs; labels allow queries.
+// (expires) if set, is a moment after which the resource is torn down and destroyed.
+// (owner_references) point to the parent resources; the resource is garbage collected with its parents.
 type Metadata struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
-	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
-	Owner         string                 `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
-	Phase         string                 `protobuf:"bytes,6,opt,name=phase,proto3" json:"phase,omitempty"`
-	Created       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created,proto3" json:"created,omitempty"`
-	Updated       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated,proto3" json:"updated,omitempty"`
-	Finalizers    []string               `protobuf:"bytes,9,rep,name=finalizers,proto3" json:"finalizers,omitempty"`
-	Annotations   map[string]string      `protobuf:"bytes,11,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Labels        map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Namespace       string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Type            string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id              string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Version         string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Owner           string                 `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	Phase           string                 `protobuf:"bytes,6,opt,name=phase,proto3" json:"phase,omitempty"`
+	Created         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created,proto3" json:"created,omitempty"`
+	Updated         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated,proto3" json:"updated,omitempty"`
+	Finalizers      []string               `protobuf:"bytes,9,rep,name=finalizers,proto3" json:"finalizers,omitempty"`
+	Annotations     map[string]string      `protobuf:"bytes,11,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Labels          map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Expires         *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=expires,proto3" json:"expires,omitempty"`
+	OwnerReferences []*OwnerReference      `protobuf:"bytes,13,rep,name=owner_references,json=ownerReferences,proto3" json:"owner_references,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Metadata) Reset() {
@@ -231,6 +263,81 @@ func (x *Metadata) GetLabels() map[string]string {
 	return nil
 }
 
+func (x *Metadata) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+func (x *Metadata) GetOwnerReferences() []*OwnerReference {
+	if x != nil {
+		return x.OwnerReferences
+	}
+	return nil
+}
+
+// OwnerReference is a pointer to the parent resource.
+type OwnerReference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OwnerReference) Reset() {
+	*x = OwnerReference{}
+	mi := &file_v1alpha1_resource_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OwnerReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OwnerReference) ProtoMessage() {}
+
+func (x *OwnerReference) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_resource_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OwnerReference.ProtoReflect.Descriptor instead.
+func (*OwnerReference) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OwnerReference) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *OwnerReference) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *OwnerReference) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
 // Spec defines content of the resource.
 type Spec struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -244,7 +351,7 @@ type Spec struct {
 
 func (x *Spec) Reset() {
 	*x = Spec{}
-	mi := &file_v1alpha1_resource_proto_msgTypes[1]
+	mi := &file_v1alpha1_resource_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -256,7 +363,7 @@ func (x *Spec) String() string {
 func (*Spec) ProtoMessage() {}
 
 func (x *Spec) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_resource_proto_msgTypes[1]
+	mi := &file_v1alpha1_resource_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -269,7 +376,7 @@ func (x *Spec) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Spec.ProtoReflect.Descriptor instead.
 func (*Spec) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{1}
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Spec) GetProtoSpec() []byte {
@@ -297,7 +404,7 @@ type Resource struct {
 
 func (x *Resource) Reset() {
 	*x = Resource{}
-	mi := &file_v1alpha1_resource_proto_msgTypes[2]
+	mi := &file_v1alpha1_resource_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -309,7 +416,7 @@ func (x *Resource) String() string {
 func (*Resource) ProtoMessage() {}
 
 func (x *Resource) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_resource_proto_msgTypes[2]
+	mi := &file_v1alpha1_resource_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -322,7 +429,7 @@ func (x *Resource) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Resource.ProtoReflect.Descriptor instead.
 func (*Resource) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{2}
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Resource) GetMetadata() *Metadata {
@@ -353,7 +460,7 @@ type LabelTerm struct {
 
 func (x *LabelTerm) Reset() {
 	*x = LabelTerm{}
-	mi := &file_v1alpha1_resource_proto_msgTypes[3]
+	mi := &file_v1alpha1_resource_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -365,7 +472,7 @@ func (x *LabelTerm) String() string {
 func (*LabelTerm) ProtoMessage() {}
 
 func (x *LabelTerm) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_resource_proto_msgTypes[3]
+	mi := &file_v1alpha1_resource_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,7 +485,7 @@ func (x *LabelTerm) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LabelTerm.ProtoReflect.Descriptor instead.
 func (*LabelTerm) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{3}
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *LabelTerm) GetKey() string {
@@ -413,15 +520,19 @@ func (x *LabelTerm) GetInvert() bool {
 //
 // Terms are combined with AND.
 type LabelQuery struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Terms         []*LabelTerm           `protobuf:"bytes,1,rep,name=terms,proto3" json:"terms,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Terms []*LabelTerm           `protobuf:"bytes,1,rep,name=terms,proto3" json:"terms,omitempty"`
+	// Kubernetes-style label selector (e.g. "a=b,c in (d,e),!f"), alternative to the terms.
+	//
+	// If both terms and selector are set, they are combined with AND.
+	Selector      string `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LabelQuery) Reset() {
 	*x = LabelQuery{}
-	mi := &file_v1alpha1_resource_proto_msgTypes[4]
+	mi := &file_v1alpha1_resource_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -433,7 +544,7 @@ func (x *LabelQuery) String() string {
 func (*LabelQuery) ProtoMessage() {}
 
 func (x *LabelQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_resource_proto_msgTypes[4]
+	mi := &file_v1alpha1_resource_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -446,7 +557,7 @@ func (x *LabelQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LabelQuery.ProtoReflect.Descriptor instead.
 func (*LabelQuery) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{4}
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *LabelQuery) GetTerms() []*LabelTerm {
@@ -456,6 +567,13 @@ func (x *LabelQuery) GetTerms() []*LabelTerm {
 	return nil
 }
 
+func (x *LabelQuery) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
 // IDQuery is a query on resource metadata ID.
 type IDQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -466,7 +584,7 @@ type IDQuery struct {
 
 func (x *IDQuery) Reset() {
 	*x = IDQuery{}
-	mi := &file_v1alpha1_resource_proto_msgTypes[5]
+	mi := &file_v1alpha1_resource_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -478,7 +596,7 @@ func (x *IDQuery) String() string {
 func (*IDQuery) ProtoMessage() {}
 
 func (x *IDQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_v1alpha1_resource_proto_msgTypes[5]
+	mi := &file_v1alpha1_resource_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -491,7 +609,7 @@ func (x *IDQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IDQuery.ProtoReflect.Descriptor instead.
 func (*IDQuery) Descriptor() ([]byte, []int) {
-	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{5}
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *IDQuery) GetRegexp() string {
@@ -501,11 +619,56 @@ func (x *IDQuery) GetRegexp() string {
 	return ""
 }
 
+// FilterQuery is a filter expression on resource metadata and spec.
+type FilterQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expression    string                 `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterQuery) Reset() {
+	*x = FilterQuery{}
+	mi := &file_v1alpha1_resource_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterQuery) ProtoMessage() {}
+
+func (x *FilterQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_resource_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterQuery.ProtoReflect.Descriptor instead.
+func (*FilterQuery) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_resource_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FilterQuery) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
 var File_v1alpha1_resource_proto protoreflect.FileDescriptor
 
 const file_v1alpha1_resource_proto_rawDesc = "" +
 	"\n" +
-	"\x17v1alpha1/resource.proto\x12\rcosi.resource\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x04\n" +
+	"\x17v1alpha1/resource.proto\x12\rcosi.resource\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x05\n" +
 	"\bMetadata\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
@@ -520,25 +683,31 @@ const file_v1alpha1_resource_proto_rawDesc = "" +
 	"finalizers\x12J\n" +
 	"\vannotations\x18\v \x03(\v2(.cosi.resource.Metadata.AnnotationsEntryR\vannotations\x12;\n" +
 	"\x06labels\x18\n" +
-	" \x03(\v2#.cosi.resource.Metadata.LabelsEntryR\x06labels\x1a>\n" +
+	" \x03(\v2#.cosi.resource.Metadata.LabelsEntryR\x06labels\x124\n" +
+	"\aexpires\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\x12H\n" +
+	"\x10owner_references\x18\r \x03(\v2\x1d.cosi.resource.OwnerReferenceR\x0fownerReferences\x1a>\n" +
 	"\x10AnnotationsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a9\n" +
 	"\vLabelsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"B\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"R\n" +
+	"\x0eOwnerReference\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\"B\n" +
 	"\x04Spec\x12\x1d\n" +
 	"\n" +
 	"proto_spec\x18\x01 \x01(\fR\tprotoSpec\x12\x1b\n" +
 	"\tyaml_spec\x18\x02 \x01(\tR\byamlSpec\"h\n" +
 	"\bResource\x123\n" +
 	"\bmetadata\x18\x01 \x01(\v2\x17.cosi.resource.MetadataR\bmetadata\x12'\n" +
-	"\x04spec\x18\x02 \x01(\v2\x13.cosi.resource.SpecR\x04spec\"\xf1\x01\n" +
+	"\x04spec\x18\x02 \x01(\v2\x13.cosi.resource.SpecR\x04spec\"\xc8\x02\n" +
 	"\tLabelTerm\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
 	"\x02op\x18\x02 \x01(\x0e2\".cosi.resource.LabelTerm.OperationR\x02op\x12\x14\n" +
 	"\x05value\x18\x03 \x03(\tR\x05value\x12\x16\n" +
-	"\x06invert\x18\x05 \x01(\bR\x06invert\"p\n" +
+	"\x06invert\x18\x05 \x01(\bR\x06invert\"\xc6\x01\n" +
 	"\tOperation\x12\n" +
 	"\n" +
 	"\x06EXISTS\x10\x00\x12\t\n" +
@@ -550,12 +719,29 @@ const file_v1alpha1_resource_proto_rawDesc = "" +
 	"\x03LTE\x10\x05\x12\x0e\n" +
 	"\n" +
 	"LT_NUMERIC\x10\x06\x12\x0f\n" +
-	"\vLTE_NUMERIC\x10\a\"<\n" +
+	"\vLTE_NUMERIC\x10\a\x12\x06\n" +
+	"\x02GT\x10\b\x12\a\n" +
+	"\x03GTE\x10\t\x12\x0e\n" +
+	"\n" +
+	"GT_NUMERIC\x10\n" +
+	"\x12\x0f\n" +
+	"\vGTE_NUMERIC\x10\v\x12\n" +
+	"\n" +
+	"\x06SEMVER\x10\f\x12\n" +
+	"\n" +
+	"\x06PREFIX\x10\r\x12\n" +
+	"\n" +
+	"\x06REGEXP\x10\x0e\"X\n" +
 	"\n" +
 	"LabelQuery\x12.\n" +
-	"\x05terms\x18\x01 \x03(\v2\x18.cosi.resource.LabelTermR\x05terms\"!\n" +
+	"\x05terms\x18\x01 \x03(\v2\x18.cosi.resource.LabelTermR\x05terms\x12\x1a\n" +
+	"\bselector\x18\x02 \x01(\tR\bselector\"!\n" +
 	"\aIDQuery\x12\x16\n" +
-	"\x06regexp\x18\x01 \x01(\tR\x06regexpB.Z,github.com/cosi-project/runtime/api/v1alpha1b\x06proto3"
+	"\x06regexp\x18\x01 \x01(\tR\x06regexp\"-\n" +
+	"\vFilterQuery\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expressionB.Z,github.com/cosi-project/runtime/api/v1alpha1b\x06proto3"
 
 var (
 	file_v1alpha1_resource_proto_rawDescOnce sync.Once
@@ -570,33 +756,37 @@ func file_v1alpha1_resource_proto_rawDescGZIP() []byte {
 }
 
 var file_v1alpha1_resource_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_v1alpha1_resource_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_v1alpha1_resource_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_v1alpha1_resource_proto_goTypes = []any{
 	(LabelTerm_Operation)(0),      // 0: cosi.resource.LabelTerm.Operation
 	(*Metadata)(nil),              // 1: cosi.resource.Metadata
-	(*Spec)(nil),                  // 2: cosi.resource.Spec
-	(*Resource)(nil),              // 3: cosi.resource.Resource
-	(*LabelTerm)(nil),             // 4: cosi.resource.LabelTerm
-	(*LabelQuery)(nil),            // 5: cosi.resource.LabelQuery
-	(*IDQuery)(nil),               // 6: cosi.resource.IDQuery
-	nil,                           // 7: cosi.resource.Metadata.AnnotationsEntry
-	nil,                           // 8: cosi.resource.Metadata.LabelsEntry
-	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*OwnerReference)(nil),        // 2: cosi.resource.OwnerReference
+	(*Spec)(nil),                  // 3: cosi.resource.Spec
+	(*Resource)(nil),              // 4: cosi.resource.Resource
+	(*LabelTerm)(nil),             // 5: cosi.resource.LabelTerm
+	(*LabelQuery)(nil),            // 6: cosi.resource.LabelQuery
+	(*IDQuery)(nil),               // 7: cosi.resource.IDQuery
+	(*FilterQuery)(nil),           // 8: cosi.resource.FilterQuery
+	nil,                           // 9: cosi.resource.Metadata.AnnotationsEntry
+	nil,                           // 10: cosi.resource.Metadata.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
 }
 var file_v1alpha1_resource_proto_depIdxs = []int32{
-	9, // 0: cosi.resource.Metadata.created:type_name -> google.protobuf.Timestamp
-	9, // 1: cosi.resource.Metadata.updated:type_name -> google.protobuf.Timestamp
-	7, // 2: cosi.resource.Metadata.annotations:type_name -> cosi.resource.Metadata.AnnotationsEntry
-	8, // 3: cosi.resource.Metadata.labels:type_name -> cosi.resource.Metadata.LabelsEntry
-	1, // 4: cosi.resource.Resource.metadata:type_name -> cosi.resource.Metadata
-	2, // 5: cosi.resource.Resource.spec:type_name -> cosi.resource.Spec
-	0, // 6: cosi.resource.LabelTerm.op:type_name -> cosi.resource.LabelTerm.Operation
-	4, // 7: cosi.resource.LabelQuery.terms:type_name -> cosi.resource.LabelTerm
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	11, // 0: cosi.resource.Metadata.created:type_name -> google.protobuf.Timestamp
+	11, // 1: cosi.resource.Metadata.updated:type_name -> google.protobuf.Timestamp
+	9,  // 2: cosi.resource.Metadata.annotations:type_name -> cosi.resource.Metadata.AnnotationsEntry
+	10, // 3: cosi.resource.Metadata.labels:type_name -> cosi.resource.Metadata.LabelsEntry
+	11, // 4: cosi.resource.Metadata.expires:type_name -> google.protobuf.Timestamp
+	2,  // 5: cosi.resource.Metadata.owner_references:type_name -> cosi.resource.OwnerReference
+	1,  // 6: cosi.resource.Resource.metadata:type_name -> cosi.resource.Metadata
+	3,  // 7: cosi.resource.Resource.spec:type_name -> cosi.resource.Spec
+	0,  // 8: cosi.resource.LabelTerm.op:type_name -> cosi.resource.LabelTerm.Operation
+	5,  // 9: cosi.resource.LabelQuery.terms:type_name -> cosi.resource.LabelTerm
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_v1alpha1_resource_proto_init() }
@@ -610,7 +800,7 @@ func file_v1alpha1_resource_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1alpha1_resource_proto_rawDesc), len(file_v1alpha1_resource_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   8,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
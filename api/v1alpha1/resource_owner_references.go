@@ -0,0 +1,32 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+// GetOwnerReferencesProto returns the owner references of the resource.
+//
+// GetOwnerReferencesProto implements resource.MetadataOwnerReferencesProto, so that the owner references
+// are read by resource.NewMetadataFromProto.
+func (x *Metadata) GetOwnerReferencesProto() []interface {
+	GetNamespace() string
+	GetType() string
+	GetId() string //nolint:revive,stylecheck
+} {
+	refs := x.GetOwnerReferences()
+	if len(refs) == 0 {
+		return nil
+	}
+
+	result := make([]interface {
+		GetNamespace() string
+		GetType() string
+		GetId() string //nolint:revive,stylecheck
+	}, 0, len(refs))
+
+	for _, ref := range refs {
+		result = append(result, ref)
+	}
+
+	return result
+}
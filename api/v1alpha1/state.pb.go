@@ -80,6 +80,55 @@ func (EventType) EnumDescriptor() ([]byte, []int) {
 	return file_v1alpha1_state_proto_rawDescGZIP(), []int{0}
 }
 
+type DiffEntry_Operation int32
+
+const (
+	DiffEntry_ADDED   DiffEntry_Operation = 0
+	DiffEntry_REMOVED DiffEntry_Operation = 1
+	DiffEntry_CHANGED DiffEntry_Operation = 2
+)
+
+// Enum value maps for DiffEntry_Operation.
+var (
+	DiffEntry_Operation_name = map[int32]string{
+		0: "ADDED",
+		1: "REMOVED",
+		2: "CHANGED",
+	}
+	DiffEntry_Operation_value = map[string]int32{
+		"ADDED":   0,
+		"REMOVED": 1,
+		"CHANGED": 2,
+	}
+)
+
+func (x DiffEntry_Operation) Enum() *DiffEntry_Operation {
+	p := new(DiffEntry_Operation)
+	*p = x
+	return p
+}
+
+func (x DiffEntry_Operation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DiffEntry_Operation) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1alpha1_state_proto_enumTypes[1].Descriptor()
+}
+
+func (DiffEntry_Operation) Type() protoreflect.EnumType {
+	return &file_v1alpha1_state_proto_enumTypes[1]
+}
+
+func (x DiffEntry_Operation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DiffEntry_Operation.Descriptor instead.
+func (DiffEntry_Operation) EnumDescriptor() ([]byte, []int) {
+	return file_v1alpha1_state_proto_rawDescGZIP(), []int{27, 0}
+}
+
 // Event is emitted when resource changes.
 type Event struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -369,6 +418,7 @@ type ListOptions struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	LabelQuery    []*LabelQuery          `protobuf:"bytes,1,rep,name=label_query,json=labelQuery,proto3" json:"label_query,omitempty"`
 	IdQuery       *IDQuery               `protobuf:"bytes,2,opt,name=id_query,json=idQuery,proto3" json:"id_query,omitempty"`
+	FilterQuery   *FilterQuery           `protobuf:"bytes,3,opt,name=filter_query,json=filterQuery,proto3" json:"filter_query,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -417,6 +467,13 @@ func (x *ListOptions) GetIdQuery() *IDQuery {
 	return nil
 }
 
+func (x *ListOptions) GetFilterQuery() *FilterQuery {
+	if x != nil {
+		return x.FilterQuery
+	}
+	return nil
+}
+
 type ListResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Resource      *Resource              `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
@@ -985,6 +1042,7 @@ type WatchOptions struct {
 	Aggregated        bool                   `protobuf:"varint,5,opt,name=aggregated,proto3" json:"aggregated,omitempty"`
 	StartFromBookmark []byte                 `protobuf:"bytes,6,opt,name=start_from_bookmark,json=startFromBookmark,proto3,oneof" json:"start_from_bookmark,omitempty"`
 	BootstrapBookmark bool                   `protobuf:"varint,7,opt,name=bootstrap_bookmark,json=bootstrapBookmark,proto3" json:"bootstrap_bookmark,omitempty"`
+	FilterQuery       *FilterQuery           `protobuf:"bytes,8,opt,name=filter_query,json=filterQuery,proto3" json:"filter_query,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -1068,6 +1126,13 @@ func (x *WatchOptions) GetBootstrapBookmark() bool {
 	return false
 }
 
+func (x *WatchOptions) GetFilterQuery() *FilterQuery {
+	if x != nil {
+		return x.FilterQuery
+	}
+	return nil
+}
+
 type WatchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Event         []*Event               `protobuf:"bytes,1,rep,name=event,proto3" json:"event,omitempty"`
@@ -1417,6 +1482,315 @@ func (*TeardownAndDestroyResponse) Descriptor() ([]byte, []int) {
 	return file_v1alpha1_state_proto_rawDescGZIP(), []int{24}
 }
 
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Options       *HistoryOptions        `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_v1alpha1_state_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_state_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_state_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *HistoryRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetOptions() *HistoryOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type HistoryOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of versions to return, zero returns all recorded versions.
+	Depth int32 `protobuf:"varint,1,opt,name=depth,proto3" json:"depth,omitempty"`
+	// Include diffs between consecutive versions into the response.
+	Diff          bool `protobuf:"varint,2,opt,name=diff,proto3" json:"diff,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryOptions) Reset() {
+	*x = HistoryOptions{}
+	mi := &file_v1alpha1_state_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryOptions) ProtoMessage() {}
+
+func (x *HistoryOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_state_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryOptions.ProtoReflect.Descriptor instead.
+func (*HistoryOptions) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_state_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *HistoryOptions) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *HistoryOptions) GetDiff() bool {
+	if x != nil {
+		return x.Diff
+	}
+	return false
+}
+
+// DiffEntry describes a single changed field between two resource versions.
+type DiffEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Path to the field, e.g. `metadata.labels.app` or `spec.items[1]`.
+	Path string              `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Op   DiffEntry_Operation `protobuf:"varint,2,opt,name=op,proto3,enum=cosi.resource.DiffEntry_Operation" json:"op,omitempty"`
+	// YAML representation of the old value (unset for ADDED).
+	OldValue *string `protobuf:"bytes,3,opt,name=old_value,json=oldValue,proto3,oneof" json:"old_value,omitempty"`
+	// YAML representation of the new value (unset for REMOVED).
+	NewValue      *string `protobuf:"bytes,4,opt,name=new_value,json=newValue,proto3,oneof" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffEntry) Reset() {
+	*x = DiffEntry{}
+	mi := &file_v1alpha1_state_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffEntry) ProtoMessage() {}
+
+func (x *DiffEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_state_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffEntry.ProtoReflect.Descriptor instead.
+func (*DiffEntry) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_state_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DiffEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DiffEntry) GetOp() DiffEntry_Operation {
+	if x != nil {
+		return x.Op
+	}
+	return DiffEntry_ADDED
+}
+
+func (x *DiffEntry) GetOldValue() string {
+	if x != nil && x.OldValue != nil {
+		return *x.OldValue
+	}
+	return ""
+}
+
+func (x *DiffEntry) GetNewValue() string {
+	if x != nil && x.NewValue != nil {
+		return *x.NewValue
+	}
+	return ""
+}
+
+// ResourceDiff is a difference between two versions of a resource.
+type ResourceDiff struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldVersion    string                 `protobuf:"bytes,1,opt,name=old_version,json=oldVersion,proto3" json:"old_version,omitempty"`
+	NewVersion    string                 `protobuf:"bytes,2,opt,name=new_version,json=newVersion,proto3" json:"new_version,omitempty"`
+	Entries       []*DiffEntry           `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourceDiff) Reset() {
+	*x = ResourceDiff{}
+	mi := &file_v1alpha1_state_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceDiff) ProtoMessage() {}
+
+func (x *ResourceDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_state_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceDiff.ProtoReflect.Descriptor instead.
+func (*ResourceDiff) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_state_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ResourceDiff) GetOldVersion() string {
+	if x != nil {
+		return x.OldVersion
+	}
+	return ""
+}
+
+func (x *ResourceDiff) GetNewVersion() string {
+	if x != nil {
+		return x.NewVersion
+	}
+	return ""
+}
+
+func (x *ResourceDiff) GetEntries() []*DiffEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type HistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resource versions, most recent first.
+	Resources []*Resource `protobuf:"bytes,1,rep,name=resources,proto3" json:"resources,omitempty"`
+	// Diffs between consecutive versions: diffs[i] is a change from resources[i+1] to resources[i].
+	Diffs         []*ResourceDiff `protobuf:"bytes,2,rep,name=diffs,proto3" json:"diffs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_v1alpha1_state_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1alpha1_state_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_v1alpha1_state_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *HistoryResponse) GetResources() []*Resource {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetDiffs() []*ResourceDiff {
+	if x != nil {
+		return x.Diffs
+	}
+	return nil
+}
+
 var File_v1alpha1_state_proto protoreflect.FileDescriptor
 
 const file_v1alpha1_state_proto_rawDesc = "" +
@@ -1444,11 +1818,12 @@ const file_v1alpha1_state_proto_rawDesc = "" +
 	"\vListRequest\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x124\n" +
-	"\aoptions\x18\x03 \x01(\v2\x1a.cosi.resource.ListOptionsR\aoptions\"|\n" +
+	"\aoptions\x18\x03 \x01(\v2\x1a.cosi.resource.ListOptionsR\aoptions\"\xbb\x01\n" +
 	"\vListOptions\x12:\n" +
 	"\vlabel_query\x18\x01 \x03(\v2\x19.cosi.resource.LabelQueryR\n" +
 	"labelQuery\x121\n" +
-	"\bid_query\x18\x02 \x01(\v2\x16.cosi.resource.IDQueryR\aidQuery\"C\n" +
+	"\bid_query\x18\x02 \x01(\v2\x16.cosi.resource.IDQueryR\aidQuery\x12=\n" +
+	"\ffilter_query\x18\x03 \x01(\v2\x1a.cosi.resource.FilterQueryR\vfilterQuery\"C\n" +
 	"\fListResponse\x123\n" +
 	"\bresource\x18\x01 \x01(\v2\x17.cosi.resource.ResourceR\bresource\"|\n" +
 	"\rCreateRequest\x123\n" +
@@ -1482,7 +1857,7 @@ const file_v1alpha1_state_proto_rawDesc = "" +
 	"\aoptions\x18\x04 \x01(\v2\x1b.cosi.resource.WatchOptionsR\aoptions\x12\x1f\n" +
 	"\vapi_version\x18\x05 \x01(\x05R\n" +
 	"apiVersionB\x05\n" +
-	"\x03_id\"\xe9\x02\n" +
+	"\x03_id\"\xa8\x03\n" +
 	"\fWatchOptions\x12-\n" +
 	"\x12bootstrap_contents\x18\x01 \x01(\bR\x11bootstrapContents\x12\x1f\n" +
 	"\vtail_events\x18\x02 \x01(\x05R\n" +
@@ -1494,7 +1869,8 @@ const file_v1alpha1_state_proto_rawDesc = "" +
 	"aggregated\x18\x05 \x01(\bR\n" +
 	"aggregated\x123\n" +
 	"\x13start_from_bookmark\x18\x06 \x01(\fH\x00R\x11startFromBookmark\x88\x01\x01\x12-\n" +
-	"\x12bootstrap_bookmark\x18\a \x01(\bR\x11bootstrapBookmarkB\x16\n" +
+	"\x12bootstrap_bookmark\x18\a \x01(\bR\x11bootstrapBookmark\x12=\n" +
+	"\ffilter_query\x18\b \x01(\v2\x1a.cosi.resource.FilterQueryR\vfilterQueryB\x16\n" +
 	"\x14_start_from_bookmark\";\n" +
 	"\rWatchResponse\x12*\n" +
 	"\x05event\x18\x01 \x03(\v2\x14.cosi.resource.EventR\x05event\"\x8d\x01\n" +
@@ -1514,14 +1890,44 @@ const file_v1alpha1_state_proto_rawDesc = "" +
 	"\aoptions\x18\x04 \x01(\v2(.cosi.resource.TeardownAndDestroyOptionsR\aoptions\"1\n" +
 	"\x19TeardownAndDestroyOptions\x12\x14\n" +
 	"\x05owner\x18\x01 \x01(\tR\x05owner\"\x1c\n" +
-	"\x1aTeardownAndDestroyResponse*]\n" +
+	"\x1aTeardownAndDestroyResponse\"\x8b\x01\n" +
+	"\x0eHistoryRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x127\n" +
+	"\aoptions\x18\x04 \x01(\v2\x1d.cosi.resource.HistoryOptionsR\aoptions\":\n" +
+	"\x0eHistoryOptions\x12\x14\n" +
+	"\x05depth\x18\x01 \x01(\x05R\x05depth\x12\x12\n" +
+	"\x04diff\x18\x02 \x01(\bR\x04diff\"\xe5\x01\n" +
+	"\tDiffEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x122\n" +
+	"\x02op\x18\x02 \x01(\x0e2\".cosi.resource.DiffEntry.OperationR\x02op\x12 \n" +
+	"\told_value\x18\x03 \x01(\tH\x00R\boldValue\x88\x01\x01\x12 \n" +
+	"\tnew_value\x18\x04 \x01(\tH\x01R\bnewValue\x88\x01\x01\"0\n" +
+	"\tOperation\x12\t\n" +
+	"\x05ADDED\x10\x00\x12\v\n" +
+	"\aREMOVED\x10\x01\x12\v\n" +
+	"\aCHANGED\x10\x02B\f\n" +
+	"\n" +
+	"_old_valueB\f\n" +
+	"\n" +
+	"_new_value\"\x84\x01\n" +
+	"\fResourceDiff\x12\x1f\n" +
+	"\vold_version\x18\x01 \x01(\tR\n" +
+	"oldVersion\x12\x1f\n" +
+	"\vnew_version\x18\x02 \x01(\tR\n" +
+	"newVersion\x122\n" +
+	"\aentries\x18\x03 \x03(\v2\x18.cosi.resource.DiffEntryR\aentries\"{\n" +
+	"\x0fHistoryResponse\x125\n" +
+	"\tresources\x18\x01 \x03(\v2\x17.cosi.resource.ResourceR\tresources\x121\n" +
+	"\x05diffs\x18\x02 \x03(\v2\x1b.cosi.resource.ResourceDiffR\x05diffs*]\n" +
 	"\tEventType\x12\v\n" +
 	"\aCREATED\x10\x00\x12\v\n" +
 	"\aUPDATED\x10\x01\x12\r\n" +
 	"\tDESTROYED\x10\x02\x12\x10\n" +
 	"\fBOOTSTRAPPED\x10\x03\x12\v\n" +
 	"\aERRORED\x10\x04\x12\b\n" +
-	"\x04NOOP\x10\x052\xde\x04\n" +
+	"\x04NOOP\x10\x052\xa8\x05\n" +
 	"\x05State\x12<\n" +
 	"\x03Get\x12\x19.cosi.resource.GetRequest\x1a\x1a.cosi.resource.GetResponse\x12A\n" +
 	"\x04List\x12\x1a.cosi.resource.ListRequest\x1a\x1b.cosi.resource.ListResponse0\x01\x12E\n" +
@@ -1530,7 +1936,8 @@ const file_v1alpha1_state_proto_rawDesc = "" +
 	"\aDestroy\x12\x1d.cosi.resource.DestroyRequest\x1a\x1e.cosi.resource.DestroyResponse\x12D\n" +
 	"\x05Watch\x12\x1b.cosi.resource.WatchRequest\x1a\x1c.cosi.resource.WatchResponse0\x01\x12K\n" +
 	"\bTeardown\x12\x1e.cosi.resource.TeardownRequest\x1a\x1f.cosi.resource.TeardownResponse\x12i\n" +
-	"\x12TeardownAndDestroy\x12(.cosi.resource.TeardownAndDestroyRequest\x1a).cosi.resource.TeardownAndDestroyResponseB.Z,github.com/cosi-project/runtime/api/v1alpha1b\x06proto3"
+	"\x12TeardownAndDestroy\x12(.cosi.resource.TeardownAndDestroyRequest\x1a).cosi.resource.TeardownAndDestroyResponse\x12H\n" +
+	"\aHistory\x12\x1d.cosi.resource.HistoryRequest\x1a\x1e.cosi.resource.HistoryResponseB.Z,github.com/cosi-project/runtime/api/v1alpha1b\x06proto3"
 
 var (
 	file_v1alpha1_state_proto_rawDescOnce sync.Once
@@ -1544,83 +1951,99 @@ func file_v1alpha1_state_proto_rawDescGZIP() []byte {
 	return file_v1alpha1_state_proto_rawDescData
 }
 
-var file_v1alpha1_state_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_v1alpha1_state_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_v1alpha1_state_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_v1alpha1_state_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_v1alpha1_state_proto_goTypes = []any{
 	(EventType)(0),                     // 0: cosi.resource.EventType
-	(*Event)(nil),                      // 1: cosi.resource.Event
-	(*GetRequest)(nil),                 // 2: cosi.resource.GetRequest
-	(*GetOptions)(nil),                 // 3: cosi.resource.GetOptions
-	(*GetResponse)(nil),                // 4: cosi.resource.GetResponse
-	(*ListRequest)(nil),                // 5: cosi.resource.ListRequest
-	(*ListOptions)(nil),                // 6: cosi.resource.ListOptions
-	(*ListResponse)(nil),               // 7: cosi.resource.ListResponse
-	(*CreateRequest)(nil),              // 8: cosi.resource.CreateRequest
-	(*CreateOptions)(nil),              // 9: cosi.resource.CreateOptions
-	(*CreateResponse)(nil),             // 10: cosi.resource.CreateResponse
-	(*UpdateRequest)(nil),              // 11: cosi.resource.UpdateRequest
-	(*UpdateOptions)(nil),              // 12: cosi.resource.UpdateOptions
-	(*UpdateResponse)(nil),             // 13: cosi.resource.UpdateResponse
-	(*DestroyRequest)(nil),             // 14: cosi.resource.DestroyRequest
-	(*DestroyOptions)(nil),             // 15: cosi.resource.DestroyOptions
-	(*DestroyResponse)(nil),            // 16: cosi.resource.DestroyResponse
-	(*WatchRequest)(nil),               // 17: cosi.resource.WatchRequest
-	(*WatchOptions)(nil),               // 18: cosi.resource.WatchOptions
-	(*WatchResponse)(nil),              // 19: cosi.resource.WatchResponse
-	(*TeardownRequest)(nil),            // 20: cosi.resource.TeardownRequest
-	(*TeardownOptions)(nil),            // 21: cosi.resource.TeardownOptions
-	(*TeardownResponse)(nil),           // 22: cosi.resource.TeardownResponse
-	(*TeardownAndDestroyRequest)(nil),  // 23: cosi.resource.TeardownAndDestroyRequest
-	(*TeardownAndDestroyOptions)(nil),  // 24: cosi.resource.TeardownAndDestroyOptions
-	(*TeardownAndDestroyResponse)(nil), // 25: cosi.resource.TeardownAndDestroyResponse
-	(*Resource)(nil),                   // 26: cosi.resource.Resource
-	(*LabelQuery)(nil),                 // 27: cosi.resource.LabelQuery
-	(*IDQuery)(nil),                    // 28: cosi.resource.IDQuery
+	(DiffEntry_Operation)(0),           // 1: cosi.resource.DiffEntry.Operation
+	(*Event)(nil),                      // 2: cosi.resource.Event
+	(*GetRequest)(nil),                 // 3: cosi.resource.GetRequest
+	(*GetOptions)(nil),                 // 4: cosi.resource.GetOptions
+	(*GetResponse)(nil),                // 5: cosi.resource.GetResponse
+	(*ListRequest)(nil),                // 6: cosi.resource.ListRequest
+	(*ListOptions)(nil),                // 7: cosi.resource.ListOptions
+	(*ListResponse)(nil),               // 8: cosi.resource.ListResponse
+	(*CreateRequest)(nil),              // 9: cosi.resource.CreateRequest
+	(*CreateOptions)(nil),              // 10: cosi.resource.CreateOptions
+	(*CreateResponse)(nil),             // 11: cosi.resource.CreateResponse
+	(*UpdateRequest)(nil),              // 12: cosi.resource.UpdateRequest
+	(*UpdateOptions)(nil),              // 13: cosi.resource.UpdateOptions
+	(*UpdateResponse)(nil),             // 14: cosi.resource.UpdateResponse
+	(*DestroyRequest)(nil),             // 15: cosi.resource.DestroyRequest
+	(*DestroyOptions)(nil),             // 16: cosi.resource.DestroyOptions
+	(*DestroyResponse)(nil),            // 17: cosi.resource.DestroyResponse
+	(*WatchRequest)(nil),               // 18: cosi.resource.WatchRequest
+	(*WatchOptions)(nil),               // 19: cosi.resource.WatchOptions
+	(*WatchResponse)(nil),              // 20: cosi.resource.WatchResponse
+	(*TeardownRequest)(nil),            // 21: cosi.resource.TeardownRequest
+	(*TeardownOptions)(nil),            // 22: cosi.resource.TeardownOptions
+	(*TeardownResponse)(nil),           // 23: cosi.resource.TeardownResponse
+	(*TeardownAndDestroyRequest)(nil),  // 24: cosi.resource.TeardownAndDestroyRequest
+	(*TeardownAndDestroyOptions)(nil),  // 25: cosi.resource.TeardownAndDestroyOptions
+	(*TeardownAndDestroyResponse)(nil), // 26: cosi.resource.TeardownAndDestroyResponse
+	(*HistoryRequest)(nil),             // 27: cosi.resource.HistoryRequest
+	(*HistoryOptions)(nil),             // 28: cosi.resource.HistoryOptions
+	(*DiffEntry)(nil),                  // 29: cosi.resource.DiffEntry
+	(*ResourceDiff)(nil),               // 30: cosi.resource.ResourceDiff
+	(*HistoryResponse)(nil),            // 31: cosi.resource.HistoryResponse
+	(*Resource)(nil),                   // 32: cosi.resource.Resource
+	(*LabelQuery)(nil),                 // 33: cosi.resource.LabelQuery
+	(*IDQuery)(nil),                    // 34: cosi.resource.IDQuery
+	(*FilterQuery)(nil),                // 35: cosi.resource.FilterQuery
 }
 var file_v1alpha1_state_proto_depIdxs = []int32{
-	26, // 0: cosi.resource.Event.resource:type_name -> cosi.resource.Resource
-	26, // 1: cosi.resource.Event.old:type_name -> cosi.resource.Resource
+	32, // 0: cosi.resource.Event.resource:type_name -> cosi.resource.Resource
+	32, // 1: cosi.resource.Event.old:type_name -> cosi.resource.Resource
 	0,  // 2: cosi.resource.Event.event_type:type_name -> cosi.resource.EventType
-	3,  // 3: cosi.resource.GetRequest.options:type_name -> cosi.resource.GetOptions
-	26, // 4: cosi.resource.GetResponse.resource:type_name -> cosi.resource.Resource
-	6,  // 5: cosi.resource.ListRequest.options:type_name -> cosi.resource.ListOptions
-	27, // 6: cosi.resource.ListOptions.label_query:type_name -> cosi.resource.LabelQuery
-	28, // 7: cosi.resource.ListOptions.id_query:type_name -> cosi.resource.IDQuery
-	26, // 8: cosi.resource.ListResponse.resource:type_name -> cosi.resource.Resource
-	26, // 9: cosi.resource.CreateRequest.resource:type_name -> cosi.resource.Resource
-	9,  // 10: cosi.resource.CreateRequest.options:type_name -> cosi.resource.CreateOptions
-	26, // 11: cosi.resource.CreateResponse.resource:type_name -> cosi.resource.Resource
-	26, // 12: cosi.resource.UpdateRequest.new_resource:type_name -> cosi.resource.Resource
-	12, // 13: cosi.resource.UpdateRequest.options:type_name -> cosi.resource.UpdateOptions
-	26, // 14: cosi.resource.UpdateResponse.resource:type_name -> cosi.resource.Resource
-	15, // 15: cosi.resource.DestroyRequest.options:type_name -> cosi.resource.DestroyOptions
-	18, // 16: cosi.resource.WatchRequest.options:type_name -> cosi.resource.WatchOptions
-	27, // 17: cosi.resource.WatchOptions.label_query:type_name -> cosi.resource.LabelQuery
-	28, // 18: cosi.resource.WatchOptions.id_query:type_name -> cosi.resource.IDQuery
-	1,  // 19: cosi.resource.WatchResponse.event:type_name -> cosi.resource.Event
-	21, // 20: cosi.resource.TeardownRequest.options:type_name -> cosi.resource.TeardownOptions
-	24, // 21: cosi.resource.TeardownAndDestroyRequest.options:type_name -> cosi.resource.TeardownAndDestroyOptions
-	2,  // 22: cosi.resource.State.Get:input_type -> cosi.resource.GetRequest
-	5,  // 23: cosi.resource.State.List:input_type -> cosi.resource.ListRequest
-	8,  // 24: cosi.resource.State.Create:input_type -> cosi.resource.CreateRequest
-	11, // 25: cosi.resource.State.Update:input_type -> cosi.resource.UpdateRequest
-	14, // 26: cosi.resource.State.Destroy:input_type -> cosi.resource.DestroyRequest
-	17, // 27: cosi.resource.State.Watch:input_type -> cosi.resource.WatchRequest
-	20, // 28: cosi.resource.State.Teardown:input_type -> cosi.resource.TeardownRequest
-	23, // 29: cosi.resource.State.TeardownAndDestroy:input_type -> cosi.resource.TeardownAndDestroyRequest
-	4,  // 30: cosi.resource.State.Get:output_type -> cosi.resource.GetResponse
-	7,  // 31: cosi.resource.State.List:output_type -> cosi.resource.ListResponse
-	10, // 32: cosi.resource.State.Create:output_type -> cosi.resource.CreateResponse
-	13, // 33: cosi.resource.State.Update:output_type -> cosi.resource.UpdateResponse
-	16, // 34: cosi.resource.State.Destroy:output_type -> cosi.resource.DestroyResponse
-	19, // 35: cosi.resource.State.Watch:output_type -> cosi.resource.WatchResponse
-	22, // 36: cosi.resource.State.Teardown:output_type -> cosi.resource.TeardownResponse
-	25, // 37: cosi.resource.State.TeardownAndDestroy:output_type -> cosi.resource.TeardownAndDestroyResponse
-	30, // [30:38] is the sub-list for method output_type
-	22, // [22:30] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	4,  // 3: cosi.resource.GetRequest.options:type_name -> cosi.resource.GetOptions
+	32, // 4: cosi.resource.GetResponse.resource:type_name -> cosi.resource.Resource
+	7,  // 5: cosi.resource.ListRequest.options:type_name -> cosi.resource.ListOptions
+	33, // 6: cosi.resource.ListOptions.label_query:type_name -> cosi.resource.LabelQuery
+	34, // 7: cosi.resource.ListOptions.id_query:type_name -> cosi.resource.IDQuery
+	35, // 8: cosi.resource.ListOptions.filter_query:type_name -> cosi.resource.FilterQuery
+	32, // 9: cosi.resource.ListResponse.resource:type_name -> cosi.resource.Resource
+	32, // 10: cosi.resource.CreateRequest.resource:type_name -> cosi.resource.Resource
+	10, // 11: cosi.resource.CreateRequest.options:type_name -> cosi.resource.CreateOptions
+	32, // 12: cosi.resource.CreateResponse.resource:type_name -> cosi.resource.Resource
+	32, // 13: cosi.resource.UpdateRequest.new_resource:type_name -> cosi.resource.Resource
+	13, // 14: cosi.resource.UpdateRequest.options:type_name -> cosi.resource.UpdateOptions
+	32, // 15: cosi.resource.UpdateResponse.resource:type_name -> cosi.resource.Resource
+	16, // 16: cosi.resource.DestroyRequest.options:type_name -> cosi.resource.DestroyOptions
+	19, // 17: cosi.resource.WatchRequest.options:type_name -> cosi.resource.WatchOptions
+	33, // 18: cosi.resource.WatchOptions.label_query:type_name -> cosi.resource.LabelQuery
+	34, // 19: cosi.resource.WatchOptions.id_query:type_name -> cosi.resource.IDQuery
+	35, // 20: cosi.resource.WatchOptions.filter_query:type_name -> cosi.resource.FilterQuery
+	2,  // 21: cosi.resource.WatchResponse.event:type_name -> cosi.resource.Event
+	22, // 22: cosi.resource.TeardownRequest.options:type_name -> cosi.resource.TeardownOptions
+	25, // 23: cosi.resource.TeardownAndDestroyRequest.options:type_name -> cosi.resource.TeardownAndDestroyOptions
+	28, // 24: cosi.resource.HistoryRequest.options:type_name -> cosi.resource.HistoryOptions
+	1,  // 25: cosi.resource.DiffEntry.op:type_name -> cosi.resource.DiffEntry.Operation
+	29, // 26: cosi.resource.ResourceDiff.entries:type_name -> cosi.resource.DiffEntry
+	32, // 27: cosi.resource.HistoryResponse.resources:type_name -> cosi.resource.Resource
+	30, // 28: cosi.resource.HistoryResponse.diffs:type_name -> cosi.resource.ResourceDiff
+	3,  // 29: cosi.resource.State.Get:input_type -> cosi.resource.GetRequest
+	6,  // 30: cosi.resource.State.List:input_type -> cosi.resource.ListRequest
+	9,  // 31: cosi.resource.State.Create:input_type -> cosi.resource.CreateRequest
+	12, // 32: cosi.resource.State.Update:input_type -> cosi.resource.UpdateRequest
+	15, // 33: cosi.resource.State.Destroy:input_type -> cosi.resource.DestroyRequest
+	18, // 34: cosi.resource.State.Watch:input_type -> cosi.resource.WatchRequest
+	21, // 35: cosi.resource.State.Teardown:input_type -> cosi.resource.TeardownRequest
+	24, // 36: cosi.resource.State.TeardownAndDestroy:input_type -> cosi.resource.TeardownAndDestroyRequest
+	27, // 37: cosi.resource.State.History:input_type -> cosi.resource.HistoryRequest
+	5,  // 38: cosi.resource.State.Get:output_type -> cosi.resource.GetResponse
+	8,  // 39: cosi.resource.State.List:output_type -> cosi.resource.ListResponse
+	11, // 40: cosi.resource.State.Create:output_type -> cosi.resource.CreateResponse
+	14, // 41: cosi.resource.State.Update:output_type -> cosi.resource.UpdateResponse
+	17, // 42: cosi.resource.State.Destroy:output_type -> cosi.resource.DestroyResponse
+	20, // 43: cosi.resource.State.Watch:output_type -> cosi.resource.WatchResponse
+	23, // 44: cosi.resource.State.Teardown:output_type -> cosi.resource.TeardownResponse
+	26, // 45: cosi.resource.State.TeardownAndDestroy:output_type -> cosi.resource.TeardownAndDestroyResponse
+	31, // 46: cosi.resource.State.History:output_type -> cosi.resource.HistoryResponse
+	38, // [38:47] is the sub-list for method output_type
+	29, // [29:38] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_v1alpha1_state_proto_init() }
@@ -1633,13 +2056,14 @@ func file_v1alpha1_state_proto_init() {
 	file_v1alpha1_state_proto_msgTypes[11].OneofWrappers = []any{}
 	file_v1alpha1_state_proto_msgTypes[16].OneofWrappers = []any{}
 	file_v1alpha1_state_proto_msgTypes[17].OneofWrappers = []any{}
+	file_v1alpha1_state_proto_msgTypes[27].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1alpha1_state_proto_rawDesc), len(file_v1alpha1_state_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   25,
+			NumEnums:      2,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
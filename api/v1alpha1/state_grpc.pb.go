@@ -28,6 +28,7 @@ const (
 	State_Watch_FullMethodName              = "/cosi.resource.State/Watch"
 	State_Teardown_FullMethodName           = "/cosi.resource.State/Teardown"
 	State_TeardownAndDestroy_FullMethodName = "/cosi.resource.State/TeardownAndDestroy"
+	State_History_FullMethodName            = "/cosi.resource.State/History"
 )
 
 // StateClient is the client API for State service.
@@ -74,6 +75,11 @@ type StateClient interface {
 	// It's not an error to tear down a resource which is already being torn down.
 	// The call blocks until the resource has no pending finalizers and has been destroyed.
 	TeardownAndDestroy(ctx context.Context, in *TeardownAndDestroyRequest, opts ...grpc.CallOption) (*TeardownAndDestroyResponse, error)
+	// History returns previous versions of a resource, most recent first.
+	//
+	// If the resource has no recorded versions, error is returned.
+	// If the state doesn't keep history, Unimplemented error is returned.
+	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
 }
 
 type stateClient struct {
@@ -182,6 +188,16 @@ func (c *stateClient) TeardownAndDestroy(ctx context.Context, in *TeardownAndDes
 	return out, nil
 }
 
+func (c *stateClient) History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, State_History_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StateServer is the server API for State service.
 // All implementations must embed UnimplementedStateServer
 // for forward compatibility.
@@ -226,6 +242,11 @@ type StateServer interface {
 	// It's not an error to tear down a resource which is already being torn down.
 	// The call blocks until the resource has no pending finalizers and has been destroyed.
 	TeardownAndDestroy(context.Context, *TeardownAndDestroyRequest) (*TeardownAndDestroyResponse, error)
+	// History returns previous versions of a resource, most recent first.
+	//
+	// If the resource has no recorded versions, error is returned.
+	// If the state doesn't keep history, Unimplemented error is returned.
+	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
 	mustEmbedUnimplementedStateServer()
 }
 
@@ -260,6 +281,9 @@ func (UnimplementedStateServer) Teardown(context.Context, *TeardownRequest) (*Te
 func (UnimplementedStateServer) TeardownAndDestroy(context.Context, *TeardownAndDestroyRequest) (*TeardownAndDestroyResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method TeardownAndDestroy not implemented")
 }
+func (UnimplementedStateServer) History(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method History not implemented")
+}
 func (UnimplementedStateServer) mustEmbedUnimplementedStateServer() {}
 func (UnimplementedStateServer) testEmbeddedByValue()               {}
 
@@ -411,6 +435,24 @@ func _State_TeardownAndDestroy_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _State_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: State_History_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServer).History(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // State_ServiceDesc is the grpc.ServiceDesc for State service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -442,6 +484,10 @@ var State_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TeardownAndDestroy",
 			Handler:    _State_TeardownAndDestroy_Handler,
 		},
+		{
+			MethodName: "History",
+			Handler:    _State_History_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
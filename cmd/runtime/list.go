@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.yaml.in/yaml/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
+)
+
+// runList lists the resources of the running runtime service.
+//
+// Usage: runtime [-socket-path path | -grpc-address address] list [-namespace ns] [-selector selector] <type>.
+func runList(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+
+	namespace := flags.String("namespace", "default", "namespace of the resources")
+	selector := flags.String("selector", "", `label selector, e.g. "a=b,c!=d,e in (x,y),!f,g<10"`)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected exactly one argument: resource type")
+	}
+
+	var opts []state.ListOption
+
+	if *selector != "" {
+		query, err := resource.ParseLabelSelector(*selector)
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, state.WithLabelQuery(resource.RawLabelQuery(query)))
+	}
+
+	// resources are printed as is, so their types don't need to be registered
+	opts = append(opts, state.WithListUnmarshalOptions(state.WithSkipProtobufUnmarshal()))
+
+	target := "unix://" + socketPath
+	if grpcAddressAndPort != "" {
+		target = grpcAddressAndPort
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("error connecting to the runtime service: %w", err)
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	st := state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(conn)))
+
+	items, err := st.List(ctx, resource.NewMetadata(*namespace, flags.Arg(0), "", resource.VersionUndefined), opts...)
+	if err != nil {
+		return fmt.Errorf("error listing resources: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+
+	for _, item := range items.Items {
+		out, err := resource.MarshalYAML(item)
+		if err != nil {
+			return err
+		}
+
+		if err = encoder.Encode(out); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
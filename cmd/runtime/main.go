@@ -46,6 +46,14 @@ func main() {
 	flag.StringVar(&httpServerAndPort, "http-address", "", `the http address and port to bind to. It can be used to access the metrics endpoint "/debug/vars"`)
 	flag.Parse()
 
+	if flag.Arg(0) == "list" {
+		if err := runList(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	if err := run(); err != nil {
 		log.Fatal(err)
 	}
@@ -591,3 +591,68 @@ func (ctrl *MetricsController) Run(ctx context.Context, r controller.Runtime, _
 		r.ResetRestartBackoff()
 	}
 }
+
+// StuckController gets stuck in the first run, and creates IntResource in the following runs.
+type StuckController struct {
+	TargetNamespace resource.Namespace
+	Timeout         controller.TimeoutSettings
+
+	runs int
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *StuckController) Name() string {
+	return "StuckController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *StuckController) Inputs() []controller.Input {
+	return nil
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *StuckController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: IntResourceType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+// ReconcileTimeout implements controller.TimeoutController interface.
+func (ctrl *StuckController) ReconcileTimeout() controller.TimeoutSettings {
+	return ctrl.Timeout
+}
+
+// Run implements controller.Controller interface.
+func (ctrl *StuckController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	ctrl.runs++
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		if ctrl.runs == 1 {
+			// get stuck with the next reconcile event pending
+			r.QueueReconcile()
+
+			<-ctx.Done()
+
+			return nil
+		}
+
+		if err := safe.WriterModify(ctx, r, NewIntResource(ctrl.TargetNamespace, "stuck", 0), func(r *IntResource) error {
+			r.SetValue(ctrl.runs)
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error updating value: %w", err)
+		}
+
+		r.ResetRestartBackoff()
+	}
+}
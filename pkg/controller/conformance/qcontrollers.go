@@ -24,6 +24,8 @@ import (
 type QIntToStrController struct {
 	SourceNamespace resource.Namespace
 	TargetNamespace resource.Namespace
+	PriorityClass   string
+	RateLimits      controller.RateLimits
 	ShutdownCalled  bool
 }
 
@@ -55,7 +57,9 @@ func (ctrl *QIntToStrController) Settings() controller.QSettings {
 				Kind: controller.OutputExclusive,
 			},
 		},
-		Concurrency: optional.Some(uint(4)),
+		Concurrency:   optional.Some(uint(4)),
+		PriorityClass: ctrl.PriorityClass,
+		RateLimits:    ctrl.RateLimits,
 		RunHook: func(ctx context.Context, _ *zap.Logger, r controller.QRuntime) error {
 			interval := time.NewTicker(time.Second)
 
@@ -157,6 +161,7 @@ func (ctrl *QIntToStrController) MapInput(_ context.Context, _ *zap.Logger, _ co
 type QFailingController struct {
 	SourceNamespace resource.Namespace
 	TargetNamespace resource.Namespace
+	Backoff         controller.BackoffPolicy
 }
 
 // Name implements controller.QController interface.
@@ -186,6 +191,7 @@ func (ctrl *QFailingController) Settings() controller.QSettings {
 			},
 		},
 		Concurrency: optional.Some(uint(4)),
+		Backoff:     ctrl.Backoff,
 	}
 }
 
@@ -209,6 +215,8 @@ func (ctrl *QFailingController) Reconcile(ctx context.Context, _ *zap.Logger, r
 		return controller.NewRequeueError(nil, 100*time.Millisecond)
 	case "requeue_with_error":
 		return controller.NewRequeueError(fmt.Errorf("requeue with error"), 100*time.Millisecond)
+	case "permanent":
+		return controller.NewPermanentErrorf("failing permanently as requested")
 	}
 
 	return safe.WriterModify(ctx, r, NewStrResource(ctrl.TargetNamespace, src.Metadata().ID(), src.Value()), func(r *StrResource) error {
@@ -225,8 +233,9 @@ func (ctrl *QFailingController) MapInput(context.Context, *zap.Logger, controlle
 
 // QIntToStrSleepingController converts IntResource to StrResource as a QController sleeping source seconds.
 type QIntToStrSleepingController struct {
-	SourceNamespace resource.Namespace
-	TargetNamespace resource.Namespace
+	SourceNamespace  resource.Namespace
+	TargetNamespace  resource.Namespace
+	ReconcileTimeout controller.TimeoutSettings
 }
 
 // Name implements controller.QController interface.
@@ -255,7 +264,8 @@ func (ctrl *QIntToStrSleepingController) Settings() controller.QSettings {
 				Kind: controller.OutputExclusive,
 			},
 		},
-		Concurrency: optional.Some(uint(1)), // use a single thread (important!)
+		Concurrency:      optional.Some(uint(1)), // use a single thread (important!)
+		ReconcileTimeout: ctrl.ReconcileTimeout,
 	}
 }
 
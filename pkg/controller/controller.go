@@ -7,8 +7,11 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 // Controller interface should be implemented by Controllers.
@@ -20,6 +23,68 @@ type Controller interface {
 	Run(context.Context, Runtime, *zap.Logger) error
 }
 
+// TimeoutController is an optional interface which Controllers might implement to set a deadline for the controller loop iterations.
+//
+// As the runtime doesn't know when the Controller iteration starts or ends, the iteration is considered to be stuck
+// if the Controller doesn't pick up the next reconcile event within the timeout.
+// If the timeout is configured to cancel, the Controller is restarted with backoff.
+type TimeoutController interface {
+	Controller
+
+	ReconcileTimeout() TimeoutSettings
+}
+
+// TimeoutSettings configures the reconcile deadline for the controller.
+//
+// Once the deadline is exceeded, the controller runtime watchdog logs goroutine stacks and increments the timeout metric.
+type TimeoutSettings struct {
+	// Timeout is the deadline for a single reconcile, zero value disables the watchdog.
+	Timeout time.Duration
+	// Cancel the reconcile context once the timeout is exceeded.
+	//
+	// The canceled reconcile is retried with backoff, the context cause is ErrReconcileTimeout.
+	Cancel bool
+}
+
+// ErrReconcileTimeout is the cause of the reconcile context cancellation when the reconcile exceeds the timeout.
+var ErrReconcileTimeout = errors.New("reconcile timeout exceeded")
+
+// RateLimitedController is an optional interface which Controllers might implement to configure the rate limits.
+type RateLimitedController interface {
+	Controller
+
+	RateLimits() RateLimits
+}
+
+// RateLimits configures rate limiting of the controller.
+//
+// Time spent waiting for the rate limiters is reported by the throttling metrics.
+type RateLimits struct {
+	// Writes limits the rate of the controller writes, overriding the runtime-wide limit (see options.WithChangeRateLimit).
+	Writes RateLimit
+	// UncachedReads limits the rate of the controller reads which are not served from the cache.
+	UncachedReads RateLimit
+	// ItemReconciles limits the rate of reconciles for each QController item, ignored for Controllers.
+	//
+	// Throttled item is requeued to be processed once the limit allows it.
+	ItemReconciles RateLimit
+}
+
+// RateLimit configures a token bucket rate limiter.
+//
+// Zero value means no limit.
+type RateLimit struct {
+	// Limit is the rate of events per second.
+	Limit rate.Limit
+	// Burst is the maximum number of events allowed at once, values less than one are treated as one.
+	Burst int
+}
+
+// IsZero returns true if the limit is not configured.
+func (limit RateLimit) IsZero() bool {
+	return limit.Limit == 0
+}
+
 // Engine is the entrypoint into Controller Runtime.
 type Engine interface {
 	// RegisterController registers new controller.
@@ -27,6 +27,8 @@ const (
 )
 
 // DependencyEdge represents relationship between controller and resource(s).
+//
+// For the inputs across namespaces, ResourceNamespace is the namespace pattern.
 type DependencyEdge struct {
 	ControllerName string
 
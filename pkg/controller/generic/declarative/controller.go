@@ -0,0 +1,328 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// CleanupRetryInterval is the interval between the attempts to stop the transform controller
+// which outputs have pending finalizers.
+const CleanupRetryInterval = time.Second
+
+// ControllerRuntime registers and unregisters the controllers built from the TransformDefinitions.
+//
+// ControllerRuntime is implemented by the controller runtime.
+type ControllerRuntime interface {
+	RegisterQController(controller.QController) error
+	UnregisterController(name string) error
+	PauseController(ctx context.Context, name string) error
+}
+
+// Controller runs a transform controller for each TransformDefinition.
+//
+// Controller puts a finalizer on the TransformDefinitions, so that the transform controller is cleaned up
+// before the definition is destroyed. When a TransformDefinition is torn down, or its input type, output type
+// or ID template change, the transform controller is paused, its outputs are torn down and destroyed,
+// and its finalizers are removed from the inputs before the controller is unregistered.
+// Otherwise the transform controller is registered again keeping the outputs, outputs of the inputs which
+// no longer match the label selector are destroyed by the transform controller itself.
+//
+// Definitions which can't be instantiated (e.g. the pair of types is not in the Registry) are logged and skipped.
+type Controller struct {
+	registry *Registry
+	runtime  ControllerRuntime
+	state    state.State
+
+	// running definitions by the ID, preserved across the restarts of the controller
+	running map[resource.ID]TransformDefinitionSpec
+	// definitions which are being stopped, preserved across the restarts of the controller
+	stopping map[resource.ID]stoppingTransform
+}
+
+type stoppingTransform struct {
+	spec    TransformDefinitionSpec
+	cleanup bool
+}
+
+// NewController initializes a Controller.
+//
+// State is used to clean up the resources of the transform controllers which are owned by them.
+func NewController(registry *Registry, runtime ControllerRuntime, st state.State) *Controller {
+	return &Controller{
+		registry: registry,
+		runtime:  runtime,
+		state:    st,
+		running:  map[resource.ID]TransformDefinitionSpec{},
+		stopping: map[resource.ID]stoppingTransform{},
+	}
+}
+
+// TransformControllerName returns the name of the transform controller for the TransformDefinition ID.
+func TransformControllerName(id resource.ID) string {
+	return fmt.Sprintf("DeclarativeTransform(%s)", id)
+}
+
+// Name implements controller.Controller interface.
+func (ctrl *Controller) Name() string {
+	return "DeclarativeTransformController"
+}
+
+// Inputs implements controller.Controller interface.
+func (ctrl *Controller) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: meta.NamespaceName,
+			Type:      TransformDefinitionType,
+			Kind:      controller.InputStrong,
+		},
+	}
+}
+
+// Outputs implements controller.Controller interface.
+func (ctrl *Controller) Outputs() []controller.Output {
+	return nil
+}
+
+// Run implements controller.Controller interface.
+//
+//nolint:gocyclo,cyclop
+func (ctrl *Controller) Run(ctx context.Context, r controller.Runtime, logger *zap.Logger) error {
+	var retryCh <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		case <-retryCh:
+		}
+
+		retryCh = nil
+
+		definitions, err := safe.ReaderListAll[*TransformDefinition](ctx, r)
+		if err != nil {
+			return fmt.Errorf("error listing transform definitions: %w", err)
+		}
+
+		current := map[resource.ID]TransformDefinitionSpec{}
+
+		for definition := range definitions.All() {
+			if definition.Metadata().Phase() != resource.PhaseRunning {
+				continue
+			}
+
+			current[definition.Metadata().ID()] = *definition.TypedSpec()
+
+			if !definition.Metadata().Finalizers().Has(ctrl.Name()) {
+				if err = r.AddFinalizer(ctx, definition.Metadata(), ctrl.Name()); err != nil {
+					return fmt.Errorf("error adding finalizer to transform definition: %w", err)
+				}
+			}
+		}
+
+		for id, running := range ctrl.running {
+			spec, ok := current[id]
+			if ok && reflect.DeepEqual(running, spec) {
+				continue
+			}
+
+			ctrl.stopping[id] = stoppingTransform{
+				spec:    running,
+				cleanup: !ok || outputsChanged(running, spec),
+			}
+
+			delete(ctrl.running, id)
+		}
+
+		// stop controllers first, so that the outputs they claimed can be claimed by the new controllers
+		//
+		// failure to stop one controller doesn't block the other definitions, it is retried later
+		for _, id := range slices.Sorted(maps.Keys(ctrl.stopping)) {
+			if err = ctrl.stop(ctx, id); err != nil {
+				if ctx.Err() != nil {
+					return nil //nolint:nilerr
+				}
+
+				logger.Warn("failed to stop transform controller, will retry", zap.String("definition", id), zap.Error(err))
+
+				retryCh = time.After(CleanupRetryInterval)
+
+				continue
+			}
+
+			logger.Info("stopped transform controller", zap.String("definition", id))
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(current)) {
+			if _, ok := ctrl.running[id]; ok {
+				continue
+			}
+
+			if _, ok := ctrl.stopping[id]; ok {
+				// previous controller for the definition is still being stopped
+				continue
+			}
+
+			if err = ctrl.start(id, current[id], logger); err != nil {
+				return err
+			}
+		}
+
+		// definitions being torn down are released once their controllers are stopped
+		for definition := range definitions.All() {
+			if _, ok := ctrl.stopping[definition.Metadata().ID()]; ok {
+				continue
+			}
+
+			if definition.Metadata().Phase() == resource.PhaseTearingDown && definition.Metadata().Finalizers().Has(ctrl.Name()) {
+				if err = r.RemoveFinalizer(ctx, definition.Metadata(), ctrl.Name()); err != nil {
+					return fmt.Errorf("error removing finalizer from transform definition: %w", err)
+				}
+			}
+		}
+
+		r.ResetRestartBackoff()
+	}
+}
+
+// outputsChanged returns true if the transform with the new spec would produce a different set of outputs.
+func outputsChanged(old, updated TransformDefinitionSpec) bool {
+	return old.InputType != updated.InputType ||
+		old.OutputType != updated.OutputType ||
+		old.IDTemplate != updated.IDTemplate
+}
+
+func (ctrl *Controller) start(id resource.ID, spec TransformDefinitionSpec, logger *zap.Logger) error {
+	logger = logger.With(zap.String("definition", id))
+
+	reg, ok := ctrl.registry.lookup(spec.InputType, spec.OutputType)
+	if !ok {
+		logger.Error("transform from input type to output type is not registered",
+			zap.String("input", spec.InputType), zap.String("output", spec.OutputType),
+		)
+
+		return nil
+	}
+
+	t, err := parseTransform(spec)
+	if err != nil {
+		logger.Error("invalid transform definition", zap.Error(err))
+
+		return nil
+	}
+
+	if err = ctrl.runtime.RegisterQController(reg.factory(TransformControllerName(id), t)); err != nil {
+		logger.Error("failed to register transform controller", zap.Error(err))
+
+		return nil
+	}
+
+	ctrl.running[id] = spec.DeepCopy()
+
+	logger.Info("started transform controller", zap.String("input", spec.InputType), zap.String("output", spec.OutputType))
+
+	return nil
+}
+
+func (ctrl *Controller) stop(ctx context.Context, id resource.ID) error {
+	stopping := ctrl.stopping[id]
+	name := TransformControllerName(id)
+
+	if stopping.cleanup {
+		// pause the controller, so that it doesn't recreate the outputs while they are being cleaned up
+		if err := ctrl.runtime.PauseController(ctx, name); err != nil {
+			return fmt.Errorf("error pausing transform controller %q: %w", id, err)
+		}
+
+		if err := ctrl.cleanup(ctx, name, stopping.spec); err != nil {
+			return fmt.Errorf("error cleaning up transform controller %q: %w", id, err)
+		}
+	}
+
+	if err := ctrl.runtime.UnregisterController(name); err != nil {
+		return fmt.Errorf("error unregistering transform controller %q: %w", id, err)
+	}
+
+	delete(ctrl.stopping, id)
+
+	return nil
+}
+
+// cleanup destroys the outputs owned by the transform controller and removes its finalizers from the inputs.
+//
+// Inputs are released only once all outputs are destroyed, if some outputs have pending finalizers, an error is returned.
+func (ctrl *Controller) cleanup(ctx context.Context, name string, spec TransformDefinitionSpec) error {
+	reg, ok := ctrl.registry.lookup(spec.InputType, spec.OutputType)
+	if !ok {
+		return fmt.Errorf("transform from %q to %q is not registered", spec.InputType, spec.OutputType)
+	}
+
+	outputs, err := ctrl.state.List(ctx, resource.NewMetadata(reg.outputNamespace, spec.OutputType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing outputs: %w", err)
+	}
+
+	pending := 0
+
+	for _, output := range outputs.Items {
+		if output.Metadata().Owner() != name {
+			continue
+		}
+
+		ready, err := ctrl.state.Teardown(ctx, output.Metadata(), state.WithTeardownOwner(name))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return fmt.Errorf("error tearing down output %s: %w", output.Metadata(), err)
+		}
+
+		if !ready {
+			pending++
+
+			continue
+		}
+
+		if err = ctrl.state.Destroy(ctx, output.Metadata(), state.WithDestroyOwner(name)); err != nil && !state.IsNotFoundError(err) {
+			return fmt.Errorf("error destroying output %s: %w", output.Metadata(), err)
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("%d outputs have pending finalizers", pending)
+	}
+
+	inputs, err := ctrl.state.List(ctx, resource.NewMetadata(reg.inputNamespace, spec.InputType, "", resource.VersionUndefined))
+	if err != nil {
+		return fmt.Errorf("error listing inputs: %w", err)
+	}
+
+	for _, input := range inputs.Items {
+		if !input.Metadata().Finalizers().Has(name) {
+			continue
+		}
+
+		if err = ctrl.state.RemoveFinalizer(ctx, input.Metadata(), name); err != nil && !state.IsNotFoundError(err) {
+			return fmt.Errorf("error removing finalizer from input %s: %w", input.Metadata(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller/generic/declarative"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/rtestutils"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestTransformDefinition(t *testing.T) {
+	setup(t, func(ctx context.Context, st state.State, rt *runtime.Runtime) {
+		registry := declarative.NewRegistry()
+		declarative.RegisterTransform[*A, *B](registry)
+
+		require.NoError(t, rt.RegisterController(declarative.NewController(registry, rt, st)))
+
+		worker := NewA("1", ASpec{Hostname: "node-1"})
+		worker.TypedSpec().Address.IP = "10.0.0.1"
+		worker.Metadata().Labels().Set("role", "worker")
+
+		controlPlane := NewA("2", ASpec{Hostname: "node-2"})
+		controlPlane.Metadata().Labels().Set("role", "controlplane")
+
+		require.NoError(t, st.Create(ctx, worker))
+		require.NoError(t, st.Create(ctx, controlPlane))
+
+		// definitions which can't be instantiated are skipped
+		require.NoError(t, st.Create(ctx, declarative.NewTransformDefinition(meta.NamespaceName, "unknown", declarative.TransformDefinitionSpec{
+			InputType:  AType,
+			OutputType: "Unknown.test.cosi.dev",
+		})))
+
+		definition := declarative.NewTransformDefinition(meta.NamespaceName, "machines", declarative.TransformDefinitionSpec{
+			InputType:     AType,
+			OutputType:    BType,
+			IDTemplate:    "b-{id}",
+			LabelSelector: map[string]string{"role": "worker"},
+			Fields: []declarative.FieldMapping{
+				{From: "spec.hostname", To: "spec.name"},
+				{From: "spec.address.ip", To: "spec.network.address"},
+				{From: "metadata.id", To: "spec.source"},
+				{From: "metadata.labels", To: "metadata.labels"},
+			},
+		})
+
+		require.NoError(t, st.Create(ctx, definition))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"b-1"}, func(r *B, assert *assert.Assertions) {
+			assert.Equal("node-1", r.TypedSpec().Name)
+			assert.Equal("10.0.0.1", r.TypedSpec().Network.Address)
+			assert.Equal("1", r.TypedSpec().Source)
+
+			role, _ := r.Metadata().Labels().Get("role")
+			assert.Equal("worker", role)
+		})
+
+		rtestutils.AssertNoResource[*B](ctx, t, st, "b-2")
+
+		// the definition is reloaded on change
+		_, err := safe.StateUpdateWithConflicts(ctx, st, definition.Metadata(), func(r *declarative.TransformDefinition) error {
+			r.TypedSpec().LabelSelector = nil
+			r.TypedSpec().Fields = []declarative.FieldMapping{
+				{From: "metadata.labels.role", To: "spec.source"},
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"b-1", "b-2"}, func(r *B, assert *assert.Assertions) {
+			switch r.Metadata().ID() {
+			case "b-1":
+				assert.Equal("worker", r.TypedSpec().Source)
+			case "b-2":
+				assert.Equal("controlplane", r.TypedSpec().Source)
+				assert.Empty(r.TypedSpec().Name)
+			}
+		})
+
+		// the controller is unregistered when the definition is removed, so the output type can be claimed again
+		teardownAndDestroy(ctx, t, st, definition.Metadata())
+
+		// outputs of the removed definition are destroyed
+		rtestutils.AssertNoResource[*B](ctx, t, st, "b-1")
+		rtestutils.AssertNoResource[*B](ctx, t, st, "b-2")
+
+		require.NoError(t, st.Create(ctx, declarative.NewTransformDefinition(meta.NamespaceName, "machines-v2", declarative.TransformDefinitionSpec{
+			InputType:  AType,
+			OutputType: BType,
+			IDTemplate: "{id}-v2",
+			Fields: []declarative.FieldMapping{
+				{From: "spec.hostname", To: "spec.name"},
+			},
+		})))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"1-v2", "2-v2"}, func(r *B, assert *assert.Assertions) {
+			assert.NotEmpty(r.TypedSpec().Name)
+		})
+
+		require.NoError(t, st.Create(ctx, NewA("3", ASpec{Hostname: "node-3"})))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"3-v2"}, func(r *B, assert *assert.Assertions) {
+			assert.Equal("node-3", r.TypedSpec().Name)
+		})
+
+		rtestutils.AssertNoResource[*B](ctx, t, st, "b-3")
+	})
+}
+
+func TestTransformDefinitionCleanup(t *testing.T) {
+	setup(t, func(ctx context.Context, st state.State, rt *runtime.Runtime) {
+		registry := declarative.NewRegistry()
+		declarative.RegisterTransform[*A, *B](registry)
+
+		require.NoError(t, rt.RegisterController(declarative.NewController(registry, rt, st)))
+
+		for _, id := range []resource.ID{"1", "2"} {
+			require.NoError(t, st.Create(ctx, NewA(id, ASpec{Hostname: "node-" + id})))
+		}
+
+		definition := declarative.NewTransformDefinition(meta.NamespaceName, "machines", declarative.TransformDefinitionSpec{
+			InputType:  AType,
+			OutputType: BType,
+			IDTemplate: "b-{id}",
+			Fields: []declarative.FieldMapping{
+				{From: "spec.hostname", To: "spec.name"},
+			},
+		})
+
+		require.NoError(t, st.Create(ctx, definition))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"b-1", "b-2"}, func(*B, *assert.Assertions) {})
+
+		// changing the ID template replaces the outputs
+		_, err := safe.StateUpdateWithConflicts(ctx, st, definition.Metadata(), func(r *declarative.TransformDefinition) error {
+			r.TypedSpec().IDTemplate = "{id}-b"
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"1-b", "2-b"}, func(r *B, assert *assert.Assertions) {
+			assert.NotEmpty(r.TypedSpec().Name)
+		})
+
+		rtestutils.AssertNoResource[*B](ctx, t, st, "b-1")
+		rtestutils.AssertNoResource[*B](ctx, t, st, "b-2")
+
+		// once the definition is removed, the inputs are released and the outputs are destroyed
+		teardownAndDestroy(ctx, t, st, definition.Metadata())
+
+		rtestutils.AssertNoResource[*B](ctx, t, st, "1-b")
+		rtestutils.AssertNoResource[*B](ctx, t, st, "2-b")
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"1", "2"}, func(r *A, assert *assert.Assertions) {
+			assert.True(r.Metadata().Finalizers().Empty())
+		})
+
+		require.NoError(t, st.TeardownAndDestroy(ctx, NewA("1", ASpec{}).Metadata()))
+
+		rtestutils.AssertNoResource[*A](ctx, t, st, "1")
+	})
+}
+
+func TestTransformDefinitionRelabel(t *testing.T) {
+	setup(t, func(ctx context.Context, st state.State, rt *runtime.Runtime) {
+		registry := declarative.NewRegistry()
+		declarative.RegisterTransform[*A, *B](registry)
+
+		require.NoError(t, rt.RegisterController(declarative.NewController(registry, rt, st)))
+
+		worker := NewA("1", ASpec{Hostname: "node-1"})
+		worker.Metadata().Labels().Set("role", "worker")
+
+		require.NoError(t, st.Create(ctx, worker))
+
+		require.NoError(t, st.Create(ctx, declarative.NewTransformDefinition(meta.NamespaceName, "workers", declarative.TransformDefinitionSpec{
+			InputType:     AType,
+			OutputType:    BType,
+			LabelSelector: map[string]string{"role": "worker"},
+			Fields: []declarative.FieldMapping{
+				{From: "spec.hostname", To: "spec.name"},
+			},
+		})))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"1"}, func(r *B, assert *assert.Assertions) {
+			assert.Equal("node-1", r.TypedSpec().Name)
+		})
+
+		// input no longer matches the label selector, so its output is destroyed
+		_, err := safe.StateUpdateWithConflicts(ctx, st, worker.Metadata(), func(r *A) error {
+			r.Metadata().Labels().Set("role", "controlplane")
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		rtestutils.AssertNoResource[*B](ctx, t, st, "1")
+
+		// input which doesn't match is still released on teardown
+		teardownAndDestroy(ctx, t, st, worker.Metadata())
+
+		rtestutils.AssertNoResource[*A](ctx, t, st, "1")
+		rtestutils.AssertNoResource[*B](ctx, t, st, "1")
+	})
+}
+
+func TestTransformDefinitionPendingCleanup(t *testing.T) {
+	setup(t, func(ctx context.Context, st state.State, rt *runtime.Runtime) {
+		registry := declarative.NewRegistry()
+		declarative.RegisterTransform[*A, *B](registry)
+		declarative.RegisterTransform[*A, *C](registry)
+
+		require.NoError(t, rt.RegisterController(declarative.NewController(registry, rt, st)))
+
+		require.NoError(t, st.Create(ctx, NewA("1", ASpec{Hostname: "node-1"})))
+
+		fields := []declarative.FieldMapping{
+			{From: "spec.hostname", To: "spec.name"},
+		}
+
+		toB := declarative.NewTransformDefinition(meta.NamespaceName, "to-b", declarative.TransformDefinitionSpec{
+			InputType:  AType,
+			OutputType: BType,
+			Fields:     fields,
+		})
+
+		require.NoError(t, st.Create(ctx, toB))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"1"}, func(*B, *assert.Assertions) {})
+
+		// output can't be destroyed until the finalizer is removed
+		output := resource.NewMetadata(BNamespaceName, BType, "1", resource.VersionUndefined)
+
+		require.NoError(t, st.AddFinalizer(ctx, output, "hold"))
+
+		_, err := st.Teardown(ctx, toB.Metadata())
+		require.NoError(t, err)
+
+		// other definitions are not blocked by the pending cleanup
+		require.NoError(t, st.Create(ctx, declarative.NewTransformDefinition(meta.NamespaceName, "to-c", declarative.TransformDefinitionSpec{
+			InputType:  AType,
+			OutputType: CType,
+			Fields:     fields,
+		})))
+
+		rtestutils.AssertResources(ctx, t, st, []resource.ID{"1"}, func(r *C, assert *assert.Assertions) {
+			assert.Equal("node-1", r.TypedSpec().Name)
+		})
+
+		r, err := st.Get(ctx, toB.Metadata())
+		require.NoError(t, err)
+		assert.True(t, r.Metadata().Finalizers().Has("DeclarativeTransformController"))
+
+		// once the output is released, the cleanup is retried
+		require.NoError(t, st.RemoveFinalizer(ctx, output, "hold"))
+
+		_, err = st.WatchFor(ctx, toB.Metadata(), state.WithFinalizerEmpty())
+		require.NoError(t, err)
+
+		require.NoError(t, st.Destroy(ctx, toB.Metadata()))
+
+		rtestutils.AssertNoResource[*B](ctx, t, st, "1")
+	})
+}
+
+func teardownAndDestroy(ctx context.Context, t *testing.T, st state.State, ptr resource.Pointer) {
+	t.Helper()
+
+	_, err := st.Teardown(ctx, ptr)
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, ptr, state.WithFinalizerEmpty())
+	require.NoError(t, err)
+
+	require.NoError(t, st.Destroy(ctx, ptr))
+}
+
+func setup(t *testing.T, f func(ctx context.Context, st state.State, rt *runtime.Runtime)) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	ctx, errCh := future.GoContext(ctx, rt.Run)
+
+	f(ctx, st, rt)
+
+	cancel()
+
+	if err = <-errCh; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
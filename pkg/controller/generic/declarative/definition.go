@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/siderolabs/gen/ensure"
+	"github.com/siderolabs/gen/xslices"
+
+	"github.com/cosi-project/runtime/api/transform_definition"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// TransformDefinitionType is the type of TransformDefinition.
+const TransformDefinitionType = resource.Type("TransformDefinitions.meta.cosi.dev")
+
+// TransformDefinition declares a transform controller, resource ID is the name of the controller.
+type TransformDefinition = typed.Resource[TransformDefinitionSpec, TransformDefinitionExtension]
+
+// NewTransformDefinition initializes a TransformDefinition resource.
+func NewTransformDefinition(ns resource.Namespace, id resource.ID, spec TransformDefinitionSpec) *TransformDefinition {
+	return typed.NewResource[TransformDefinitionSpec, TransformDefinitionExtension](
+		resource.NewMetadata(ns, TransformDefinitionType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// TransformDefinitionExtension provides auxiliary methods for TransformDefinition.
+type TransformDefinitionExtension struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (TransformDefinitionExtension) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             TransformDefinitionType,
+		DefaultNamespace: meta.NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Input",
+				JSONPath: "{.inputType}",
+			},
+			{
+				Name:     "Output",
+				JSONPath: "{.outputType}",
+			},
+		},
+	}
+}
+
+// TransformDefinitionSpec provides TransformDefinition definition.
+//
+// Example:
+//
+//	inputType: Machines.example.org
+//	outputType: MachineLabels.example.org
+//	idTemplate: "machine-{id}"
+//	labelSelector:
+//	  role: worker
+//	fields:
+//	  - from: metadata.labels
+//	    to: metadata.labels
+//	  - from: spec.hostname
+//	    to: spec.name
+//
+//nolint:recvcheck
+type TransformDefinitionSpec struct {
+	// LabelSelector (optional) is the set of labels the input resources should have to be transformed.
+	LabelSelector map[string]string `yaml:"labelSelector,omitempty"`
+	// InputType is the type of the input resources.
+	InputType resource.Type `yaml:"inputType"`
+	// OutputType is the type of the output resources.
+	OutputType resource.Type `yaml:"outputType"`
+	// IDTemplate (optional) is the template of the output ID, {id} is replaced with the input ID.
+	//
+	// The template should contain {id} exactly once, defaults to "{id}".
+	IDTemplate string `yaml:"idTemplate,omitempty"`
+	// Fields (optional) maps the fields of the input to the fields of the output (see FieldMapping).
+	Fields []FieldMapping `yaml:"fields,omitempty"`
+}
+
+// FieldMapping copies a field of the input resource to a field of the output resource.
+//
+// Paths are dot-separated, the first element is either "metadata" or "spec":
+//   - metadata.id, metadata.namespace, metadata.phase, metadata.version can be read from the input
+//   - metadata.labels and metadata.annotations (or a single key, e.g. metadata.labels.app) can be read and written
+//   - spec.path.to.field refers to the fields of the YAML representation of the spec.
+type FieldMapping struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// DeepCopy generates a deep copy of TransformDefinitionSpec.
+func (spec TransformDefinitionSpec) DeepCopy() TransformDefinitionSpec {
+	spec.LabelSelector = maps.Clone(spec.LabelSelector)
+	spec.Fields = slices.Clone(spec.Fields)
+
+	return spec
+}
+
+// MarshalProto implements ProtoMarshaler.
+func (spec TransformDefinitionSpec) MarshalProto() ([]byte, error) {
+	protoSpec := transform_definition.TransformDefinitionSpec{
+		InputType:     spec.InputType,
+		OutputType:    spec.OutputType,
+		IdTemplate:    spec.IDTemplate,
+		LabelSelector: spec.LabelSelector,
+		Fields: xslices.Map(spec.Fields, func(field FieldMapping) *transform_definition.TransformFieldMapping {
+			return &transform_definition.TransformFieldMapping{
+				From: field.From,
+				To:   field.To,
+			}
+		}),
+	}
+
+	return protobuf.ProtoMarshal(&protoSpec)
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (spec *TransformDefinitionSpec) UnmarshalProto(protoBytes []byte) error {
+	protoSpec := transform_definition.TransformDefinitionSpec{}
+
+	if err := protobuf.ProtoUnmarshal(protoBytes, &protoSpec); err != nil {
+		return err
+	}
+
+	*spec = TransformDefinitionSpec{
+		InputType:     protoSpec.InputType,
+		OutputType:    protoSpec.OutputType,
+		IDTemplate:    protoSpec.IdTemplate,
+		LabelSelector: protoSpec.LabelSelector,
+		Fields: xslices.Map(protoSpec.Fields, func(field *transform_definition.TransformFieldMapping) FieldMapping {
+			return FieldMapping{
+				From: field.From,
+				To:   field.To,
+			}
+		}),
+	}
+
+	return nil
+}
+
+func init() {
+	ensure.NoError(protobuf.RegisterResource(TransformDefinitionType, &TransformDefinition{}))
+}
@@ -0,0 +1,286 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative
+
+import (
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+const (
+	rootMetadata = "metadata"
+	rootSpec     = "spec"
+
+	fieldLabels      = "labels"
+	fieldAnnotations = "annotations"
+)
+
+// keyValues is implemented by resource labels and annotations.
+type keyValues interface {
+	Get(key string) (string, bool)
+	Set(key, value string)
+	Raw() map[string]string
+}
+
+func metadataKeyValues(md *resource.Metadata, field string) keyValues { //nolint:ireturn
+	if field == fieldLabels {
+		return md.Labels()
+	}
+
+	return md.Annotations()
+}
+
+// fieldPath is a parsed path of the field mapping.
+type fieldPath struct {
+	root  string
+	elems []string
+}
+
+func (path fieldPath) isKeyValue() bool {
+	return path.root == rootMetadata && (path.elems[0] == fieldLabels || path.elems[0] == fieldAnnotations)
+}
+
+func parseFieldPath(s string, write bool) (fieldPath, error) {
+	elems := strings.Split(s, ".")
+
+	for _, elem := range elems {
+		if elem == "" {
+			return fieldPath{}, fmt.Errorf("invalid field path %q", s)
+		}
+	}
+
+	path := fieldPath{
+		root:  elems[0],
+		elems: elems[1:],
+	}
+
+	switch path.root {
+	case rootSpec:
+		if write && len(path.elems) == 0 {
+			return fieldPath{}, fmt.Errorf("field path %q should refer to a field of the spec", s)
+		}
+
+		return path, nil
+	case rootMetadata:
+		if len(path.elems) == 0 || len(path.elems) > 2 {
+			return fieldPath{}, fmt.Errorf("invalid metadata field path %q", s)
+		}
+
+		if path.isKeyValue() {
+			return path, nil
+		}
+
+		if write {
+			return fieldPath{}, fmt.Errorf("metadata field %q can't be written", s)
+		}
+
+		switch path.elems[0] {
+		case "id", "namespace", "phase", "version":
+			if len(path.elems) == 1 {
+				return path, nil
+			}
+		}
+
+		return fieldPath{}, fmt.Errorf("unsupported metadata field path %q", s)
+	default:
+		return fieldPath{}, fmt.Errorf("field path %q should start with %q or %q", s, rootMetadata, rootSpec)
+	}
+}
+
+// fieldMapping is a parsed FieldMapping.
+type fieldMapping struct {
+	from fieldPath
+	to   fieldPath
+}
+
+func parseFieldMappings(fields []FieldMapping) ([]fieldMapping, error) {
+	mappings := make([]fieldMapping, 0, len(fields))
+
+	for _, field := range fields {
+		from, err := parseFieldPath(field.From, false)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parseFieldPath(field.To, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if to.isKeyValue() && len(to.elems) == 1 && !(from.isKeyValue() && len(from.elems) == 1) {
+			return nil, fmt.Errorf("field %q can only be mapped from labels or annotations", field.To)
+		}
+
+		mappings = append(mappings, fieldMapping{
+			from: from,
+			to:   to,
+		})
+	}
+
+	return mappings, nil
+}
+
+func decodeSpec(r resource.Resource) (any, error) {
+	out, err := yaml.Marshal(r.Spec())
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling spec of %s: %w", resource.String(r), err)
+	}
+
+	var spec any
+
+	if err = yaml.Unmarshal(out, &spec); err != nil {
+		return nil, fmt.Errorf("error unmarshaling spec of %s: %w", resource.String(r), err)
+	}
+
+	return spec, nil
+}
+
+// readField reads the field of the resource, returning false if the field is not set.
+func readField(r resource.Resource, spec func() (any, error), path fieldPath) (any, bool, error) {
+	if path.root == rootSpec {
+		value, err := spec()
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, elem := range path.elems {
+			m, ok := value.(map[string]any)
+			if !ok {
+				return nil, false, nil
+			}
+
+			if value, ok = m[elem]; !ok {
+				return nil, false, nil
+			}
+		}
+
+		return value, true, nil
+	}
+
+	md := r.Metadata()
+
+	switch path.elems[0] {
+	case "id":
+		return md.ID(), true, nil
+	case "namespace":
+		return md.Namespace(), true, nil
+	case "phase":
+		return md.Phase().String(), true, nil
+	case "version":
+		return md.Version().String(), true, nil
+	}
+
+	kv := metadataKeyValues(md, path.elems[0])
+
+	if len(path.elems) == 2 {
+		value, ok := kv.Get(path.elems[1])
+
+		return value, ok, nil
+	}
+
+	return kv.Raw(), true, nil
+}
+
+// writeField writes the field of the resource, spec fields are written to the decoded spec.
+func writeField(r resource.Resource, spec map[string]any, path fieldPath, value any) {
+	if path.root == rootSpec {
+		for _, elem := range path.elems[:len(path.elems)-1] {
+			next, ok := spec[elem].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				spec[elem] = next
+			}
+
+			spec = next
+		}
+
+		spec[path.elems[len(path.elems)-1]] = value
+
+		return
+	}
+
+	kv := metadataKeyValues(r.Metadata(), path.elems[0])
+
+	if len(path.elems) == 2 {
+		kv.Set(path.elems[1], fmt.Sprint(value))
+
+		return
+	}
+
+	// whole labels or annotations are merged
+	if values, ok := value.(map[string]string); ok {
+		for k, v := range values {
+			kv.Set(k, v)
+		}
+	}
+}
+
+// applyFields copies the fields of the input to the output.
+func applyFields(mappings []fieldMapping, in, out resource.Resource) error {
+	var (
+		inSpec     any
+		inDecoded  bool
+		outSpec    map[string]any
+		outChanged bool
+	)
+
+	decodeInSpec := func() (any, error) {
+		if inDecoded {
+			return inSpec, nil
+		}
+
+		var err error
+
+		inSpec, err = decodeSpec(in)
+		inDecoded = err == nil
+
+		return inSpec, err
+	}
+
+	for _, mapping := range mappings {
+		value, ok, err := readField(in, decodeInSpec, mapping.from)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if mapping.to.root == rootSpec && outSpec == nil {
+			decoded, err := decodeSpec(out)
+			if err != nil {
+				return err
+			}
+
+			if outSpec, ok = decoded.(map[string]any); !ok {
+				outSpec = map[string]any{}
+			}
+		}
+
+		writeField(out, outSpec, mapping.to, value)
+
+		outChanged = outChanged || mapping.to.root == rootSpec
+	}
+
+	if !outChanged {
+		return nil
+	}
+
+	encoded, err := yaml.Marshal(outSpec)
+	if err != nil {
+		return fmt.Errorf("error marshaling spec of %s: %w", resource.String(out), err)
+	}
+
+	if err = yaml.Unmarshal(encoded, out.Spec()); err != nil {
+		return fmt.Errorf("error unmarshaling spec of %s: %w", resource.String(out), err)
+	}
+
+	return nil
+}
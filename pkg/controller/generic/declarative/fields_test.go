@@ -0,0 +1,91 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldMappings(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name          string
+		fields        []FieldMapping
+		expectedError string
+	}{
+		{
+			name: "valid",
+			fields: []FieldMapping{
+				{From: "spec", To: "spec.input"},
+				{From: "spec.a.b", To: "spec.c"},
+				{From: "metadata.id", To: "metadata.labels.id"},
+				{From: "metadata.annotations", To: "metadata.labels"},
+			},
+		},
+		{
+			name:          "unknown root",
+			fields:        []FieldMapping{{From: "status.a", To: "spec.a"}},
+			expectedError: `field path "status.a" should start with "metadata" or "spec"`,
+		},
+		{
+			name:          "empty element",
+			fields:        []FieldMapping{{From: "spec..a", To: "spec.a"}},
+			expectedError: `invalid field path "spec..a"`,
+		},
+		{
+			name:          "whole spec",
+			fields:        []FieldMapping{{From: "spec.a", To: "spec"}},
+			expectedError: `field path "spec" should refer to a field of the spec`,
+		},
+		{
+			name:          "read-only metadata",
+			fields:        []FieldMapping{{From: "spec.a", To: "metadata.id"}},
+			expectedError: `metadata field "metadata.id" can't be written`,
+		},
+		{
+			name:          "unsupported metadata",
+			fields:        []FieldMapping{{From: "metadata.owner", To: "spec.a"}},
+			expectedError: `unsupported metadata field path "metadata.owner"`,
+		},
+		{
+			name:          "labels from spec",
+			fields:        []FieldMapping{{From: "spec.a", To: "metadata.labels"}},
+			expectedError: `field "metadata.labels" can only be mapped from labels or annotations`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseFieldMappings(test.fields)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	t.Parallel()
+
+	tr, err := parseTransform(TransformDefinitionSpec{IDTemplate: "pre-{id}-post"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "pre-foo-post", tr.outputID("foo"))
+	assert.Equal(t, "foo", tr.inputID("pre-foo-post"))
+
+	tr, err = parseTransform(TransformDefinitionSpec{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", tr.outputID("foo"))
+
+	_, err = parseTransform(TransformDefinitionSpec{IDTemplate: "{id}-{id}"})
+	require.EqualError(t, err, `ID template "{id}-{id}" should contain {id} exactly once`)
+}
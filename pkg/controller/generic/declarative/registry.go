@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/siderolabs/gen/xerrors"
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/generic"
+	"github.com/cosi-project/runtime/pkg/controller/generic/qtransform"
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+const idPlaceholder = "{id}"
+
+// typePair is the pair of the input and output types of the transform.
+type typePair struct {
+	input  resource.Type
+	output resource.Type
+}
+
+// transform is the parsed TransformDefinitionSpec.
+type transform struct {
+	labelSelector map[string]string
+	idPrefix      string
+	idSuffix      string
+	mappings      []fieldMapping
+}
+
+func parseTransform(spec TransformDefinitionSpec) (transform, error) {
+	template := spec.IDTemplate
+	if template == "" {
+		template = idPlaceholder
+	}
+
+	if strings.Count(template, idPlaceholder) != 1 {
+		return transform{}, fmt.Errorf("ID template %q should contain %s exactly once", template, idPlaceholder)
+	}
+
+	mappings, err := parseFieldMappings(spec.Fields)
+	if err != nil {
+		return transform{}, err
+	}
+
+	prefix, suffix, _ := strings.Cut(template, idPlaceholder)
+
+	return transform{
+		labelSelector: spec.LabelSelector,
+		idPrefix:      prefix,
+		idSuffix:      suffix,
+		mappings:      mappings,
+	}, nil
+}
+
+func (t transform) matches(md *resource.Metadata) bool {
+	for key, value := range t.labelSelector {
+		if v, ok := md.Labels().Get(key); !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t transform) outputID(id resource.ID) resource.ID {
+	return t.idPrefix + id + t.idSuffix
+}
+
+func (t transform) inputID(id resource.ID) resource.ID {
+	return strings.TrimSuffix(strings.TrimPrefix(id, t.idPrefix), t.idSuffix)
+}
+
+// transformFactory builds the controller of the transform.
+type transformFactory func(name string, t transform) controller.QController
+
+// registration is a pair of types registered with RegisterTransform.
+type registration struct {
+	factory         transformFactory
+	inputNamespace  resource.Namespace
+	outputNamespace resource.Namespace
+}
+
+// Registry holds the resource types which can be used in TransformDefinitions.
+//
+// As the controllers are built with generic qtransform.NewQController, the Go types of the input and output
+// resources should be known at compile time, so each pair of types is registered with RegisterTransform.
+type Registry struct {
+	factories map[typePair]registration
+	mu        sync.Mutex
+}
+
+// NewRegistry initializes an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: map[typePair]registration{},
+	}
+}
+
+// RegisterTransform allows TransformDefinitions to transform resources I into resources O.
+//
+// Input resources are read from the default namespace of I, and outputs are created in the default namespace of O.
+func RegisterTransform[I, O generic.ResourceWithRD](registry *Registry) {
+	var (
+		zeroInput  I
+		zeroOutput O
+	)
+
+	inputRD := zeroInput.ResourceDefinition()
+	outputRD := zeroOutput.ResourceDefinition()
+
+	factory := func(name string, t transform) controller.QController {
+		return qtransform.NewQController(
+			qtransform.Settings[I, O]{
+				Name: name,
+				// inputs which don't match the label selector are still mapped, so that their outputs are destroyed
+				// if the labels change, and the input finalizer is removed on teardown
+				MapMetadataFunc: func(in I) O {
+					return newResource[O](resource.NewMetadata(
+						outputRD.DefaultNamespace, outputRD.Type, t.outputID(in.Metadata().ID()), resource.VersionUndefined,
+					))
+				},
+				UnmapMetadataFunc: func(out O) I {
+					return newResource[I](resource.NewMetadata(
+						inputRD.DefaultNamespace, inputRD.Type, t.inputID(out.Metadata().ID()), resource.VersionUndefined,
+					))
+				},
+				TransformFunc: func(_ context.Context, _ controller.Reader, _ *zap.Logger, in I, out O) error {
+					if !t.matches(in.Metadata()) {
+						return xerrors.NewTaggedf[qtransform.DestroyOutputTag]("input doesn't match the label selector")
+					}
+
+					return applyFields(t.mappings, in, out)
+				},
+			},
+		)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.factories[typePair{input: inputRD.Type, output: outputRD.Type}] = registration{
+		factory:         factory,
+		inputNamespace:  inputRD.DefaultNamespace,
+		outputNamespace: outputRD.DefaultNamespace,
+	}
+}
+
+func (registry *Registry) lookup(input, output resource.Type) (registration, bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	reg, ok := registry.factories[typePair{input: input, output: output}]
+
+	return reg, ok
+}
+
+// newResource creates an empty resource of type T (which should be a pointer) with the given metadata.
+func newResource[T resource.Resource](md resource.Metadata) T { //nolint:ireturn
+	r := reflect.New(reflect.TypeFor[T]().Elem()).Interface().(T) //nolint:forcetypeassert,errcheck
+
+	*r.Metadata() = md
+
+	return r
+}
@@ -0,0 +1,116 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package declarative_test
+
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// ANamespaceName is the namespace of A resource.
+const ANamespaceName = resource.Namespace("ns-a")
+
+// AType is the type of A.
+const AType = resource.Type("A.test.cosi.dev")
+
+// A is a test resource.
+type A = typed.Resource[ASpec, AE]
+
+// NewA initializes a A resource.
+func NewA(id resource.ID, spec ASpec) *A {
+	return typed.NewResource[ASpec, AE](
+		resource.NewMetadata(ANamespaceName, AType, id, resource.VersionUndefined),
+		spec,
+	)
+}
+
+// AE provides auxiliary methods for A.
+type AE struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (AE) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             AType,
+		DefaultNamespace: ANamespaceName,
+	}
+}
+
+// ASpec provides A definition.
+type ASpec struct {
+	Hostname string `yaml:"hostname"`
+	Address  struct {
+		IP string `yaml:"ip"`
+	} `yaml:"address"`
+}
+
+// DeepCopy generates a deep copy of ASpec.
+func (a ASpec) DeepCopy() ASpec {
+	return a
+}
+
+// BNamespaceName is the namespace of B resource.
+const BNamespaceName = resource.Namespace("ns-b")
+
+// BType is the type of B.
+const BType = resource.Type("B.test.cosi.dev")
+
+// B is a test resource.
+type B = typed.Resource[BSpec, BE]
+
+// BE provides auxiliary methods for B.
+type BE struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (BE) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             BType,
+		DefaultNamespace: BNamespaceName,
+	}
+}
+
+// BSpec provides B definition.
+type BSpec struct {
+	Name    string `yaml:"name"`
+	Network struct {
+		Address string `yaml:"address"`
+	} `yaml:"network"`
+	Source string `yaml:"source"`
+}
+
+// DeepCopy generates a deep copy of BSpec.
+func (b BSpec) DeepCopy() BSpec {
+	return b
+}
+
+// CNamespaceName is the namespace of C resource.
+const CNamespaceName = resource.Namespace("ns-c")
+
+// CType is the type of C.
+const CType = resource.Type("C.test.cosi.dev")
+
+// C is a test resource.
+type C = typed.Resource[CSpec, CE]
+
+// CE provides auxiliary methods for C.
+type CE struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (CE) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             CType,
+		DefaultNamespace: CNamespaceName,
+	}
+}
+
+// CSpec provides C definition.
+type CSpec struct {
+	Name string `yaml:"name"`
+}
+
+// DeepCopy generates a deep copy of CSpec.
+func (c CSpec) DeepCopy() CSpec {
+	return c
+}
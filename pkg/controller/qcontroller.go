@@ -68,6 +68,67 @@ type QSettings struct {
 	Inputs       []Input
 	Outputs      []Output
 	Concurrency  optional.Optional[uint]
+	// ReconcileTimeout sets the deadline for each Reconcile and MapInput call.
+	ReconcileTimeout TimeoutSettings
+	// Backoff configures the backoff for the failed items.
+	Backoff BackoffPolicy
+	// RateLimits configures rate limiting of the controller writes, uncached reads and item reconciles.
+	RateLimits RateLimits
+	// PriorityClass assigns the controller to the priority class defined by the runtime.
+	//
+	// Priority classes order the controllers competing for the runtime-wide reconcile slots (see options.WithPriorityClass).
+	PriorityClass string
+}
+
+// PrioritizedPointer is a resource.Pointer returned by QController.MapInput to set the priority of the mapped item.
+type PrioritizedPointer struct {
+	resource.Pointer
+
+	Priority int
+}
+
+// WithPriority wraps the pointer to be queued with the specified priority when returned by QController.MapInput.
+func WithPriority(ptr resource.Pointer, priority int) PrioritizedPointer {
+	return PrioritizedPointer{
+		Pointer:  ptr,
+		Priority: priority,
+	}
+}
+
+// BackoffPolicy configures the exponential backoff of the controller failures.
+//
+// Zero values are replaced with the defaults.
+type BackoffPolicy struct {
+	// InitialInterval is the backoff interval after the first failure.
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff interval.
+	MaxInterval time.Duration
+	// Multiplier is the factor the interval grows by on each failure.
+	Multiplier float64
+	// Jitter randomizes the interval to be within [interval * (1 - Jitter), interval * (1 + Jitter)].
+	Jitter optional.Optional[float64]
+	// MaxAttempts is the number of consecutive failures after which the QController item is parked (see ParkedItem).
+	//
+	// Zero value means unlimited attempts, MaxAttempts is ignored for Controllers.
+	MaxAttempts int
+}
+
+// BackoffController is an optional interface which Controllers might implement to configure the restart backoff.
+type BackoffController interface {
+	Controller
+
+	BackoffPolicy() BackoffPolicy
+}
+
+// ParkedItem is a QController item which was parked (moved to the dead-letter set) after exhausting
+// the backoff attempts or failing with a PermanentError.
+//
+// Parked item is not retried until it is queued again (e.g. on input change), or retried explicitly.
+type ParkedItem struct {
+	ParkedAt  time.Time
+	Pointer   resource.Pointer
+	LastError string
+	Attempts  int
 }
 
 // ReducedResourceMetadata is the input type for MapInput.
@@ -131,3 +192,32 @@ func NewRequeueErrorf(interval time.Duration, format string, args ...any) *Reque
 func NewRequeueInterval(interval time.Duration) *RequeueError {
 	return NewRequeueError(nil, interval)
 }
+
+// PermanentError is returned by QController.Reconcile to indicate that the item can't be reconciled until the input changes.
+//
+// The item is parked without retries (see ParkedItem), and it is re-admitted once the input resource changes.
+type PermanentError struct {
+	err error
+}
+
+// Error implements error interface.
+func (err *PermanentError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap implements errors.Unwrap interface.
+func (err *PermanentError) Unwrap() error {
+	return err.err
+}
+
+// NewPermanentError creates a new PermanentError.
+func NewPermanentError(err error) *PermanentError {
+	return &PermanentError{
+		err: err,
+	}
+}
+
+// NewPermanentErrorf creates a new PermanentError with the formatted error message.
+func NewPermanentErrorf(format string, args ...any) *PermanentError {
+	return NewPermanentError(fmt.Errorf(format, args...))
+}
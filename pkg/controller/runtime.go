@@ -7,6 +7,7 @@ package controller
 import (
 	"cmp"
 	"context"
+	"path"
 
 	"github.com/siderolabs/gen/optional"
 
@@ -63,11 +64,36 @@ const (
 // some of its outputs to be ready to be destroyed. Controller will be notified
 // when the resource enters "teardown" phase and has no finalizers attached.
 // Resources are filtered to be owned by the controller.
+//
+// Priority is only used by QControllers: items queued for the input are processed
+// before the items with lower priority (e.g. primary inputs might be prioritized over mapped ones).
+// Reconcile items returned by MapInput inherit the priority of the mapped input, unless overridden with WithPriority.
+//
+// NamespacePattern (if set) makes the input cover the resources of the type across all namespaces matching
+// the pattern (syntax of path.Match), Namespace should be empty in that case, and ID can't be set.
+// Resources across the namespaces are listed with state.WithListNamespacePattern.
 type Input struct {
-	Namespace resource.Namespace
-	Type      resource.Type
-	ID        optional.Optional[resource.ID]
-	Kind      InputKind
+	Namespace        resource.Namespace
+	NamespacePattern string
+	Type             resource.Type
+	ID               optional.Optional[resource.ID]
+	Kind             InputKind
+	Priority         int
+}
+
+// Matches checks if the resources of the namespace and type are covered by the Input.
+func (a Input) Matches(namespace resource.Namespace, typ resource.Type) bool {
+	if a.Type != typ {
+		return false
+	}
+
+	if a.NamespacePattern != "" {
+		matched, _ := path.Match(a.NamespacePattern, namespace) //nolint:errcheck // invalid pattern doesn't match anything
+
+		return matched
+	}
+
+	return a.Namespace == namespace
 }
 
 // Compare defines Input sort order.
@@ -76,6 +102,10 @@ func (a Input) Compare(b Input) int {
 		return cmp.Compare(a.Namespace, b.Namespace)
 	}
 
+	if a.NamespacePattern != b.NamespacePattern {
+		return cmp.Compare(a.NamespacePattern, b.NamespacePattern)
+	}
+
 	if a.Type != b.Type {
 		return cmp.Compare(a.Type, b.Type)
 	}
@@ -89,7 +119,7 @@ func (a Input) Compare(b Input) int {
 
 // EqualKeys checks if two Inputs have equal (conflicting) keys.
 func (a Input) EqualKeys(b Input) bool {
-	return a.Namespace == b.Namespace && a.Type == b.Type && a.ID == b.ID
+	return a.Namespace == b.Namespace && a.NamespacePattern == b.NamespacePattern && a.Type == b.Type && a.ID == b.ID
 }
 
 // OutputKind for outputs.
@@ -122,6 +152,34 @@ type UncachedReader interface {
 	ListUncached(context.Context, resource.Kind, ...state.ListOption) (resource.List, error)
 }
 
+// IndexReader provides read-only access to the cached resources by the index key.
+//
+// Indexes are declared for the cached resources of the runtime (see options.WithIndex).
+// Runtimes implement IndexReader optionally, see safe.ReaderListByIndex.
+type IndexReader interface {
+	ListByIndex(ctx context.Context, kind resource.Kind, index, key string) (resource.List, error)
+}
+
+// ReadOnlyReader provides read-only access to the cached resources without copying them.
+//
+// Returned resources are shared with the cache and other controllers, so they must not be modified
+// (see options.WithReadOnlyChecks to detect modifications).
+// Runtimes implement ReadOnlyReader optionally, see safe.ReaderGetReadOnly and safe.ReaderListReadOnly.
+type ReadOnlyReader interface {
+	GetReadOnly(ctx context.Context, ptr resource.Pointer) (resource.Resource, error)
+	ListReadOnly(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error)
+}
+
+// SnapshotReader provides a consistent view of the cached resources across resource kinds.
+//
+// Reads of several kinds through the regular Reader might observe the kinds at different points in time,
+// while the Reader returned by Snapshot is pinned to a single point of the runtime watch stream.
+// Reads of the uncached resources are not pinned to the snapshot.
+// Runtimes implement SnapshotReader optionally, see safe.ReaderSnapshot.
+type SnapshotReader interface {
+	Snapshot(ctx context.Context) (Reader, error)
+}
+
 // Writer provides write access to the state.
 //
 // Only output objects can be written to by the controller.
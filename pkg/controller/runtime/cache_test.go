@@ -0,0 +1,145 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// intCountController counts IntResources, and whether they were read from the metadata-only cache.
+type intCountController struct{}
+
+func (ctrl *intCountController) Name() string {
+	return "IntCountController"
+}
+
+func (ctrl *intCountController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: "ints",
+			Type:      conformance.IntResourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+func (ctrl *intCountController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: conformance.StrResourceType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+func (ctrl *intCountController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		list, err := r.List(ctx, resource.NewMetadata("ints", conformance.IntResourceType, "", resource.VersionUndefined))
+		if err != nil {
+			return err
+		}
+
+		metadataOnly := len(list.Items) > 0 && !slices.ContainsFunc(list.Items, func(res resource.Resource) bool { return !resource.IsMetadataOnly(res) })
+
+		if err := safe.WriterModify(ctx, r, conformance.NewStrResource("strings", "count", ""), func(res *conformance.StrResource) error {
+			res.SetValue(fmt.Sprintf("%d:%t", len(list.Items), metadataOnly))
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestRuntimeCacheMetadataOnlyMaxSize(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	for i := range 4 {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", fmt.Sprint(i), i)))
+	}
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t),
+		options.WithCachedResource("ints", conformance.IntResourceType, options.WithMetadataOnly(), options.WithMaxSize(4)),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&intCountController{}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	waitCount := func(expected string) {
+		_, err := st.WatchFor(ctx, conformance.NewStrResource("strings", "count", "").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+			str, ok := r.(*conformance.StrResource)
+
+			return ok && str.Value() == expected, nil
+		}))
+		require.NoError(t, err)
+	}
+
+	waitCount("4:true")
+
+	// metadata-only resources can't be read as typed resources, or written back
+	cached := state.WrapCore(rt.CachedState())
+	kind := resource.NewMetadata("ints", conformance.IntResourceType, "", resource.VersionUndefined)
+
+	_, err = safe.ReaderList[*conformance.IntResource](ctx, cached, kind)
+	assert.ErrorContains(t, err, "metadata-only")
+
+	list, err := cached.List(ctx, kind)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 4)
+	assert.ErrorContains(t, cached.Update(ctx, list.Items[0]), "metadata-only")
+
+	// the cache exceeds the size cap, so the reads fall back to the state
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "4", 4)))
+
+	waitCount("5:false")
+
+	// the cache is recovered once the number of the resources drops to half of the cap
+	for _, id := range []string{"0", "1", "2"} {
+		require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("ints", id, 0).Metadata()))
+	}
+
+	waitCount("2:true")
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
@@ -0,0 +1,395 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package dryrun provides a copy-on-write state overlay which records the planned changes instead of applying them.
+package dryrun
+
+import (
+	"cmp"
+	"context"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+)
+
+// Operation is the kind of the planned change.
+type Operation int
+
+// Operation constants.
+const (
+	OperationCreate Operation = iota
+	OperationUpdate
+	OperationDestroy
+)
+
+func (op Operation) String() string {
+	return [...]string{"create", "update", "destroy"}[op]
+}
+
+// Change is a planned change of a single resource.
+//
+// Old is the resource in the underlying state (nil for OperationCreate), New is the resource in the overlay (nil for OperationDestroy).
+// Diff is only set for OperationUpdate.
+type Change struct {
+	Old       resource.Resource
+	New       resource.Resource
+	Diff      []resource.DiffEntry
+	Operation Operation
+}
+
+type overlayKey struct {
+	Namespace resource.Namespace
+	Type      resource.Type
+	ID        resource.ID
+}
+
+func keyOf(ptr resource.Pointer) overlayKey {
+	return overlayKey{Namespace: ptr.Namespace(), Type: ptr.Type(), ID: ptr.ID()}
+}
+
+// Overlay implements state.CoreState on top of the underlying state.
+//
+// Reads see the underlying state with the overlay changes applied, while writes are applied only to the overlay,
+// so that the underlying state is never modified. Watches are passed through to the underlying state,
+// so they don't observe the overlay changes.
+type Overlay struct {
+	underlying state.CoreState
+	logger     *zap.Logger
+
+	// resources in the overlay, nil value means the resource was destroyed
+	resources map[overlayKey]resource.Resource
+	// original resources in the underlying state, nil value means the resource didn't exist
+	originals map[overlayKey]resource.Resource
+
+	mu sync.Mutex
+}
+
+// NewOverlay creates a new Overlay on top of the underlying state.
+//
+// Each planned change is logged with the logger.
+func NewOverlay(underlying state.CoreState, logger *zap.Logger) *Overlay {
+	return &Overlay{
+		underlying: underlying,
+		logger:     logger,
+		resources:  map[overlayKey]resource.Resource{},
+		originals:  map[overlayKey]resource.Resource{},
+	}
+}
+
+// Check interfaces.
+var _ state.CoreState = (*Overlay)(nil)
+
+// Get implements state.CoreState.
+func (overlay *Overlay) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	overlay.mu.Lock()
+	res, ok := overlay.resources[keyOf(ptr)]
+	overlay.mu.Unlock()
+
+	if !ok {
+		return overlay.underlying.Get(ctx, ptr, opts...)
+	}
+
+	if res == nil {
+		return nil, inmem.ErrNotFound(ptr)
+	}
+
+	return res.DeepCopy(), nil
+}
+
+// List implements state.CoreState.
+func (overlay *Overlay) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	list, err := overlay.underlying.List(ctx, kind, opts...)
+	if err != nil {
+		return list, err
+	}
+
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	overlay.mu.Lock()
+	defer overlay.mu.Unlock()
+
+	// drop the resources changed in the overlay, and add them back from the overlay if they match
+	list.Items = slices.DeleteFunc(list.Items, func(res resource.Resource) bool {
+		_, changed := overlay.resources[keyOf(res.Metadata())]
+
+		return changed
+	})
+
+	for key, res := range overlay.resources {
+		if res == nil || key.Type != kind.Type() || !matchNamespace(options.NamespacePattern, kind.Namespace(), key.Namespace) {
+			continue
+		}
+
+		if !options.IDQuery.Matches(*res.Metadata()) || !options.LabelQueries.Matches(*res.Metadata().Labels()) || !resource.MatchesFilter(options.Filter, res) {
+			continue
+		}
+
+		list.Items = append(list.Items, res.DeepCopy())
+	}
+
+	slices.SortFunc(list.Items, func(a, b resource.Resource) int {
+		return cmp.Or(
+			cmp.Compare(a.Metadata().Namespace(), b.Metadata().Namespace()),
+			cmp.Compare(a.Metadata().ID(), b.Metadata().ID()),
+		)
+	})
+
+	return list, nil
+}
+
+// matchNamespace checks if the namespace is listed, pattern (if set) takes precedence over the namespace of the kind.
+func matchNamespace(pattern string, kindNamespace, namespace resource.Namespace) bool {
+	if pattern != "" {
+		matched, _ := path.Match(pattern, namespace) //nolint:errcheck // invalid pattern doesn't match anything
+
+		return matched
+	}
+
+	return kindNamespace == namespace
+}
+
+// Create implements state.CoreState.
+func (overlay *Overlay) Create(ctx context.Context, res resource.Resource, opts ...state.CreateOption) error {
+	var options state.CreateOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	overlay.mu.Lock()
+	defer overlay.mu.Unlock()
+
+	cur, err := overlay.current(ctx, res.Metadata())
+	if err != nil {
+		return err
+	}
+
+	if cur != nil {
+		return inmem.ErrAlreadyExists(res.Metadata())
+	}
+
+	resCopy := res.DeepCopy()
+
+	if err = resCopy.Metadata().SetOwner(options.Owner); err != nil {
+		return err
+	}
+
+	resCopy.Metadata().SetVersion(resource.VersionUndefined.Next())
+	resCopy.Metadata().SetCreated(time.Now())
+
+	overlay.record(resCopy.Metadata(), cur, resCopy)
+
+	*res.Metadata() = *resCopy.Metadata()
+
+	return nil
+}
+
+// Update implements state.CoreState.
+func (overlay *Overlay) Update(ctx context.Context, newResource resource.Resource, opts ...state.UpdateOption) error {
+	options := state.DefaultUpdateOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	overlay.mu.Lock()
+	defer overlay.mu.Unlock()
+
+	cur, err := overlay.current(ctx, newResource.Metadata())
+	if err != nil {
+		return err
+	}
+
+	if cur == nil {
+		return inmem.ErrNotFound(newResource.Metadata())
+	}
+
+	if cur.Metadata().Owner() != options.Owner {
+		return inmem.ErrOwnerConflict(cur.Metadata(), cur.Metadata().Owner())
+	}
+
+	if !cur.Metadata().Version().Equal(newResource.Metadata().Version()) {
+		return inmem.ErrVersionConflict(cur.Metadata(), newResource.Metadata().Version(), cur.Metadata().Version())
+	}
+
+	if options.ExpectedPhase != nil && cur.Metadata().Phase() != *options.ExpectedPhase {
+		return inmem.ErrPhaseConflict(cur.Metadata(), *options.ExpectedPhase)
+	}
+
+	resCopy := newResource.DeepCopy()
+
+	resCopy.Metadata().SetVersion(cur.Metadata().Version().Next())
+	resCopy.Metadata().SetUpdated(time.Now())
+	resCopy.Metadata().SetCreated(cur.Metadata().Created())
+
+	overlay.record(resCopy.Metadata(), cur, resCopy)
+
+	*newResource.Metadata() = *resCopy.Metadata()
+
+	return nil
+}
+
+// Destroy implements state.CoreState.
+func (overlay *Overlay) Destroy(ctx context.Context, ptr resource.Pointer, opts ...state.DestroyOption) error {
+	var options state.DestroyOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	overlay.mu.Lock()
+	defer overlay.mu.Unlock()
+
+	cur, err := overlay.current(ctx, ptr)
+	if err != nil {
+		return err
+	}
+
+	if cur == nil {
+		return inmem.ErrNotFound(ptr)
+	}
+
+	if cur.Metadata().Owner() != options.Owner {
+		return inmem.ErrOwnerConflict(cur.Metadata(), cur.Metadata().Owner())
+	}
+
+	if !cur.Metadata().Finalizers().Empty() {
+		return inmem.ErrPendingFinalizers(*cur.Metadata())
+	}
+
+	overlay.record(ptr, cur, nil)
+
+	return nil
+}
+
+// Watch implements state.CoreState.
+func (overlay *Overlay) Watch(ctx context.Context, ptr resource.Pointer, ch chan<- state.Event, opts ...state.WatchOption) error {
+	return overlay.underlying.Watch(ctx, ptr, ch, opts...)
+}
+
+// WatchKind implements state.CoreState.
+func (overlay *Overlay) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	return overlay.underlying.WatchKind(ctx, kind, ch, opts...)
+}
+
+// WatchKindAggregated implements state.CoreState.
+func (overlay *Overlay) WatchKindAggregated(ctx context.Context, kind resource.Kind, ch chan<- []state.Event, opts ...state.WatchKindOption) error {
+	return overlay.underlying.WatchKindAggregated(ctx, kind, ch, opts...)
+}
+
+// current returns the resource as seen through the overlay, or nil if it doesn't exist.
+//
+// current should be called with the lock held.
+func (overlay *Overlay) current(ctx context.Context, ptr resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	key := keyOf(ptr)
+
+	if res, ok := overlay.resources[key]; ok {
+		return res, nil
+	}
+
+	res, err := overlay.underlying.Get(ctx, ptr)
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// record the change in the overlay, it should be called with the lock held.
+//
+// The resource cur is the current resource as returned by current().
+func (overlay *Overlay) record(ptr resource.Pointer, cur, res resource.Resource) {
+	key := keyOf(ptr)
+
+	if _, ok := overlay.originals[key]; !ok {
+		// first change of the resource, so the current resource comes from the underlying state
+		overlay.originals[key] = cur
+	}
+
+	overlay.resources[key] = res
+
+	if overlay.logger == nil {
+		return
+	}
+
+	logger := overlay.logger.With(zap.String("namespace", ptr.Namespace()), zap.String("type", ptr.Type()), zap.String("id", ptr.ID()))
+
+	if res == nil {
+		logger.Info("dry-run: planned destroy")
+
+		return
+	}
+
+	logger.Info("dry-run: planned write", zap.Stringer("version", res.Metadata().Version()), zap.Stringer("phase", res.Metadata().Phase()))
+}
+
+// Plan returns the changes in the overlay compared to the underlying state (as seen on the first change of the resource).
+//
+// Changes are sorted by the resource namespace, type and ID.
+func (overlay *Overlay) Plan() ([]Change, error) {
+	overlay.mu.Lock()
+	defer overlay.mu.Unlock()
+
+	keys := make([]overlayKey, 0, len(overlay.resources))
+
+	for key := range overlay.resources {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b overlayKey) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+	})
+
+	changes := make([]Change, 0, len(keys))
+
+	for _, key := range keys {
+		oldRes, newRes := overlay.originals[key], overlay.resources[key]
+
+		switch {
+		case oldRes == nil && newRes == nil:
+			// created and destroyed
+			continue
+		case oldRes == nil:
+			changes = append(changes, Change{Operation: OperationCreate, New: newRes.DeepCopy()})
+		case newRes == nil:
+			changes = append(changes, Change{Operation: OperationDestroy, Old: oldRes.DeepCopy()})
+		default:
+			diff, err := resource.Diff(oldRes, newRes)
+			if err != nil {
+				return nil, err
+			}
+
+			changes = append(changes, Change{Operation: OperationUpdate, Old: oldRes.DeepCopy(), New: newRes.DeepCopy(), Diff: diff})
+		}
+	}
+
+	return changes, nil
+}
+
+// Reset drops all changes in the overlay.
+//
+// Overlay changes shadow the underlying state, so the overlay should be reset to observe the changes
+// of the underlying state made after the resources were changed in the overlay.
+func (overlay *Overlay) Reset() {
+	overlay.mu.Lock()
+	defer overlay.mu.Unlock()
+
+	clear(overlay.resources)
+	clear(overlay.originals)
+}
@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dryrun_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/dryrun"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/conformance"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/owned"
+)
+
+func TestOverlay(t *testing.T) {
+	ctx := t.Context()
+
+	underlying := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	existing := conformance.NewPathResource("ns", "existing")
+	require.NoError(t, underlying.Create(ctx, existing, state.WithCreateOwner("ctrl")))
+
+	toDestroy := conformance.NewPathResource("ns", "destroy")
+	require.NoError(t, underlying.Create(ctx, toDestroy, state.WithCreateOwner("ctrl")))
+
+	foreign := conformance.NewPathResource("ns", "foreign")
+	require.NoError(t, underlying.Create(ctx, foreign))
+
+	overlay := dryrun.NewOverlay(underlying, zaptest.NewLogger(t))
+	st := owned.New(state.WrapCore(overlay), "ctrl")
+
+	// create
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("ns", "new")))
+
+	_, err := st.Get(ctx, conformance.NewPathResource("ns", "new").Metadata())
+	require.NoError(t, err)
+
+	_, err = underlying.Get(ctx, conformance.NewPathResource("ns", "new").Metadata())
+	require.True(t, state.IsNotFoundError(err))
+
+	// update
+	_, err = st.ModifyWithResult(ctx, conformance.NewPathResource("ns", "existing"), func(r resource.Resource) error {
+		r.Metadata().Labels().Set("app", "test")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	r, err := underlying.Get(ctx, existing.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, existing.Metadata().Version(), r.Metadata().Version())
+	assert.True(t, r.Metadata().Labels().Empty())
+
+	// ownership is still enforced
+	require.True(t, state.IsOwnerConflictError(st.Destroy(ctx, foreign.Metadata())))
+
+	// destroy
+	require.NoError(t, st.Destroy(ctx, toDestroy.Metadata()))
+
+	_, err = st.Get(ctx, toDestroy.Metadata())
+	require.True(t, state.IsNotFoundError(err))
+
+	_, err = underlying.Get(ctx, toDestroy.Metadata())
+	require.NoError(t, err)
+
+	// create and destroy is not in the plan
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("ns", "temp")))
+	require.NoError(t, st.Destroy(ctx, conformance.NewPathResource("ns", "temp").Metadata()))
+
+	// list sees the overlay
+	list, err := st.List(ctx, resource.NewMetadata("ns", conformance.PathResourceType, "", resource.VersionUndefined))
+	require.NoError(t, err)
+
+	ids := make([]resource.ID, 0, len(list.Items))
+
+	for _, item := range list.Items {
+		ids = append(ids, item.Metadata().ID())
+	}
+
+	assert.Equal(t, []resource.ID{"existing", "foreign", "new"}, ids)
+	assert.Equal(t, "test", list.Items[0].Metadata().Labels().Raw()["app"])
+
+	list, err = st.List(ctx, resource.NewMetadata("ns", conformance.PathResourceType, "", resource.VersionUndefined), state.WithLabelQuery(resource.LabelExists("app")))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "existing", list.Items[0].Metadata().ID())
+
+	plan, err := overlay.Plan()
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+
+	assert.Equal(t, dryrun.OperationDestroy, plan[0].Operation)
+	assert.Equal(t, "destroy", plan[0].Old.Metadata().ID())
+	assert.Nil(t, plan[0].New)
+
+	assert.Equal(t, dryrun.OperationUpdate, plan[1].Operation)
+	assert.Equal(t, "existing", plan[1].New.Metadata().ID())
+	assert.Contains(t, plan[1].Diff, resource.DiffEntry{Path: "metadata.labels", New: map[string]any{"app": "test"}, Op: resource.DiffAdded})
+
+	assert.Equal(t, dryrun.OperationCreate, plan[2].Operation)
+	assert.Equal(t, "new", plan[2].New.Metadata().ID())
+	assert.Nil(t, plan[2].Old)
+
+	overlay.Reset()
+
+	plan, err = overlay.Plan()
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+}
@@ -0,0 +1,159 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func parityIndex(r resource.Resource) []string {
+	if r.(*conformance.IntResource).Value()%2 == 0 { //nolint:forcetypeassert
+		return []string{"even"}
+	}
+
+	return []string{"odd"}
+}
+
+// evenIntsController collects the IDs of even IntResources with the cache index.
+type evenIntsController struct{}
+
+func (ctrl *evenIntsController) Name() string {
+	return "EvenIntsController"
+}
+
+func (ctrl *evenIntsController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: "ints",
+			Type:      conformance.IntResourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+func (ctrl *evenIntsController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: conformance.StrResourceType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+func (ctrl *evenIntsController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		list, err := safe.ReaderListByIndex[*conformance.IntResource](ctx, r, resource.NewMetadata("ints", conformance.IntResourceType, "", resource.VersionUndefined), "parity", "even")
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, list.Len())
+
+		for res := range list.All() {
+			ids = append(ids, res.Metadata().ID())
+		}
+
+		if err = safe.WriterModify(ctx, r, conformance.NewStrResource("strings", "even", ""), func(res *conformance.StrResource) error {
+			res.SetValue(strings.Join(ids, ","))
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestRuntimeCacheIndex(t *testing.T) {
+	for _, test := range []struct {
+		check func(ctx context.Context, t *testing.T, st state.State, rt *runtime.Runtime)
+		name  string
+		opts  []options.Option
+	}{
+		{
+			name: "cached",
+			check: func(ctx context.Context, t *testing.T, st state.State, _ *runtime.Runtime) {
+				_, err := st.WatchFor(ctx, conformance.NewStrResource("strings", "even", "").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+					str, ok := r.(*conformance.StrResource)
+
+					return ok && str.Value() == "four,two", nil
+				}))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "dry-run",
+			opts: []options.Option{options.WithDryRun()},
+			check: func(_ context.Context, t *testing.T, _ state.State, rt *runtime.Runtime) {
+				// the index is served from the state, as the cache is bypassed in dry-run mode
+				assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+					plan, err := rt.DryRunPlan("EvenIntsController")
+					require.NoError(collect, err)
+					require.Len(collect, plan, 1)
+
+					str, ok := plan[0].New.(*conformance.StrResource)
+					require.True(collect, ok)
+					assert.Equal(collect, "four,two", str.Value())
+				}, 5*time.Second, 10*time.Millisecond)
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+			st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			for i, id := range []resource.ID{"one", "two", "three", "four"} {
+				require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", id, i+1)))
+			}
+
+			rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t), append(test.opts,
+				options.WithCachedResource("ints", conformance.IntResourceType, options.WithIndex("parity", parityIndex)),
+			)...)
+			require.NoError(t, err)
+
+			require.NoError(t, rt.RegisterController(&evenIntsController{}))
+
+			runCtx, runCancel := context.WithCancel(ctx)
+			defer runCancel()
+
+			_, errCh := future.GoContext(runCtx, rt.Run)
+
+			test.check(ctx, t, st, rt)
+
+			runCancel()
+
+			require.NoError(t, <-errCh)
+		})
+	}
+}
@@ -8,14 +8,21 @@ package adapter
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/dryrun"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/cache"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/controllerstatus"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/dependency"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/reduced"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/scheduler"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/logging"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/tracing"
 )
 
 // Adapter is common interface for controller adapters.
@@ -26,6 +33,28 @@ type Adapter interface {
 	//
 	// WatchTrigger should not block and should process the event asynchronously.
 	WatchTrigger(md *reduced.Metadata)
+	// Pause blocks controller writes, waiting for in-flight writes to finish.
+	Pause(ctx context.Context) error
+	// Resume unblocks controller writes.
+	Resume()
+}
+
+// ParkingAdapter is implemented by the adapters which park the items exhausting the backoff attempts.
+type ParkingAdapter interface {
+	// ParkedItems returns the parked items.
+	ParkedItems() []controller.ParkedItem
+	// RetryParked queues the parked items matching the pointers (or all parked items if none given) for reconcile.
+	//
+	// RetryParked returns the number of items queued.
+	RetryParked(ptrs ...resource.Pointer) int
+}
+
+// DryRunAdapter is implemented by the adapters which support dry-run mode.
+type DryRunAdapter interface {
+	// DryRunPlan returns the changes planned by the controller in dry-run mode.
+	DryRunPlan() ([]dryrun.Change, error)
+	// DryRunReset drops the changes planned by the controller in dry-run mode.
+	DryRunReset() error
 }
 
 // Options are options for creating a new Adapter.
@@ -34,6 +63,32 @@ type Options struct {
 	State          state.State
 	Cache          *cache.ResourceCache
 	DepDB          *dependency.Database
-	RegisterWatch  func(resourceNamespace resource.Namespace, resourceType resource.Type) error
+	Status         *controllerstatus.Tracker
+	Scheduler      *scheduler.Scheduler
+	RegisterWatch  func(input controller.Input) error
 	RuntimeOptions options.Options
 }
+
+// Tracer returns the tracer for the controller, or nil if the tracing is disabled.
+func (options Options) Tracer() trace.Tracer { //nolint:ireturn
+	if options.RuntimeOptions.TracerProvider == nil {
+		return nil
+	}
+
+	return tracing.Tracer(options.RuntimeOptions.TracerProvider)
+}
+
+// DryRun wraps the controller state with the dry-run overlay if the controller runs in dry-run mode.
+//
+// DryRun returns the state and the cache to be used by the controller, and the overlay (nil if dry-run is disabled).
+// The controller in dry-run mode reads through the overlay, so that it sees its own writes, while the overlay
+// reads the cached resources from the runtime cache.
+func (options Options) DryRun(controllerName string, st state.State) (state.State, *cache.ResourceCache, *dryrun.Overlay) {
+	if !options.RuntimeOptions.DryRun.Enabled(controllerName) {
+		return st, options.Cache, nil
+	}
+
+	overlay := dryrun.NewOverlay(options.Cache.WrapState(st), options.Logger.With(logging.Controller(controllerName)))
+
+	return state.WrapCore(overlay), cache.NewResourceCache(nil), overlay
+}
@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package adapter
+
+import (
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+)
+
+// NewBackOff creates a new exponential backoff from the policy.
+//
+// Backoff never stops retrying, zero values of the policy are replaced with the library defaults.
+func NewBackOff(policy controller.BackoffPolicy) *backoff.ExponentialBackOff {
+	bckoff := backoff.NewExponentialBackOff()
+	bckoff.MaxElapsedTime = 0
+
+	if policy.InitialInterval > 0 {
+		bckoff.InitialInterval = policy.InitialInterval
+	}
+
+	if policy.MaxInterval > 0 {
+		bckoff.MaxInterval = policy.MaxInterval
+	}
+
+	if policy.Multiplier > 0 {
+		bckoff.Multiplier = policy.Multiplier
+	}
+
+	if jitter, ok := policy.Jitter.Get(); ok {
+		bckoff.RandomizationFactor = jitter
+	}
+
+	bckoff.Reset()
+
+	return bckoff
+}
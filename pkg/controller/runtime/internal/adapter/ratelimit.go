@@ -0,0 +1,33 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package adapter
+
+import (
+	"golang.org/x/time/rate"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+)
+
+// NewLimiter creates a rate limiter from the controller rate limit.
+//
+// If the limit is not configured, NewLimiter returns nil.
+func NewLimiter(limit controller.RateLimit) *rate.Limiter {
+	if limit.IsZero() {
+		return nil
+	}
+
+	return rate.NewLimiter(limit.Limit, max(limit.Burst, 1))
+}
+
+// NewUpdateLimiter creates the rate limiter for the controller writes.
+//
+// Controller-specific limit overrides the runtime-wide one.
+func (options Options) NewUpdateLimiter(limit controller.RateLimit) *rate.Limiter {
+	if limiter := NewLimiter(limit); limiter != nil {
+		return limiter
+	}
+
+	return rate.NewLimiter(options.RuntimeOptions.ChangeRateLimit, options.RuntimeOptions.ChangeBurst)
+}
@@ -6,8 +6,11 @@
 package cache
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
@@ -24,10 +27,24 @@ import (
 // - on the Bootstrapped event, Watch handler should call MarkBootstrapped, and Get/List operations will be unblocked
 // - after the Bootstrapped event, CachePut/CacheRemove should be called to update the cache.
 //
+// If the watch is established again with BootstrapContents, the cache is resynced:
+// - BeginResync should be called before the watch is established
+// - bootstrapped contents should be passed to CacheResync
+// - on the Bootstrapped event, EndResync removes the resources which were not resynced.
+//
+// Resources cached across the namespaces matching a pattern are bootstrapped with a single watch,
+// so the Bootstrapped event (and MarkBootstrapped, Len, BeginResync, EndResync) uses the pattern as the namespace.
+//
 // Get/List operations will always return date from the cache without blocking once the cache is bootstrapped.
+//
+// Snapshot captures a consistent view of the cache across resource kinds.
 type ResourceCache struct {
 	// not using any locking here, as handlers can only be registered during initialization
 	handlers map[cacheKey]*cacheHandler
+	patterns map[resource.Type][]*patternHandlers
+
+	// snapshotMu is locked for writing while the snapshot is captured, and for reading while the cache is modified
+	snapshotMu sync.RWMutex
 }
 
 type cacheKey struct {
@@ -36,21 +53,52 @@ type cacheKey struct {
 }
 
 // Check interfaces.
-var _ controller.Reader = (*ResourceCache)(nil)
+var (
+	_ controller.Reader         = (*ResourceCache)(nil)
+	_ controller.IndexReader    = (*ResourceCache)(nil)
+	_ controller.ReadOnlyReader = (*ResourceCache)(nil)
+)
+
+// Option configures the ResourceCache.
+type Option func(*cacheOptions)
+
+type cacheOptions struct {
+	readOnlyChecks bool
+}
+
+// WithReadOnlyChecks enables verification that the resources returned by the read-only reads are not modified.
+func WithReadOnlyChecks(enabled bool) Option {
+	return func(opts *cacheOptions) {
+		opts.readOnlyChecks = enabled
+	}
+}
 
 // NewResourceCache creates new resource cache.
-func NewResourceCache(resources []options.CachedResource) *ResourceCache {
+func NewResourceCache(resources []options.CachedResource, opts ...Option) *ResourceCache {
+	var cacheOpts cacheOptions
+
+	for _, opt := range opts {
+		opt(&cacheOpts)
+	}
+
 	cache := &ResourceCache{
 		handlers: make(map[cacheKey]*cacheHandler, len(resources)),
+		patterns: map[resource.Type][]*patternHandlers{},
 	}
 
 	for _, r := range resources {
+		if r.NamespacePattern != "" {
+			cache.patterns[r.Type] = append(cache.patterns[r.Type], newPatternHandlers(r, cacheOpts.readOnlyChecks))
+
+			continue
+		}
+
 		key := cacheKey{
 			Namespace: r.Namespace,
 			Type:      r.Type,
 		}
 
-		cache.handlers[key] = newCacheHandler(key)
+		cache.handlers[key] = newCacheHandler(key, r, cacheOpts.readOnlyChecks)
 	}
 
 	return cache
@@ -62,32 +110,183 @@ func (cache *ResourceCache) getHandler(namespace resource.Namespace, resourceTyp
 		Type:      resourceType,
 	}
 
-	handler, ok := cache.handlers[key]
-	if !ok {
-		panic(fmt.Sprintf("cache handler for %s/%s doesn't exist", namespace, resourceType))
+	if handler, ok := cache.handlers[key]; ok {
+		return handler
+	}
+
+	if p := cache.matchPattern(namespace, resourceType); p != nil {
+		return p.handler(namespace)
+	}
+
+	panic(fmt.Sprintf("cache handler for %s/%s doesn't exist", namespace, resourceType))
+}
+
+// getPattern returns the pattern handlers if the namespace is the pattern itself.
+func (cache *ResourceCache) getPattern(namespace resource.Namespace, resourceType resource.Type) *patternHandlers {
+	for _, p := range cache.patterns[resourceType] {
+		if p.pattern == namespace {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (cache *ResourceCache) mustGetPattern(pattern string, resourceType resource.Type) *patternHandlers {
+	if p := cache.getPattern(pattern, resourceType); p != nil {
+		return p
+	}
+
+	panic(fmt.Sprintf("cache handler for pattern %s/%s doesn't exist", pattern, resourceType))
+}
+
+// matchPattern returns the pattern handlers matching the namespace.
+func (cache *ResourceCache) matchPattern(namespace resource.Namespace, resourceType resource.Type) *patternHandlers {
+	for _, p := range cache.patterns[resourceType] {
+		if p.matches(namespace) {
+			return p
+		}
 	}
 
-	return handler
+	return nil
 }
 
 // MarkBootstrapped marks cache as bootstrapped.
 func (cache *ResourceCache) MarkBootstrapped(namespace resource.Namespace, resourceType resource.Type) {
+	if p := cache.getPattern(namespace, resourceType); p != nil {
+		p.markBootstrapped()
+
+		return
+	}
+
 	cache.getHandler(namespace, resourceType).markBootstrapped()
 }
 
 // Len returns number of cached resources.
 func (cache *ResourceCache) Len(namespace resource.Namespace, resourceType resource.Type) int {
+	if p := cache.getPattern(namespace, resourceType); p != nil {
+		return p.len()
+	}
+
 	return cache.getHandler(namespace, resourceType).len()
 }
 
 // IsHandled returns true if cache is handling given resource type.
 func (cache *ResourceCache) IsHandled(namespace resource.Namespace, resourceType resource.Type) bool {
-	_, ok := cache.handlers[cacheKey{
+	if _, ok := cache.handlers[cacheKey{
 		Namespace: namespace,
 		Type:      resourceType,
-	}]
+	}]; ok {
+		return true
+	}
+
+	return cache.getPattern(namespace, resourceType) != nil || cache.matchPattern(namespace, resourceType) != nil
+}
+
+// ServesGet returns true if Get of the resource is served from the cache.
+//
+// Metadata-only cache and the cache which exceeded the size cap don't serve Get.
+func (cache *ResourceCache) ServesGet(namespace resource.Namespace, resourceType resource.Type) bool {
+	if !cache.IsHandled(namespace, resourceType) {
+		return false
+	}
 
-	return ok
+	h := cache.getHandler(namespace, resourceType)
+
+	return !h.metadataOnly && !h.isOverflowed()
+}
+
+// ServesList returns true if List of the resource is served from the cache.
+//
+// The cache which exceeded the size cap doesn't serve List.
+func (cache *ResourceCache) ServesList(namespace resource.Namespace, resourceType resource.Type) bool {
+	if !cache.IsHandled(namespace, resourceType) {
+		return false
+	}
+
+	return !cache.getHandler(namespace, resourceType).isOverflowed()
+}
+
+// IsPopulatedBy returns true if the cache handler of the resource is populated by the watch of the namespace
+// (or the namespace pattern if pattern is set).
+//
+// If the namespace is cached both exactly and by a pattern, the exact watch populates the cache.
+func (cache *ResourceCache) IsPopulatedBy(watchNamespace resource.Namespace, pattern bool, r resource.Resource) bool {
+	namespace, resourceType := r.Metadata().Namespace(), r.Metadata().Type()
+
+	if _, ok := cache.handlers[cacheKey{Namespace: namespace, Type: resourceType}]; ok {
+		return !pattern && watchNamespace == namespace
+	}
+
+	if p := cache.matchPattern(namespace, resourceType); p != nil {
+		return pattern && p.pattern == watchNamespace
+	}
+
+	return false
+}
+
+// ServesListPattern returns true if List across the namespaces matching the pattern is served from the cache.
+//
+// The resources should be cached with the same pattern.
+func (cache *ResourceCache) ServesListPattern(pattern string, resourceType resource.Type) bool {
+	p := cache.getPattern(pattern, resourceType)
+
+	return p != nil && !slices.ContainsFunc(cache.patternHandlers(p), (*cacheHandler).isOverflowed)
+}
+
+// patternHandlers returns the handlers of the namespaces matching the pattern sorted by namespace.
+//
+// Namespaces which are cached exactly are handled by their own handlers.
+func (cache *ResourceCache) patternHandlers(p *patternHandlers) []*cacheHandler {
+	handlers := p.all()
+
+	for key, h := range cache.handlers {
+		if key.Type == p.typ && p.matches(key.Namespace) {
+			handlers = append(handlers, h)
+		}
+	}
+
+	slices.SortFunc(handlers, func(a, b *cacheHandler) int {
+		return cmp.Compare(a.key.Namespace, b.key.Namespace)
+	})
+
+	return handlers
+}
+
+// listPattern lists the resources across the namespaces matching the pattern, sorted by namespace and ID.
+func (cache *ResourceCache) listPattern(ctx context.Context, p *patternHandlers, readOnly bool, opts ...state.ListOption) (resource.List, error) {
+	// wait for bootstrap, as there might be no namespace handlers yet
+	select {
+	case <-ctx.Done():
+		return resource.List{}, ctx.Err()
+	case <-p.bootstrapped:
+	}
+
+	// namespace handlers list without the pattern
+	opts = append(slices.Clone(opts), state.WithListNamespacePattern(""))
+
+	var list resource.List
+
+	for _, h := range cache.patternHandlers(p) {
+		var (
+			nsList resource.List
+			err    error
+		)
+
+		if readOnly {
+			nsList, err = h.listReadOnly(ctx, opts...)
+		} else {
+			nsList, err = h.list(ctx, opts...)
+		}
+
+		if err != nil {
+			return resource.List{}, err
+		}
+
+		list.Items = append(list.Items, nsList.Items...)
+	}
+
+	return list, nil
 }
 
 // IsHandledBootstrapped returns true if cache is handling given resource type and whether it is bootstrapped.
@@ -101,9 +300,15 @@ func (cache *ResourceCache) IsHandledBootstrapped(namespace resource.Namespace,
 
 	if handled {
 		bootstrapped = handler.isBootstrapped()
+
+		return handled, bootstrapped
+	}
+
+	if p := cache.matchPattern(namespace, resourceType); p != nil {
+		return true, p.isBootstrapped()
 	}
 
-	return handled, bootstrapped
+	return false, false
 }
 
 // Get implements controller.Reader interface.
@@ -112,10 +317,35 @@ func (cache *ResourceCache) Get(ctx context.Context, ptr resource.Pointer, opts
 }
 
 // List implements controller.Reader interface.
+//
+// List across the namespaces (see state.WithListNamespacePattern) is served if the resources are cached with the same pattern.
 func (cache *ResourceCache) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if pattern := listNamespacePattern(opts); pattern != "" {
+		return cache.listPattern(ctx, cache.mustGetPattern(pattern, kind.Type()), false, opts...)
+	}
+
 	return cache.getHandler(kind.Namespace(), kind.Type()).list(ctx, opts...)
 }
 
+// GetReadOnly implements controller.ReadOnlyReader interface.
+func (cache *ResourceCache) GetReadOnly(ctx context.Context, ptr resource.Pointer) (resource.Resource, error) {
+	return cache.getHandler(ptr.Namespace(), ptr.Type()).getReadOnly(ctx, ptr.ID())
+}
+
+// ListReadOnly implements controller.ReadOnlyReader interface.
+func (cache *ResourceCache) ListReadOnly(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if pattern := listNamespacePattern(opts); pattern != "" {
+		return cache.listPattern(ctx, cache.mustGetPattern(pattern, kind.Type()), true, opts...)
+	}
+
+	return cache.getHandler(kind.Namespace(), kind.Type()).listReadOnly(ctx, opts...)
+}
+
+// ListByIndex implements controller.IndexReader interface.
+func (cache *ResourceCache) ListByIndex(ctx context.Context, kind resource.Kind, index, key string) (resource.List, error) {
+	return cache.getHandler(kind.Namespace(), kind.Type()).listByIndex(ctx, index, key)
+}
+
 // ContextWithTeardown implements controller.Reader interface.
 func (cache *ResourceCache) ContextWithTeardown(ctx context.Context, ptr resource.Pointer) (context.Context, error) {
 	return cache.getHandler(ptr.Namespace(), ptr.Type()).contextWithTeardown(ctx, ptr.ID())
@@ -125,6 +355,9 @@ func (cache *ResourceCache) ContextWithTeardown(ctx context.Context, ptr resourc
 //
 // CacheAppend should be called in the bootstrapped phase, with resources coming in sorted by ID order.
 func (cache *ResourceCache) CacheAppend(r resource.Resource) {
+	cache.snapshotMu.RLock()
+	defer cache.snapshotMu.RUnlock()
+
 	cache.getHandler(r.Metadata().Namespace(), r.Metadata().Type()).append(r)
 }
 
@@ -132,14 +365,64 @@ func (cache *ResourceCache) CacheAppend(r resource.Resource) {
 //
 // It is called once the bootstrap is done.
 func (cache *ResourceCache) CachePut(r resource.Resource) {
+	cache.snapshotMu.RLock()
+	defer cache.snapshotMu.RUnlock()
+
 	cache.getHandler(r.Metadata().Namespace(), r.Metadata().Type()).put(r)
 }
 
 // CacheRemove handles deleted objects.
 func (cache *ResourceCache) CacheRemove(r resource.Resource) {
+	cache.snapshotMu.RLock()
+	defer cache.snapshotMu.RUnlock()
+
 	cache.getHandler(r.Metadata().Namespace(), r.Metadata().Type()).remove(r)
 }
 
+// BeginResync starts the resync of the cache.
+//
+// BeginResync should be called before the watch is established again with BootstrapContents.
+func (cache *ResourceCache) BeginResync(namespace resource.Namespace, resourceType resource.Type) {
+	if p := cache.getPattern(namespace, resourceType); p != nil {
+		p.beginResync()
+
+		return
+	}
+
+	cache.getHandler(namespace, resourceType).beginResync()
+}
+
+// NeedsResync returns true if the cache of the resource exceeded the size cap, and it can be recovered now.
+//
+// The cache is recovered once the number of the resources drops to half of the cap, and the watch is established
+// again with BootstrapContents (see BeginResync), until the resync is done the reads are not served from the cache.
+func (cache *ResourceCache) NeedsResync(namespace resource.Namespace, resourceType resource.Type) bool {
+	return cache.getHandler(namespace, resourceType).needsResync()
+}
+
+// CacheResync handles objects of the bootstrapped contents during the resync.
+func (cache *ResourceCache) CacheResync(r resource.Resource) {
+	cache.snapshotMu.RLock()
+	defer cache.snapshotMu.RUnlock()
+
+	cache.getHandler(r.Metadata().Namespace(), r.Metadata().Type()).resync(r)
+}
+
+// EndResync finishes the resync of the cache.
+//
+// EndResync removes the resources which were not resynced (as they were destroyed while the watch was failed),
+// and it returns the removed resources.
+func (cache *ResourceCache) EndResync(namespace resource.Namespace, resourceType resource.Type) []resource.Resource {
+	cache.snapshotMu.RLock()
+	defer cache.snapshotMu.RUnlock()
+
+	if p := cache.getPattern(namespace, resourceType); p != nil {
+		return p.endResync()
+	}
+
+	return cache.getHandler(namespace, resourceType).endResync()
+}
+
 // WrapState returns a cached wrapped state, which serves some operations from the cache bypassing the underlying state.
 func (cache *ResourceCache) WrapState(st state.CoreState) state.CoreState {
 	return &stateWrapper{
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/siderolabs/gen/xslices"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/filter"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/conformance"
 )
@@ -314,3 +316,469 @@ func BenchmarkRemove(b *testing.B) {
 		c.CacheRemove(resource.NewTombstone(resource.NewMetadata("a", "A", resourceIDGenerator(i%N), resource.VersionUndefined)))
 	}
 }
+
+func TestCacheResync(t *testing.T) {
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			Namespace: "a",
+			Type:      "A",
+		},
+	})
+
+	for _, id := range []resource.ID{"1", "2", "3"} {
+		c.CacheAppend(resource.NewTombstone(resource.NewMetadata("a", "A", id, resource.VersionUndefined)))
+	}
+
+	c.MarkBootstrapped("a", "A")
+
+	c.BeginResync("a", "A")
+
+	// resource "2" was destroyed, and "4" was created while the watch was failed
+	for _, id := range []resource.ID{"1", "3", "4"} {
+		c.CacheResync(resource.NewTombstone(resource.NewMetadata("a", "A", id, resource.VersionUndefined)))
+	}
+
+	stale := c.EndResync("a", "A")
+	require.Len(t, stale, 1)
+	assert.Equal(t, "2", stale[0].Metadata().ID())
+
+	// cache is still bootstrapped
+	c.MarkBootstrapped("a", "A")
+
+	list, err := c.List(t.Context(), resource.NewMetadata("a", "A", "", resource.VersionUndefined))
+	require.NoError(t, err)
+
+	ids := make([]resource.ID, 0, len(list.Items))
+
+	for _, r := range list.Items {
+		ids = append(ids, r.Metadata().ID())
+	}
+
+	assert.Equal(t, []resource.ID{"1", "3", "4"}, ids)
+}
+
+func TestCacheIndex(t *testing.T) {
+	parity := func(r resource.Resource) []string {
+		n, _ := strconv.Atoi(r.Metadata().ID()) //nolint:errcheck
+
+		if n%2 == 0 {
+			return []string{"even"}
+		}
+
+		return []string{"odd"}
+	}
+
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			Namespace: "a",
+			Type:      "A",
+			Indexes: map[string]options.IndexFunc{
+				"parity": parity,
+			},
+		},
+	})
+
+	for i := range 5 {
+		c.CacheAppend(resource.NewTombstone(resource.NewMetadata("a", "A", strconv.Itoa(i), resource.VersionUndefined)))
+	}
+
+	c.MarkBootstrapped("a", "A")
+
+	kind := resource.NewMetadata("a", "A", "", resource.VersionUndefined)
+
+	listIDs := func(key string) []resource.ID {
+		list, err := c.ListByIndex(t.Context(), kind, "parity", key)
+		require.NoError(t, err)
+
+		ids := make([]resource.ID, 0, len(list.Items))
+
+		for _, r := range list.Items {
+			ids = append(ids, r.Metadata().ID())
+		}
+
+		return ids
+	}
+
+	assert.Equal(t, []resource.ID{"0", "2", "4"}, listIDs("even"))
+	assert.Equal(t, []resource.ID{"1", "3"}, listIDs("odd"))
+	assert.Empty(t, listIDs("none"))
+
+	c.CachePut(resource.NewTombstone(resource.NewMetadata("a", "A", "6", resource.VersionUndefined)))
+	c.CacheRemove(resource.NewTombstone(resource.NewMetadata("a", "A", "2", resource.VersionUndefined)))
+
+	assert.Equal(t, []resource.ID{"0", "4", "6"}, listIDs("even"))
+
+	_, err := c.ListByIndex(t.Context(), kind, "missing", "even")
+	require.ErrorContains(t, err, `index "missing" is not defined`)
+}
+
+func TestCacheReadOnly(t *testing.T) {
+	newCache := func(readOnlyChecks bool) (*cache.ResourceCache, resource.Kind) {
+		c := cache.NewResourceCache([]options.CachedResource{
+			{
+				Namespace: "a",
+				Type:      conformance.PathResourceType,
+			},
+		}, cache.WithReadOnlyChecks(readOnlyChecks))
+
+		for _, id := range []resource.ID{"1", "2"} {
+			c.CacheAppend(conformance.NewPathResource("a", id))
+		}
+
+		c.MarkBootstrapped("a", conformance.PathResourceType)
+
+		return c, resource.NewMetadata("a", conformance.PathResourceType, "", resource.VersionUndefined)
+	}
+
+	t.Run("shared", func(t *testing.T) {
+		c, kind := newCache(false)
+
+		list1, err := c.ListReadOnly(t.Context(), kind)
+		require.NoError(t, err)
+
+		list2, err := c.ListReadOnly(t.Context(), kind)
+		require.NoError(t, err)
+
+		require.Len(t, list1.Items, 2)
+		assert.Same(t, list1.Items[0], list2.Items[0])
+
+		r, err := c.GetReadOnly(t.Context(), conformance.NewPathResource("a", "2").Metadata())
+		require.NoError(t, err)
+		assert.Same(t, list1.Items[1], r)
+
+		// regular reads return copies
+		list3, err := c.List(t.Context(), kind)
+		require.NoError(t, err)
+		assert.NotSame(t, list1.Items[0], list3.Items[0])
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		c, kind := newCache(true)
+
+		list, err := c.ListReadOnly(t.Context(), kind)
+		require.NoError(t, err)
+
+		_, err = c.GetReadOnly(t.Context(), conformance.NewPathResource("a", "1").Metadata())
+		require.NoError(t, err)
+
+		list.Items[0].Metadata().Labels().Set("modified", "")
+
+		_, err = c.GetReadOnly(t.Context(), conformance.NewPathResource("a", "1").Metadata())
+		require.ErrorContains(t, err, "was modified after the read-only access")
+
+		_, err = c.ListReadOnly(t.Context(), kind)
+		require.Error(t, err)
+	})
+
+	t.Run("modified and replaced", func(t *testing.T) {
+		c, kind := newCache(true)
+
+		list, err := c.ListReadOnly(t.Context(), kind)
+		require.NoError(t, err)
+
+		list.Items[1].Metadata().Labels().Set("modified", "")
+
+		c.CachePut(conformance.NewPathResource("a", "2"))
+
+		_, err = c.ListReadOnly(t.Context(), kind)
+		require.ErrorContains(t, err, "was modified after the read-only access")
+	})
+}
+
+func TestCachePattern(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			NamespacePattern: "tenant-*",
+			Type:             "A",
+		},
+	})
+
+	assert.True(t, c.IsHandled("tenant-*", "A"))
+	assert.True(t, c.IsHandled("tenant-a", "A"))
+	assert.False(t, c.IsHandled("other", "A"))
+	assert.False(t, c.IsHandled("tenant-a", "B"))
+
+	c.CacheAppend(resource.NewTombstone(resource.NewMetadata("tenant-a", "A", "1", resource.VersionUndefined)))
+	c.CacheAppend(resource.NewTombstone(resource.NewMetadata("tenant-b", "A", "2", resource.VersionUndefined)))
+
+	handled, bootstrapped := c.IsHandledBootstrapped("tenant-a", "A")
+	assert.True(t, handled)
+	assert.False(t, bootstrapped)
+
+	c.MarkBootstrapped("tenant-*", "A")
+
+	// namespace which was not bootstrapped is bootstrapped with the pattern
+	handled, bootstrapped = c.IsHandledBootstrapped("tenant-c", "A")
+	assert.True(t, handled)
+	assert.True(t, bootstrapped)
+
+	assert.Equal(t, 2, c.Len("tenant-*", "A"))
+
+	list, err := c.List(ctx, resource.NewMetadata("tenant-c", "A", "", resource.VersionUndefined))
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+
+	c.CachePut(resource.NewTombstone(resource.NewMetadata("tenant-c", "A", "3", resource.VersionUndefined)))
+
+	_, err = c.Get(ctx, resource.NewMetadata("tenant-c", "A", "3", resource.VersionUndefined))
+	require.NoError(t, err)
+
+	c.BeginResync("tenant-*", "A")
+	c.CacheResync(resource.NewTombstone(resource.NewMetadata("tenant-a", "A", "1", resource.VersionUndefined)))
+
+	removed := c.EndResync("tenant-*", "A")
+	require.Len(t, removed, 2)
+	assert.ElementsMatch(t, []resource.ID{"2", "3"}, []resource.ID{removed[0].Metadata().ID(), removed[1].Metadata().ID()})
+	assert.Equal(t, 1, c.Len("tenant-*", "A"))
+}
+
+func TestCachePatternList(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			Namespace: "tenant-a",
+			Type:      "A",
+		},
+		{
+			NamespacePattern: "tenant-*",
+			Type:             "A",
+		},
+	})
+
+	tenantA := resource.NewTombstone(resource.NewMetadata("tenant-a", "A", "1", resource.VersionUndefined))
+	tenantB := resource.NewTombstone(resource.NewMetadata("tenant-b", "A", "2", resource.VersionUndefined))
+
+	// the namespace cached exactly is populated by its own watch
+	assert.True(t, c.IsPopulatedBy("tenant-a", false, tenantA))
+	assert.False(t, c.IsPopulatedBy("tenant-*", true, tenantA))
+	assert.True(t, c.IsPopulatedBy("tenant-*", true, tenantB))
+	assert.False(t, c.IsPopulatedBy("tenant-b", false, tenantB))
+
+	assert.True(t, c.ServesListPattern("tenant-*", "A"))
+	assert.False(t, c.ServesListPattern("tenant-a*", "A"))
+
+	c.CacheAppend(tenantA)
+	c.CacheAppend(resource.NewTombstone(resource.NewMetadata("tenant-b", "A", "0", resource.VersionUndefined)))
+	c.CacheAppend(tenantB)
+
+	c.MarkBootstrapped("tenant-a", "A")
+	c.MarkBootstrapped("tenant-*", "A")
+
+	// the list spans the namespace cached exactly, and it is sorted by namespace and ID
+	list, err := c.List(ctx, resource.NewMetadata("tenant-*", "A", "", resource.VersionUndefined), state.WithListNamespacePattern("tenant-*"))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"tenant-a/1", "tenant-b/0", "tenant-b/2"},
+		xslices.Map(list.Items, func(r resource.Resource) string { return r.Metadata().Namespace() + "/" + r.Metadata().ID() }),
+	)
+
+	list, err = c.ListReadOnly(ctx, resource.NewMetadata("tenant-*", "A", "", resource.VersionUndefined), state.WithListNamespacePattern("tenant-*"), state.WithIDQuery(resource.IDRegexpMatch(regexp.MustCompile("^2$"))))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "tenant-b", list.Items[0].Metadata().Namespace())
+}
+
+func TestCacheMetadataOnly(t *testing.T) {
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			Namespace:    "a",
+			Type:         conformance.PathResourceType,
+			MetadataOnly: true,
+			Indexes: map[string]options.IndexFunc{
+				"labeled": func(r resource.Resource) []string {
+					return []string{r.Metadata().Labels().Raw()["app"]}
+				},
+			},
+		},
+	})
+
+	r := conformance.NewPathResource("a", "1")
+	r.Metadata().Labels().Set("app", "test")
+	require.NoError(t, r.Metadata().SetOwner("ctrl"))
+
+	c.CacheAppend(r)
+	c.MarkBootstrapped("a", conformance.PathResourceType)
+
+	assert.True(t, c.ServesList("a", conformance.PathResourceType))
+	assert.False(t, c.ServesGet("a", conformance.PathResourceType))
+
+	kind := resource.NewMetadata("a", conformance.PathResourceType, "", resource.VersionUndefined)
+
+	list, err := c.List(t.Context(), kind, state.WithLabelQuery(resource.LabelEqual("app", "test")))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	assert.Nil(t, list.Items[0].Spec())
+	assert.True(t, resource.IsMetadataOnly(list.Items[0]))
+	assert.True(t, list.Items[0].Metadata().Equal(*r.Metadata()))
+
+	list, err = c.List(t.Context(), kind, state.WithFilter(filter.MustCompile(`metadata.owner == "ctrl"`)))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	_, err = c.List(t.Context(), kind, state.WithFilter(filter.MustCompile(`spec.value == 1`)))
+	require.ErrorContains(t, err, "doesn't support filters on the spec")
+
+	list, err = c.ListByIndex(t.Context(), kind, "labeled", "test")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "ctrl", list.Items[0].Metadata().Owner())
+}
+
+func TestCacheMaxSize(t *testing.T) {
+	metrics.CachedResources.Init()
+	metrics.CacheOverflows.Init()
+
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			Namespace: "a",
+			Type:      "A",
+			MaxSize:   2,
+		},
+	})
+
+	c.CacheAppend(resource.NewTombstone(resource.NewMetadata("a", "A", "1", resource.VersionUndefined)))
+	c.CacheAppend(resource.NewTombstone(resource.NewMetadata("a", "A", "2", resource.VersionUndefined)))
+	c.MarkBootstrapped("a", "A")
+
+	assert.True(t, c.ServesList("a", "A"))
+	assert.Equal(t, "2", metrics.CachedResources.Get("A").String())
+
+	c.CachePut(resource.NewTombstone(resource.NewMetadata("a", "A", "3", resource.VersionUndefined)))
+
+	assert.False(t, c.ServesList("a", "A"))
+	assert.False(t, c.ServesGet("a", "A"))
+	assert.Equal(t, 0, c.Len("a", "A"))
+	assert.Equal(t, "0", metrics.CachedResources.Get("A").String())
+	assert.Equal(t, "1", metrics.CacheOverflows.Get("A").String())
+
+	// further updates are ignored
+	c.CachePut(resource.NewTombstone(resource.NewMetadata("a", "A", "4", resource.VersionUndefined)))
+	assert.Equal(t, 0, c.Len("a", "A"))
+
+	_, err := c.List(t.Context(), resource.NewMetadata("a", "A", "", resource.VersionUndefined))
+	require.Error(t, err)
+	assert.True(t, cache.IsOverflowed(err))
+
+	// the cache is recovered once the number of the resources drops to half of the cap
+	c.CacheRemove(resource.NewTombstone(resource.NewMetadata("a", "A", "3", resource.VersionUndefined)))
+	c.CacheRemove(resource.NewTombstone(resource.NewMetadata("a", "A", "4", resource.VersionUndefined)))
+	assert.False(t, c.NeedsResync("a", "A"))
+
+	c.CacheRemove(resource.NewTombstone(resource.NewMetadata("a", "A", "2", resource.VersionUndefined)))
+	assert.True(t, c.NeedsResync("a", "A"))
+
+	c.BeginResync("a", "A")
+	assert.False(t, c.NeedsResync("a", "A"))
+
+	c.CacheResync(resource.NewTombstone(resource.NewMetadata("a", "A", "5", resource.VersionUndefined)))
+	assert.False(t, c.ServesList("a", "A"))
+
+	// resource 1 was destroyed while the watch was established again
+	removed := c.EndResync("a", "A")
+	require.Len(t, removed, 1)
+	assert.Equal(t, "1", removed[0].Metadata().ID())
+	assert.True(t, resource.IsTombstone(removed[0]))
+
+	assert.True(t, c.ServesList("a", "A"))
+	assert.Equal(t, 1, c.Len("a", "A"))
+	assert.Equal(t, "1", metrics.CachedResources.Get("A").String())
+
+	list, err := c.List(t.Context(), resource.NewMetadata("a", "A", "", resource.VersionUndefined))
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "5", list.Items[0].Metadata().ID())
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	c := cache.NewResourceCache([]options.CachedResource{
+		{
+			Namespace: "a",
+			Type:      "A",
+		},
+		{
+			Namespace: "b",
+			Type:      "B",
+		},
+		{
+			NamespacePattern: "p-*",
+			Type:             "P",
+		},
+	})
+
+	for i := range 3 {
+		c.CacheAppend(resource.NewTombstone(resource.NewMetadata("a", "A", resourceIDGenerator(i), resource.VersionUndefined)))
+	}
+
+	c.MarkBootstrapped("a", "A")
+
+	// snapshot waits for the bootstrap of all requested types
+	blockedCtx, blockedCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer blockedCancel()
+
+	_, err := c.Snapshot(blockedCtx, "A", "B")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	c.CacheAppend(resource.NewTombstone(resource.NewMetadata("b", "B", "b", resource.VersionUndefined)))
+	c.MarkBootstrapped("b", "B")
+	c.MarkBootstrapped("p-*", "P")
+
+	snapshot, err := c.Snapshot(ctx, "A", "B", "P")
+	require.NoError(t, err)
+
+	// modify the cache after the snapshot
+	c.CachePut(resource.NewTombstone(resource.NewMetadata("a", "A", "new", resource.VersionUndefined)))
+	c.CacheRemove(resource.NewTombstone(resource.NewMetadata("a", "A", resourceIDGenerator(0), resource.VersionUndefined)))
+	c.CacheRemove(resource.NewTombstone(resource.NewMetadata("b", "B", "b", resource.VersionUndefined)))
+	c.CachePut(resource.NewTombstone(resource.NewMetadata("p-1", "P", "p", resource.VersionUndefined)))
+
+	kindA := resource.NewMetadata("a", "A", "", resource.VersionUndefined)
+
+	list, err := snapshot.List(kindA)
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 3)
+
+	_, err = snapshot.Get(resource.NewMetadata("a", "A", resourceIDGenerator(0), resource.VersionUndefined))
+	require.NoError(t, err)
+
+	_, err = snapshot.Get(resource.NewMetadata("b", "B", "b", resource.VersionUndefined))
+	require.NoError(t, err)
+
+	_, err = snapshot.Get(resource.NewMetadata("a", "A", "new", resource.VersionUndefined))
+	require.True(t, state.IsNotFoundError(err))
+
+	assert.True(t, snapshot.ServesList("p-1", "P"))
+
+	list, err = snapshot.List(resource.NewMetadata("p-1", "P", "", resource.VersionUndefined))
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+
+	assert.False(t, snapshot.ServesList("c", "C"))
+
+	// live cache sees the modifications
+	list, err = c.List(ctx, kindA)
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 3)
+	assert.Equal(t, "new", list.Items[2].Metadata().ID())
+
+	// new snapshot sees the modifications
+	snapshot, err = c.Snapshot(ctx, "A", "B", "P")
+	require.NoError(t, err)
+
+	list, err = snapshot.List(resource.NewMetadata("p-1", "P", "", resource.VersionUndefined))
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+
+	list, err = snapshot.List(resource.NewMetadata("b", "B", "", resource.VersionUndefined))
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
@@ -5,6 +5,7 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/cosi-project/runtime/pkg/resource"
@@ -23,3 +24,19 @@ func ErrNotFound(r resource.Pointer) error {
 		fmt.Errorf("resource %s doesn't exist", r),
 	}
 }
+
+// errOverflowed is returned by the reads of the cache which exceeded the size cap while the read was in progress.
+var errOverflowed = errors.New("resource cache exceeded the size cap")
+
+// IsOverflowed checks if the read failed because the cache exceeded the size cap.
+//
+// The read should fall back to the underlying state.
+func IsOverflowed(err error) bool {
+	return errors.Is(err, errOverflowed)
+}
+
+// ErrMetadataOnlyWrite is returned on the write of the resource returned by the metadata-only cache,
+// as it would replace the spec with nil.
+func ErrMetadataOnlyWrite(r resource.Resource) error {
+	return fmt.Errorf("resource %s is metadata-only, the spec is not loaded, so it can't be written", r.Metadata())
+}
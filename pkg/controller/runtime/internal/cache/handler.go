@@ -15,6 +15,7 @@ import (
 	"github.com/siderolabs/gen/xslices"
 
 	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 )
@@ -25,21 +26,108 @@ import (
 // When marked as bootstrapped, the channel is closed, so reading from it would not block.
 //
 // Field resources contains a sorted (by ID) list of resources.
-// Field mu protects resources slice.
+// Field indexes maps the index names to the indexes of the resources.
+// Field checker (optional) verifies that the resources returned by the read-only reads are not modified.
+// Field resynced is non-nil while the resync is in progress, and it contains IDs of the resources seen during the resync.
+// Field metadataOnly is set if only the metadata of the resources is cached (see resource.MetadataOnly).
+// Field maxSize (optional) caps the number of the resources, once exceeded the handler is overflowed,
+// the resources are dropped and the reads should not be served from the cache (see ResourceCache.ServesList).
+// Field overflowIDs contains IDs of the resources while the handler is overflowed, once the number of the resources
+// drops to half of the cap, the handler should be resynced to recover (see ResourceCache.NeedsResync).
+// Field recovering is set while the overflowed handler is resynced, the resources are cached again.
+// Field shared is set if the resources slice is captured by a snapshot, so it should be copied before it is modified.
+// Field mu protects resources slice, indexes, checker, resynced and overflowIDs maps, overflowed, recovering and shared flags.
 type cacheHandler struct {
 	key          cacheKey
 	bootstrapped chan struct{}
 
+	metadataOnly bool
+	maxSize      int
+	overflowed   bool
+	recovering   bool
+	shared       bool
+
+	indexes         map[string]*cacheIndex
+	checker         *readOnlyChecker
 	teardownWaiters map[resource.ID]chan struct{}
+	resynced        map[resource.ID]struct{}
+	overflowIDs     map[resource.ID]struct{}
 	resources       []resource.Resource
 	mu              sync.Mutex
 }
 
-func newCacheHandler(key cacheKey) *cacheHandler {
-	return &cacheHandler{
+func newCacheHandler(key cacheKey, cached options.CachedResource, readOnlyChecks bool) *cacheHandler {
+	h := &cacheHandler{
 		key:          key,
 		bootstrapped: make(chan struct{}),
+		metadataOnly: cached.MetadataOnly,
+		maxSize:      cached.MaxSize,
+		indexes:      make(map[string]*cacheIndex, len(cached.Indexes)),
+	}
+
+	if readOnlyChecks {
+		h.checker = newReadOnlyChecker()
 	}
+
+	for name, fn := range cached.Indexes {
+		h.indexes[name] = newCacheIndex(fn)
+	}
+
+	return h
+}
+
+// stored returns the representation of the resource stored in the cache.
+func (h *cacheHandler) stored(r resource.Resource) resource.Resource { //nolint:ireturn
+	if h.metadataOnly {
+		return resource.NewMetadataOnly(r.Metadata())
+	}
+
+	return r
+}
+
+func (h *cacheHandler) isOverflowed() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.overflowed
+}
+
+// checkSizeLocked drops the cached resources if the size cap is exceeded.
+func (h *cacheHandler) checkSizeLocked() {
+	if h.maxSize <= 0 || len(h.resources) <= h.maxSize {
+		return
+	}
+
+	metrics.CachedResources.Add(h.key.Type, -int64(len(h.resources)))
+	metrics.CacheOverflows.Add(h.key.Type, 1)
+
+	if h.overflowIDs == nil {
+		h.overflowIDs = make(map[resource.ID]struct{}, len(h.resources))
+	}
+
+	for _, r := range h.resources {
+		h.overflowIDs[r.Metadata().ID()] = struct{}{}
+	}
+
+	h.overflowed = true
+	h.recovering = false
+	h.resources = nil
+
+	for name, index := range h.indexes {
+		h.indexes[name] = newCacheIndex(index.fn)
+	}
+
+	if h.checker != nil {
+		h.checker = newReadOnlyChecker()
+	}
+}
+
+// needsResync returns true if the overflowed handler can be recovered.
+func (h *cacheHandler) needsResync() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.overflowed && !h.recovering && len(h.overflowIDs) <= h.maxSize/2
 }
 
 func (h *cacheHandler) isBootstrapped() bool {
@@ -52,10 +140,37 @@ func (h *cacheHandler) isBootstrapped() bool {
 }
 
 func (h *cacheHandler) markBootstrapped() {
-	close(h.bootstrapped)
+	// the cache might be bootstrapped again after the resync
+	if !h.isBootstrapped() {
+		close(h.bootstrapped)
+	}
 }
 
 func (h *cacheHandler) get(ctx context.Context, id resource.ID, opts ...state.GetOption) (resource.Resource, error) {
+	r, err := h.getShared(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// return a copy of the resource to satisfy State semantics
+	return r.DeepCopy(), nil
+}
+
+func (h *cacheHandler) getReadOnly(ctx context.Context, id resource.ID) (resource.Resource, error) {
+	r, err := h.getShared(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.checkReadOnly([]resource.Resource{r}); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// getShared returns the cached resource without copying it.
+func (h *cacheHandler) getShared(ctx context.Context, id resource.ID, opts ...state.GetOption) (resource.Resource, error) {
 	if len(opts) > 0 {
 		return nil, fmt.Errorf("cached get doesn't support options")
 	}
@@ -71,6 +186,10 @@ func (h *cacheHandler) get(ctx context.Context, id resource.ID, opts ...state.Ge
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if h.overflowed {
+		return nil, errOverflowed
+	}
+
 	idx, found := slices.BinarySearchFunc(h.resources, id, func(r resource.Resource, id resource.ID) int {
 		return cmp.Compare(r.Metadata().ID(), id)
 	})
@@ -79,8 +198,7 @@ func (h *cacheHandler) get(ctx context.Context, id resource.ID, opts ...state.Ge
 		return nil, ErrNotFound(resource.NewMetadata(h.key.Namespace, h.key.Type, id, resource.VersionUndefined))
 	}
 
-	// return a copy of the resource to satisfy State semantics
-	return h.resources[idx].DeepCopy(), nil
+	return h.resources[idx], nil
 }
 
 func (h *cacheHandler) contextWithTeardown(ctx context.Context, id resource.ID) (context.Context, error) {
@@ -95,6 +213,10 @@ func (h *cacheHandler) contextWithTeardown(ctx context.Context, id resource.ID)
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if h.overflowed {
+		return nil, errOverflowed
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	idx, found := slices.BinarySearchFunc(h.resources, id, func(r resource.Resource, id resource.ID) int {
@@ -138,10 +260,38 @@ func (h *cacheHandler) contextWithTeardown(ctx context.Context, id resource.ID)
 }
 
 func (h *cacheHandler) list(ctx context.Context, opts ...state.ListOption) (resource.List, error) {
+	resources, err := h.listShared(ctx, opts...)
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	// return a copy of the resource to satisfy State semantics
+	return resource.List{
+		Items: xslices.Map(resources, resource.Resource.DeepCopy),
+	}, nil
+}
+
+func (h *cacheHandler) listReadOnly(ctx context.Context, opts ...state.ListOption) (resource.List, error) {
+	resources, err := h.listShared(ctx, opts...)
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	if err = h.checkReadOnly(resources); err != nil {
+		return resource.List{}, err
+	}
+
+	return resource.List{
+		Items: resources,
+	}, nil
+}
+
+// listShared returns the cached resources without copying them.
+func (h *cacheHandler) listShared(ctx context.Context, opts ...state.ListOption) ([]resource.Resource, error) {
 	// wait for bootstrap
 	select {
 	case <-ctx.Done():
-		return resource.List{}, ctx.Err()
+		return nil, ctx.Err()
 	case <-h.bootstrapped:
 	}
 
@@ -152,21 +302,70 @@ func (h *cacheHandler) list(ctx context.Context, opts ...state.ListOption) (reso
 	}
 
 	if !value.IsZero(options.UnmarshalOptions) {
-		return resource.List{}, fmt.Errorf("cached list doesn't support unmarshal options")
+		return nil, fmt.Errorf("cached list doesn't support unmarshal options")
+	}
+
+	if h.metadataOnly && resource.FilterUsesSpec(options.Filter) {
+		return nil, fmt.Errorf("metadata-only cached list doesn't support filters on the spec")
 	}
 
 	// create a copy of the list while locked to allow concurrent reads/updates
 	h.mu.Lock()
-	resources := slices.Clone(h.resources)
+	resources, overflowed := slices.Clone(h.resources), h.overflowed
 	h.mu.Unlock()
 
+	if overflowed {
+		return nil, errOverflowed
+	}
+
 	// micro optimization: apply filter only if some filters are specified
-	if !value.IsZero(options.IDQuery) || options.LabelQueries != nil {
+	if !value.IsZero(options.IDQuery) || options.LabelQueries != nil || options.Filter != nil {
 		resources = xslices.Filter(resources, func(r resource.Resource) bool {
-			return options.IDQuery.Matches(*r.Metadata()) && options.LabelQueries.Matches(*r.Metadata().Labels())
+			return options.IDQuery.Matches(*r.Metadata()) && options.LabelQueries.Matches(*r.Metadata().Labels()) && resource.MatchesFilter(options.Filter, r)
+		})
+	}
+
+	return resources, nil
+}
+
+func (h *cacheHandler) listByIndex(ctx context.Context, name, key string) (resource.List, error) {
+	// wait for bootstrap
+	select {
+	case <-ctx.Done():
+		return resource.List{}, ctx.Err()
+	case <-h.bootstrapped:
+	}
+
+	h.mu.Lock()
+
+	if h.overflowed {
+		h.mu.Unlock()
+
+		return resource.List{}, errOverflowed
+	}
+
+	index, ok := h.indexes[name]
+	if !ok {
+		h.mu.Unlock()
+
+		return resource.List{}, fmt.Errorf("index %q is not defined for %s/%s", name, h.key.Namespace, h.key.Type)
+	}
+
+	ids := index.lookup(key)
+	resources := make([]resource.Resource, 0, len(ids))
+
+	for _, id := range ids {
+		idx, found := slices.BinarySearchFunc(h.resources, id, func(r resource.Resource, id resource.ID) int {
+			return cmp.Compare(r.Metadata().ID(), id)
 		})
+
+		if found {
+			resources = append(resources, h.resources[idx])
+		}
 	}
 
+	h.mu.Unlock()
+
 	// return a copy of the resource to satisfy State semantics
 	return resource.List{
 		Items: xslices.Map(resources, resource.Resource.DeepCopy),
@@ -175,28 +374,69 @@ func (h *cacheHandler) list(ctx context.Context, opts ...state.ListOption) (reso
 
 func (h *cacheHandler) append(r resource.Resource) {
 	h.mu.Lock()
-	h.resources = append(h.resources, r)
-	h.mu.Unlock()
+	defer h.mu.Unlock()
+
+	if h.overflowed {
+		h.overflowIDs[r.Metadata().ID()] = struct{}{}
+
+		return
+	}
+
+	h.resources = append(h.resources, h.stored(r))
+
+	for _, index := range h.indexes {
+		index.put(r)
+	}
 
 	metrics.CachedResources.Add(r.Metadata().Type(), 1)
+
+	h.checkSizeLocked()
 }
 
 func (h *cacheHandler) put(r resource.Resource) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	h.putLocked(r)
+}
+
+func (h *cacheHandler) putLocked(r resource.Resource) {
+	if h.overflowed && !h.recovering {
+		h.overflowIDs[r.Metadata().ID()] = struct{}{}
+
+		h.notifyTeardownLocked(r)
+
+		return
+	}
+
 	idx, found := slices.BinarySearchFunc(h.resources, r.Metadata().ID(), func(r resource.Resource, id resource.ID) int {
 		return cmp.Compare(r.Metadata().ID(), id)
 	})
 
+	h.ownResourcesLocked()
+
 	if found {
-		h.resources[idx] = r
+		if h.checker != nil {
+			h.checker.forget(h.resources[idx])
+		}
+
+		h.resources[idx] = h.stored(r)
 	} else {
-		h.resources = slices.Insert(h.resources, idx, r)
+		h.resources = slices.Insert(h.resources, idx, h.stored(r))
 
 		metrics.CachedResources.Add(r.Metadata().Type(), 1)
 	}
 
+	for _, index := range h.indexes {
+		index.put(r)
+	}
+
+	h.notifyTeardownLocked(r)
+
+	h.checkSizeLocked()
+}
+
+func (h *cacheHandler) notifyTeardownLocked(r resource.Resource) {
 	if r.Metadata().Phase() == resource.PhaseTearingDown {
 		if ch, ok := h.teardownWaiters[r.Metadata().ID()]; ok {
 			close(ch)
@@ -209,22 +449,97 @@ func (h *cacheHandler) remove(r resource.Resource) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	h.removeLocked(r)
+}
+
+func (h *cacheHandler) removeLocked(r resource.Resource) {
+	delete(h.overflowIDs, r.Metadata().ID())
+
 	idx, found := slices.BinarySearchFunc(h.resources, r.Metadata().ID(), func(r resource.Resource, id resource.ID) int {
 		return cmp.Compare(r.Metadata().ID(), id)
 	})
 
 	if found {
+		if h.checker != nil {
+			h.checker.forget(h.resources[idx])
+		}
+
+		h.ownResourcesLocked()
+
 		h.resources = slices.Delete(h.resources, idx, idx+1)
 
 		metrics.CachedResources.Add(r.Metadata().Type(), -1)
 	}
 
+	for _, index := range h.indexes {
+		index.remove(r.Metadata().ID())
+	}
+
 	if ch, ok := h.teardownWaiters[r.Metadata().ID()]; ok {
 		close(ch)
 		delete(h.teardownWaiters, r.Metadata().ID())
 	}
 }
 
+func (h *cacheHandler) beginResync() {
+	h.mu.Lock()
+	h.resynced = map[resource.ID]struct{}{}
+
+	// cache the resources again, while the reads are not served until the resync is done
+	if h.overflowed && len(h.overflowIDs) <= h.maxSize/2 {
+		h.recovering = true
+	}
+
+	h.mu.Unlock()
+}
+
+func (h *cacheHandler) resync(r resource.Resource) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.resynced != nil {
+		h.resynced[r.Metadata().ID()] = struct{}{}
+	}
+
+	h.putLocked(r)
+}
+
+func (h *cacheHandler) endResync() []resource.Resource {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.resynced == nil {
+		return nil
+	}
+
+	var stale []resource.Resource
+
+	for _, r := range h.resources {
+		if _, seen := h.resynced[r.Metadata().ID()]; !seen {
+			stale = append(stale, r)
+		}
+	}
+
+	// resources of the overflowed handler are not cached (or cached again only if resynced), so only their IDs are known
+	for id := range h.overflowIDs {
+		if _, seen := h.resynced[id]; !seen {
+			stale = append(stale, resource.NewTombstone(resource.NewMetadata(h.key.Namespace, h.key.Type, id, resource.VersionUndefined)))
+		}
+	}
+
+	for _, r := range stale {
+		h.removeLocked(r)
+	}
+
+	if h.recovering {
+		h.overflowed, h.recovering, h.overflowIDs = false, false, nil
+	}
+
+	h.resynced = nil
+
+	return stale
+}
+
 func (h *cacheHandler) len() int {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cache
+
+import (
+	"slices"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// cacheIndex maps the index keys to the IDs of the cached resources.
+//
+// cacheIndex is protected by the mutex of the cacheHandler.
+type cacheIndex struct {
+	fn options.IndexFunc
+
+	ids  map[string]map[resource.ID]struct{}
+	keys map[resource.ID][]string
+}
+
+func newCacheIndex(fn options.IndexFunc) *cacheIndex {
+	return &cacheIndex{
+		fn:   fn,
+		ids:  map[string]map[resource.ID]struct{}{},
+		keys: map[resource.ID][]string{},
+	}
+}
+
+// put (re-)indexes the resource.
+func (index *cacheIndex) put(r resource.Resource) {
+	id := r.Metadata().ID()
+
+	index.remove(id)
+
+	keys := index.fn(r)
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, key := range keys {
+		ids, ok := index.ids[key]
+		if !ok {
+			ids = map[resource.ID]struct{}{}
+			index.ids[key] = ids
+		}
+
+		ids[id] = struct{}{}
+	}
+
+	index.keys[id] = keys
+}
+
+// remove the resource from the index.
+func (index *cacheIndex) remove(id resource.ID) {
+	for _, key := range index.keys[id] {
+		delete(index.ids[key], id)
+
+		if len(index.ids[key]) == 0 {
+			delete(index.ids, key)
+		}
+	}
+
+	delete(index.keys, id)
+}
+
+// lookup returns the sorted IDs of the resources with the key.
+func (index *cacheIndex) lookup(key string) []resource.ID {
+	ids := make([]resource.ID, 0, len(index.ids[key]))
+
+	for id := range index.ids[key] {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cache
+
+import (
+	"path"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// patternHandlers handles a resource type across the namespaces matching the pattern.
+//
+// The handler of each namespace is created when the namespace is accessed for the first time,
+// all handlers share the bootstrapped channel, as the namespaces are bootstrapped with a single watch.
+//
+// Operations on the whole pattern (MarkBootstrapped, Len, BeginResync, EndResync) use the pattern as the namespace,
+// as the Bootstrapped event of the watch across namespaces carries the pattern as the namespace.
+type patternHandlers struct {
+	pattern      string
+	typ          resource.Type
+	bootstrapped chan struct{}
+
+	cached         options.CachedResource
+	readOnlyChecks bool
+
+	handlers map[resource.Namespace]*cacheHandler
+	mu       sync.Mutex
+}
+
+func newPatternHandlers(cached options.CachedResource, readOnlyChecks bool) *patternHandlers {
+	return &patternHandlers{
+		pattern:        cached.NamespacePattern,
+		typ:            cached.Type,
+		bootstrapped:   make(chan struct{}),
+		cached:         cached,
+		readOnlyChecks: readOnlyChecks,
+		handlers:       map[resource.Namespace]*cacheHandler{},
+	}
+}
+
+func (p *patternHandlers) matches(namespace resource.Namespace) bool {
+	matched, _ := path.Match(p.pattern, namespace) //nolint:errcheck // invalid pattern doesn't match anything
+
+	return matched
+}
+
+// handler returns the handler of the namespace, creating it if needed.
+func (p *patternHandlers) handler(namespace resource.Namespace) *cacheHandler {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h, ok := p.handlers[namespace]
+	if !ok {
+		h = newCacheHandler(cacheKey{Namespace: namespace, Type: p.typ}, p.cached, p.readOnlyChecks)
+		h.bootstrapped = p.bootstrapped
+
+		p.handlers[namespace] = h
+	}
+
+	return h
+}
+
+// all returns the handlers of all namespaces accessed so far.
+func (p *patternHandlers) all() []*cacheHandler {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	handlers := make([]*cacheHandler, 0, len(p.handlers))
+
+	for _, h := range p.handlers {
+		handlers = append(handlers, h)
+	}
+
+	return handlers
+}
+
+func (p *patternHandlers) isBootstrapped() bool {
+	select {
+	case <-p.bootstrapped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *patternHandlers) markBootstrapped() {
+	// the cache might be bootstrapped again after the resync
+	if !p.isBootstrapped() {
+		close(p.bootstrapped)
+	}
+}
+
+func (p *patternHandlers) len() int {
+	var n int
+
+	for _, h := range p.all() {
+		n += h.len()
+	}
+
+	return n
+}
+
+func (p *patternHandlers) beginResync() {
+	for _, h := range p.all() {
+		h.beginResync()
+	}
+}
+
+func (p *patternHandlers) endResync() []resource.Resource {
+	var removed []resource.Resource
+
+	for _, h := range p.all() {
+		removed = append(removed, h.endResync()...)
+	}
+
+	return removed
+}
+
+func listNamespacePattern(opts []state.ListOption) string {
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options.NamespacePattern
+}
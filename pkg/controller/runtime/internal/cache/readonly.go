@@ -0,0 +1,125 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cache
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+type checksum [sha256.Size]byte
+
+// readOnlyChecker verifies that the resources returned by the read-only reads are not modified.
+//
+// The checksum of the resource is recorded when it is returned for the first time, and it is verified
+// on each subsequent read-only read of the resource, and when the resource is replaced or removed from the cache.
+// Once the modification is detected, all read-only reads of the resource type fail.
+//
+// readOnlyChecker is protected by the mutex of the cacheHandler.
+type readOnlyChecker struct {
+	checksums map[resource.Resource]checksum
+	err       error
+}
+
+func newReadOnlyChecker() *readOnlyChecker {
+	return &readOnlyChecker{
+		checksums: map[resource.Resource]checksum{},
+	}
+}
+
+func resourceChecksum(r resource.Resource) (checksum, error) {
+	v, err := resource.MarshalYAML(r)
+	if err != nil {
+		return checksum{}, err
+	}
+
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return checksum{}, err
+	}
+
+	return sha256.Sum256(out), nil
+}
+
+// track records the checksum of the resource, or verifies it if the resource was returned before.
+func (checker *readOnlyChecker) track(r resource.Resource) error {
+	if checker.err != nil {
+		return checker.err
+	}
+
+	sum, err := resourceChecksum(r)
+	if err != nil {
+		return fmt.Errorf("error calculating checksum of %s: %w", resource.String(r), err)
+	}
+
+	expected, ok := checker.checksums[r]
+	if !ok {
+		checker.checksums[r] = sum
+
+		return nil
+	}
+
+	if sum != expected {
+		checker.err = fmt.Errorf("cached resource %s was modified after the read-only access", resource.String(r))
+	}
+
+	return checker.err
+}
+
+// forget the resource which is no longer cached, verifying its checksum.
+func (checker *readOnlyChecker) forget(r resource.Resource) {
+	expected, ok := checker.checksums[r]
+	if !ok {
+		return
+	}
+
+	delete(checker.checksums, r)
+
+	if checker.err != nil {
+		return
+	}
+
+	if sum, err := resourceChecksum(r); err == nil && sum != expected {
+		checker.err = fmt.Errorf("cached resource %s was modified after the read-only access", resource.String(r))
+	}
+}
+
+func (h *cacheHandler) checkReadOnly(resources []resource.Resource) error {
+	if h.checker == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, r := range resources {
+		// the resource might have been replaced or removed since the read released the lock,
+		// such resource is no longer cached, so it's not tracked (otherwise its checksum would never be forgotten)
+		if !h.isCachedLocked(r) {
+			continue
+		}
+
+		if err := h.checker.track(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isCachedLocked checks if the resource is the current cached entry.
+func (h *cacheHandler) isCachedLocked(r resource.Resource) bool {
+	idx, found := slices.BinarySearchFunc(h.resources, r.Metadata().ID(), func(r resource.Resource, id resource.ID) int {
+		return cmp.Compare(r.Metadata().ID(), id)
+	})
+
+	return found && h.resources[idx] == r
+}
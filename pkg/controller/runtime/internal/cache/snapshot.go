@@ -0,0 +1,304 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cache
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/siderolabs/gen/value"
+	"github.com/siderolabs/gen/xslices"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Snapshot is a consistent view of the cached resources across the resource kinds.
+//
+// Snapshot captures the contents of the cache at a single point of the watch event stream,
+// so the reads of several kinds never observe the events out of order.
+//
+// Snapshot doesn't copy the cached resources: the cache handler marks the captured resources slice as shared,
+// and it copies the slice on the next modification (copy-on-write).
+type Snapshot struct {
+	cache   *ResourceCache
+	handled map[cacheKey]snapshotHandler
+	types   []resource.Type
+}
+
+// snapshotHandler is the captured state of a single cache handler.
+type snapshotHandler struct {
+	handler   *cacheHandler
+	resources []resource.Resource
+}
+
+// Snapshot captures the cached resources of the given types.
+//
+// Snapshot waits for the cache of the types to be bootstrapped.
+// The resource kinds which exceeded the size cap are not captured.
+func (cache *ResourceCache) Snapshot(ctx context.Context, types ...resource.Type) (*Snapshot, error) {
+	var (
+		handlers     []*cacheHandler
+		bootstrapped []chan struct{}
+	)
+
+	for key, h := range cache.handlers {
+		if slices.Contains(types, key.Type) {
+			handlers = append(handlers, h)
+			bootstrapped = append(bootstrapped, h.bootstrapped)
+		}
+	}
+
+	for _, typ := range types {
+		for _, p := range cache.patterns[typ] {
+			bootstrapped = append(bootstrapped, p.bootstrapped)
+		}
+	}
+
+	for _, ch := range bootstrapped {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ch:
+		}
+	}
+
+	snapshot := &Snapshot{
+		cache:   cache,
+		handled: make(map[cacheKey]snapshotHandler, len(handlers)),
+		types:   types,
+	}
+
+	// block the modifications of the cache while capturing the handlers
+	cache.snapshotMu.Lock()
+	defer cache.snapshotMu.Unlock()
+
+	// handlers of the patterns are only created by the reads and modifications (which are blocked)
+	for _, typ := range types {
+		for _, p := range cache.patterns[typ] {
+			handlers = append(handlers, p.all()...)
+		}
+	}
+
+	for _, h := range handlers {
+		if resources, ok := h.capture(); ok {
+			snapshot.handled[h.key] = snapshotHandler{
+				handler:   h,
+				resources: resources,
+			}
+		}
+	}
+
+	return snapshot, nil
+}
+
+// capture returns the shared resources slice, marking it as shared.
+func (h *cacheHandler) capture() ([]resource.Resource, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.overflowed {
+		return nil, false
+	}
+
+	h.shared = true
+
+	return h.resources[:len(h.resources):len(h.resources)], true
+}
+
+func (h *cacheHandler) indexFunc(name string) options.IndexFunc {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if index, ok := h.indexes[name]; ok {
+		return index.fn
+	}
+
+	return nil
+}
+
+// ownResourcesLocked copies the resources slice if it is shared with a snapshot.
+func (h *cacheHandler) ownResourcesLocked() {
+	if h.shared {
+		h.resources = slices.Clone(h.resources)
+		h.shared = false
+	}
+}
+
+func (snapshot *Snapshot) lookup(namespace resource.Namespace, resourceType resource.Type) (snapshotHandler, bool) {
+	key := cacheKey{
+		Namespace: namespace,
+		Type:      resourceType,
+	}
+
+	if handled, ok := snapshot.handled[key]; ok {
+		return handled, true
+	}
+
+	// namespaces of the patterns which were not seen by the cache when the snapshot was taken are empty
+	if slices.Contains(snapshot.types, resourceType) && snapshot.cache.matchPattern(namespace, resourceType) != nil {
+		return snapshotHandler{}, true
+	}
+
+	return snapshotHandler{}, false
+}
+
+// ServesList returns true if the resources of the kind are captured in the snapshot.
+func (snapshot *Snapshot) ServesList(namespace resource.Namespace, resourceType resource.Type) bool {
+	_, ok := snapshot.lookup(namespace, resourceType)
+
+	return ok
+}
+
+// ServesGet returns true if the full resources of the kind are captured in the snapshot.
+//
+// The index is served from the snapshot by filtering the captured resources with the index function,
+// so ListByIndex is served from the snapshot only if ServesGet is true.
+func (snapshot *Snapshot) ServesGet(namespace resource.Namespace, resourceType resource.Type) bool {
+	handled, ok := snapshot.lookup(namespace, resourceType)
+
+	return ok && (handled.handler == nil || !handled.handler.metadataOnly)
+}
+
+func (snapshot *Snapshot) getShared(ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) {
+	if len(opts) > 0 {
+		return nil, fmt.Errorf("cached get doesn't support options")
+	}
+
+	handled, ok := snapshot.lookup(ptr.Namespace(), ptr.Type())
+	if !ok {
+		return nil, fmt.Errorf("resource %s/%s is not captured in the snapshot", ptr.Namespace(), ptr.Type())
+	}
+
+	idx, found := slices.BinarySearchFunc(handled.resources, ptr.ID(), func(r resource.Resource, id resource.ID) int {
+		return cmp.Compare(r.Metadata().ID(), id)
+	})
+
+	if !found {
+		return nil, ErrNotFound(ptr)
+	}
+
+	return handled.resources[idx], nil
+}
+
+func (snapshot *Snapshot) listShared(kind resource.Kind, opts ...state.ListOption) ([]resource.Resource, error) {
+	handled, ok := snapshot.lookup(kind.Namespace(), kind.Type())
+	if !ok {
+		return nil, fmt.Errorf("resource %s/%s is not captured in the snapshot", kind.Namespace(), kind.Type())
+	}
+
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if !value.IsZero(options.UnmarshalOptions) {
+		return nil, fmt.Errorf("cached list doesn't support unmarshal options")
+	}
+
+	if handled.handler != nil && handled.handler.metadataOnly && resource.FilterUsesSpec(options.Filter) {
+		return nil, fmt.Errorf("metadata-only cached list doesn't support filters on the spec")
+	}
+
+	resources := handled.resources
+
+	if !value.IsZero(options.IDQuery) || options.LabelQueries != nil || options.Filter != nil {
+		resources = xslices.Filter(resources, func(r resource.Resource) bool {
+			return options.IDQuery.Matches(*r.Metadata()) && options.LabelQueries.Matches(*r.Metadata().Labels()) && resource.MatchesFilter(options.Filter, r)
+		})
+	}
+
+	return resources, nil
+}
+
+// Get returns a copy of the captured resource.
+func (snapshot *Snapshot) Get(ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) {
+	r, err := snapshot.getShared(ptr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DeepCopy(), nil
+}
+
+// List returns copies of the captured resources.
+func (snapshot *Snapshot) List(kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	resources, err := snapshot.listShared(kind, opts...)
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	return resource.List{
+		Items: xslices.Map(resources, resource.Resource.DeepCopy),
+	}, nil
+}
+
+// GetReadOnly returns the captured resource without copying it.
+func (snapshot *Snapshot) GetReadOnly(ptr resource.Pointer) (resource.Resource, error) {
+	r, err := snapshot.getShared(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	if h := snapshot.handled[cacheKey{Namespace: ptr.Namespace(), Type: ptr.Type()}].handler; h != nil {
+		if err = h.checkReadOnly([]resource.Resource{r}); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// ListReadOnly returns the captured resources without copying them.
+func (snapshot *Snapshot) ListReadOnly(kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	resources, err := snapshot.listShared(kind, opts...)
+	if err != nil {
+		return resource.List{}, err
+	}
+
+	if h := snapshot.handled[cacheKey{Namespace: kind.Namespace(), Type: kind.Type()}].handler; h != nil {
+		if err = h.checkReadOnly(resources); err != nil {
+			return resource.List{}, err
+		}
+	}
+
+	return resource.List{
+		Items: resources,
+	}, nil
+}
+
+// ListByIndex returns copies of the captured resources with the index key.
+func (snapshot *Snapshot) ListByIndex(kind resource.Kind, index, key string) (resource.List, error) {
+	handled, ok := snapshot.lookup(kind.Namespace(), kind.Type())
+	if !ok {
+		return resource.List{}, fmt.Errorf("resource %s/%s is not captured in the snapshot", kind.Namespace(), kind.Type())
+	}
+
+	if handled.handler == nil {
+		// namespace of the pattern which is empty in the snapshot
+		return resource.List{}, nil
+	}
+
+	indexFunc := handled.handler.indexFunc(index)
+	if indexFunc == nil {
+		return resource.List{}, fmt.Errorf("index %q is not defined for %s/%s", index, kind.Namespace(), kind.Type())
+	}
+
+	var items []resource.Resource
+
+	for _, r := range handled.resources {
+		if slices.Contains(indexFunc(r), key) {
+			items = append(items, r.DeepCopy())
+		}
+	}
+
+	return resource.List{
+		Items: items,
+	}, nil
+}
@@ -13,7 +13,8 @@ import (
 
 // stateWrapper adapts cache to the state.CoreState interface.
 //
-// Get and List operations are delegated to the cache (if the resource is cached).
+// Get and List operations are delegated to the cache (if the resource is cached), the reads fall back
+// to the underlying state if the cache exceeds the size cap.
 //
 // Watch operation is passed through the underlying state.
 // Create, Update, and Destroy operations are passed through the underlying state.
@@ -29,8 +30,11 @@ var _ state.CoreState = (*stateWrapper)(nil)
 //
 // If a resource is not found, error is returned.
 func (wrapper *stateWrapper) Get(ctx context.Context, r resource.Pointer, opts ...state.GetOption) (resource.Resource, error) {
-	if wrapper.cache.IsHandled(r.Namespace(), r.Type()) {
-		return wrapper.cache.Get(ctx, r, opts...)
+	if wrapper.cache.ServesGet(r.Namespace(), r.Type()) {
+		res, err := wrapper.cache.Get(ctx, r, opts...)
+		if !IsOverflowed(err) {
+			return res, err
+		}
 	}
 
 	return wrapper.st.Get(ctx, r, opts...)
@@ -38,8 +42,19 @@ func (wrapper *stateWrapper) Get(ctx context.Context, r resource.Pointer, opts .
 
 // List resources by type.
 func (wrapper *stateWrapper) List(ctx context.Context, r resource.Kind, opts ...state.ListOption) (resource.List, error) {
-	if wrapper.cache.IsHandled(r.Namespace(), r.Type()) {
-		return wrapper.cache.List(ctx, r, opts...)
+	var served bool
+
+	if pattern := listNamespacePattern(opts); pattern != "" {
+		served = wrapper.cache.ServesListPattern(pattern, r.Type())
+	} else {
+		served = wrapper.cache.ServesList(r.Namespace(), r.Type())
+	}
+
+	if served {
+		list, err := wrapper.cache.List(ctx, r, opts...)
+		if !IsOverflowed(err) {
+			return list, err
+		}
 	}
 
 	return wrapper.st.List(ctx, r, opts...)
@@ -49,6 +64,10 @@ func (wrapper *stateWrapper) List(ctx context.Context, r resource.Kind, opts ...
 //
 // If a resource already exists, Create returns an error.
 func (wrapper *stateWrapper) Create(ctx context.Context, r resource.Resource, opts ...state.CreateOption) error {
+	if resource.IsMetadataOnly(r) {
+		return ErrMetadataOnlyWrite(r)
+	}
+
 	return wrapper.st.Create(ctx, r, opts...)
 }
 
@@ -58,6 +77,10 @@ func (wrapper *stateWrapper) Create(ctx context.Context, r resource.Resource, op
 // On update current version of resource `new` in the state should match
 // the version on the backend, otherwise conflict error is returned.
 func (wrapper *stateWrapper) Update(ctx context.Context, newResource resource.Resource, opts ...state.UpdateOption) error {
+	if resource.IsMetadataOnly(newResource) {
+		return ErrMetadataOnlyWrite(newResource)
+	}
+
 	return wrapper.st.Update(ctx, newResource, opts...)
 }
 
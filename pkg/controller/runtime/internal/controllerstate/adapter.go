@@ -7,17 +7,26 @@ package controllerstate
 
 import (
 	"context"
+	"expvar"
 	"fmt"
+	"path"
+	"slices"
+	"time"
 
 	"github.com/siderolabs/gen/optional"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 
 	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/dryrun"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/cache"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/owned"
+	"github.com/cosi-project/runtime/pkg/tracing"
 )
 
 // StateAdapter implements filtered access to the resource state by controller inputs/outputs.
@@ -29,18 +38,40 @@ type StateAdapter struct {
 	Name       string
 
 	UpdateLimiter *rate.Limiter
-	Logger        *zap.Logger
+	// ReadLimiter (optional) limits the reads which are not served from the cache.
+	ReadLimiter *rate.Limiter
+	Logger      *zap.Logger
 
 	Inputs  []controller.Input
 	Outputs []controller.Output
 
+	// Tracer creates spans for reads and writes, if not set, tracing is disabled.
+	Tracer trace.Tracer
+	// TraceContext (optional) returns the context used as a parent for the spans.
+	TraceContext func(ctx context.Context) context.Context
+	// TraceRegistry (optional) records the trace context of the writes.
+	TraceRegistry *tracing.Registry
+
+	// Gate blocks writes while the controller is paused.
+	Gate Gate
+
+	// DryRun (optional) is the overlay which OwnedState writes to in dry-run mode.
+	DryRun *dryrun.Overlay
+
+	// CachedResources is used to look up the index functions if the index can't be served from the cache.
+	CachedResources []options.CachedResource
+
 	WarnOnUncachedReads bool
+	MetricsEnabled      bool
 }
 
 // Check interfaces.
 var (
-	_ controller.Reader = (*StateAdapter)(nil)
-	_ controller.Writer = (*StateAdapter)(nil)
+	_ controller.Reader         = (*StateAdapter)(nil)
+	_ controller.IndexReader    = (*StateAdapter)(nil)
+	_ controller.ReadOnlyReader = (*StateAdapter)(nil)
+	_ controller.SnapshotReader = (*StateAdapter)(nil)
+	_ controller.Writer         = (*StateAdapter)(nil)
 )
 
 func (adapter *StateAdapter) isOutput(resourceType resource.Type) bool {
@@ -60,7 +91,7 @@ func (adapter *StateAdapter) checkReadAccess(resourceNamespace resource.Namespac
 
 	// go over cached dependencies here
 	for _, dep := range adapter.Inputs {
-		if dep.Namespace == resourceNamespace && dep.Type == resourceType {
+		if dep.Matches(resourceNamespace, resourceType) {
 			// any ID is allowed
 			if !dep.ID.IsPresent() {
 				return nil
@@ -83,7 +114,7 @@ func (adapter *StateAdapter) checkReadAccess(resourceNamespace resource.Namespac
 func (adapter *StateAdapter) checkFinalizerAccess(resourceNamespace resource.Namespace, resourceType resource.Type, resourceID resource.ID) error {
 	// go over cached dependencies here
 	for _, dep := range adapter.Inputs {
-		if dep.Namespace == resourceNamespace && dep.Type == resourceType && (dep.Kind == controller.InputStrong || dep.Kind == controller.InputQPrimary || dep.Kind == controller.InputQMapped) {
+		if dep.Matches(resourceNamespace, resourceType) && (dep.Kind == controller.InputStrong || dep.Kind == controller.InputQPrimary || dep.Kind == controller.InputQMapped) {
 			// any ID is allowed
 			if !dep.ID.IsPresent() {
 				return nil
@@ -98,14 +129,88 @@ func (adapter *StateAdapter) checkFinalizerAccess(resourceNamespace resource.Nam
 	return fmt.Errorf("attempt to change finalizers for resource %q/%q, not an input with Strong dependency for controller %q", resourceNamespace, resourceType, adapter.Name)
 }
 
+func (adapter *StateAdapter) startSpan(ctx context.Context, name string, ns resource.Namespace, typ resource.Type, id resource.ID) (context.Context, trace.Span) { //nolint:ireturn
+	if adapter.Tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	if adapter.TraceContext != nil {
+		ctx = adapter.TraceContext(ctx)
+	}
+
+	return adapter.Tracer.Start(ctx, "controller."+name,
+		trace.WithAttributes(tracing.AttributeController.String(adapter.Name)),
+		trace.WithAttributes(tracing.ResourceAttributes(ns, typ, id)...),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if state.IsNotFoundError(err) {
+		err = nil
+	}
+
+	tracing.End(span, err)
+}
+
+// DryRunPlan implements adapter.DryRunAdapter interface.
+func (adapter *StateAdapter) DryRunPlan() ([]dryrun.Change, error) {
+	if adapter.DryRun == nil {
+		return nil, fmt.Errorf("controller %q is not running in dry-run mode", adapter.Name)
+	}
+
+	return adapter.DryRun.Plan()
+}
+
+// DryRunReset implements adapter.DryRunAdapter interface.
+func (adapter *StateAdapter) DryRunReset() error {
+	if adapter.DryRun == nil {
+		return fmt.Errorf("controller %q is not running in dry-run mode", adapter.Name)
+	}
+
+	adapter.DryRun.Reset()
+
+	return nil
+}
+
+// throttle waits for the rate limiter (if set), and records the throttled time.
+func (adapter *StateAdapter) throttle(ctx context.Context, limiter *rate.Limiter, throttled *expvar.Map) error {
+	if limiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+
+	err := limiter.Wait(ctx)
+
+	if adapter.MetricsEnabled {
+		if waited := time.Since(start); waited > 0 {
+			throttled.AddFloat(adapter.Name, waited.Seconds())
+		}
+	}
+
+	return err
+}
+
 // Get implements controller.Runtime interface.
 func (adapter *StateAdapter) Get(ctx context.Context, resourcePointer resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
-	return adapter.get(ctx, false, resourcePointer, opts...)
+	ctx, span := adapter.startSpan(ctx, "Get", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+
+	r, err := adapter.get(ctx, false, resourcePointer, opts...)
+
+	endSpan(span, err)
+
+	return r, err
 }
 
 // GetUncached implements controller.Runtime interface.
 func (adapter *StateAdapter) GetUncached(ctx context.Context, resourcePointer resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
-	return adapter.get(ctx, true, resourcePointer, opts...)
+	ctx, span := adapter.startSpan(ctx, "GetUncached", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+
+	r, err := adapter.get(ctx, true, resourcePointer, opts...)
+
+	endSpan(span, err)
+
+	return r, err
 }
 
 func (adapter *StateAdapter) get(ctx context.Context, disableCache bool, resourcePointer resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
@@ -113,52 +218,259 @@ func (adapter *StateAdapter) get(ctx context.Context, disableCache bool, resourc
 		return nil, err
 	}
 
-	if cacheHandled := adapter.Cache.IsHandled(resourcePointer.Namespace(), resourcePointer.Type()); cacheHandled && !disableCache {
-		return adapter.Cache.Get(ctx, resourcePointer, opts...)
+	if cacheHandled := adapter.Cache.ServesGet(resourcePointer.Namespace(), resourcePointer.Type()); cacheHandled && !disableCache {
+		r, err := adapter.Cache.Get(ctx, resourcePointer, opts...)
+		if !cache.IsOverflowed(err) {
+			return r, err
+		}
+
+		// the cache exceeded the size cap while the read was in progress
 	}
 
 	if adapter.WarnOnUncachedReads {
 		adapter.Logger.Warn("get uncached resource", zap.String("namespace", resourcePointer.Namespace()), zap.String("type", resourcePointer.Type()), zap.String("id", resourcePointer.ID()))
 	}
 
+	if err := adapter.throttle(ctx, adapter.ReadLimiter, metrics.ControllerReadThrottled); err != nil {
+		return nil, fmt.Errorf("get rate limited: %w", err)
+	}
+
 	return adapter.OwnedState.Get(ctx, resourcePointer, opts...)
 }
 
 // List implements controller.Runtime interface.
 func (adapter *StateAdapter) List(ctx context.Context, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
-	return adapter.list(ctx, false, resourceKind, opts...)
+	ctx, span := adapter.startSpan(ctx, "List", resourceKind.Namespace(), resourceKind.Type(), "")
+
+	list, err := adapter.list(ctx, false, resourceKind, opts...)
+
+	endSpan(span, err)
+
+	return list, err
 }
 
 // ListUncached implements controller.Runtime interface.
 func (adapter *StateAdapter) ListUncached(ctx context.Context, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
-	return adapter.list(ctx, true, resourceKind, opts...)
+	ctx, span := adapter.startSpan(ctx, "ListUncached", resourceKind.Namespace(), resourceKind.Type(), "")
+
+	list, err := adapter.list(ctx, true, resourceKind, opts...)
+
+	endSpan(span, err)
+
+	return list, err
 }
 
 // List implements controller.Runtime interface.
 func (adapter *StateAdapter) list(ctx context.Context, disableCache bool, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if pattern := listNamespacePattern(opts); pattern != "" {
+		return adapter.listPattern(ctx, disableCache, false, pattern, resourceKind, opts...)
+	}
+
 	if err := adapter.checkReadAccess(resourceKind.Namespace(), resourceKind.Type(), optional.None[resource.ID]()); err != nil {
 		return resource.List{}, err
 	}
 
-	if cacheHandled := adapter.Cache.IsHandled(resourceKind.Namespace(), resourceKind.Type()); cacheHandled && !disableCache {
-		return adapter.Cache.List(ctx, resourceKind, opts...)
+	if cacheHandled := adapter.Cache.ServesList(resourceKind.Namespace(), resourceKind.Type()); cacheHandled && !disableCache {
+		list, err := adapter.Cache.List(ctx, resourceKind, opts...)
+		if !cache.IsOverflowed(err) {
+			return list, err
+		}
+
+		// the cache exceeded the size cap while the read was in progress
 	}
 
 	if adapter.WarnOnUncachedReads {
 		adapter.Logger.Warn("list uncached resource", zap.String("namespace", resourceKind.Namespace()), zap.String("type", resourceKind.Type()))
 	}
 
+	if err := adapter.throttle(ctx, adapter.ReadLimiter, metrics.ControllerReadThrottled); err != nil {
+		return resource.List{}, fmt.Errorf("list rate limited: %w", err)
+	}
+
+	return adapter.OwnedState.List(ctx, resourceKind, opts...)
+}
+
+// listPattern lists the resources across the namespaces matching the pattern.
+//
+// The controller should have an input with the same namespace pattern (or the resource type should be an output).
+func (adapter *StateAdapter) listPattern(ctx context.Context, disableCache, readOnly bool, pattern string, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if !adapter.isOutput(resourceKind.Type()) && !slices.ContainsFunc(adapter.Inputs, func(dep controller.Input) bool {
+		return dep.NamespacePattern == pattern && dep.Type == resourceKind.Type()
+	}) {
+		return resource.List{}, fmt.Errorf("attempt to query resource %q/%q, not input or output for controller %q", pattern, resourceKind.Type(), adapter.Name)
+	}
+
+	if adapter.Cache.ServesListPattern(pattern, resourceKind.Type()) && !disableCache {
+		var (
+			list resource.List
+			err  error
+		)
+
+		if readOnly {
+			list, err = adapter.Cache.ListReadOnly(ctx, resourceKind, opts...)
+		} else {
+			list, err = adapter.Cache.List(ctx, resourceKind, opts...)
+		}
+
+		if !cache.IsOverflowed(err) {
+			return list, err
+		}
+
+		// the cache exceeded the size cap while the read was in progress
+	}
+
+	if adapter.WarnOnUncachedReads {
+		adapter.Logger.Warn("list uncached resource", zap.String("namespace", pattern), zap.String("type", resourceKind.Type()))
+	}
+
+	if err := adapter.throttle(ctx, adapter.ReadLimiter, metrics.ControllerReadThrottled); err != nil {
+		return resource.List{}, fmt.Errorf("list rate limited: %w", err)
+	}
+
 	return adapter.OwnedState.List(ctx, resourceKind, opts...)
 }
 
+func listNamespacePattern(opts []state.ListOption) string {
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options.NamespacePattern
+}
+
+// GetReadOnly implements controller.ReadOnlyReader interface.
+func (adapter *StateAdapter) GetReadOnly(ctx context.Context, resourcePointer resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	ctx, span := adapter.startSpan(ctx, "GetReadOnly", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+
+	r, err := adapter.getReadOnly(ctx, resourcePointer)
+
+	endSpan(span, err)
+
+	return r, err
+}
+
+func (adapter *StateAdapter) getReadOnly(ctx context.Context, resourcePointer resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	if !adapter.Cache.ServesGet(resourcePointer.Namespace(), resourcePointer.Type()) {
+		// uncached reads return a fresh copy anyway
+		return adapter.get(ctx, false, resourcePointer)
+	}
+
+	if err := adapter.checkReadAccess(resourcePointer.Namespace(), resourcePointer.Type(), optional.Some(resourcePointer.ID())); err != nil {
+		return nil, err
+	}
+
+	r, err := adapter.Cache.GetReadOnly(ctx, resourcePointer)
+	if cache.IsOverflowed(err) {
+		// the cache exceeded the size cap while the read was in progress
+		return adapter.get(ctx, true, resourcePointer)
+	}
+
+	return r, err
+}
+
+// ListReadOnly implements controller.ReadOnlyReader interface.
+func (adapter *StateAdapter) ListReadOnly(ctx context.Context, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	ctx, span := adapter.startSpan(ctx, "ListReadOnly", resourceKind.Namespace(), resourceKind.Type(), "")
+
+	list, err := adapter.listReadOnly(ctx, resourceKind, opts...)
+
+	endSpan(span, err)
+
+	return list, err
+}
+
+func (adapter *StateAdapter) listReadOnly(ctx context.Context, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if pattern := listNamespacePattern(opts); pattern != "" {
+		return adapter.listPattern(ctx, false, true, pattern, resourceKind, opts...)
+	}
+
+	if !adapter.Cache.ServesList(resourceKind.Namespace(), resourceKind.Type()) {
+		// uncached reads return a fresh copy anyway
+		return adapter.list(ctx, false, resourceKind, opts...)
+	}
+
+	if err := adapter.checkReadAccess(resourceKind.Namespace(), resourceKind.Type(), optional.None[resource.ID]()); err != nil {
+		return resource.List{}, err
+	}
+
+	list, err := adapter.Cache.ListReadOnly(ctx, resourceKind, opts...)
+	if cache.IsOverflowed(err) {
+		// the cache exceeded the size cap while the read was in progress
+		return adapter.list(ctx, true, resourceKind, opts...)
+	}
+
+	return list, err
+}
+
+// ListByIndex implements controller.IndexReader interface.
+func (adapter *StateAdapter) ListByIndex(ctx context.Context, resourceKind resource.Kind, index, key string) (resource.List, error) {
+	ctx, span := adapter.startSpan(ctx, "ListByIndex", resourceKind.Namespace(), resourceKind.Type(), "")
+
+	list, err := adapter.listByIndex(ctx, resourceKind, index, key)
+
+	endSpan(span, err)
+
+	return list, err
+}
+
+func (adapter *StateAdapter) listByIndex(ctx context.Context, resourceKind resource.Kind, index, key string) (resource.List, error) {
+	if err := adapter.checkReadAccess(resourceKind.Namespace(), resourceKind.Type(), optional.None[resource.ID]()); err != nil {
+		return resource.List{}, err
+	}
+
+	if adapter.Cache.ServesList(resourceKind.Namespace(), resourceKind.Type()) {
+		list, err := adapter.Cache.ListByIndex(ctx, resourceKind, index, key)
+		if !cache.IsOverflowed(err) {
+			return list, err
+		}
+
+		// the cache exceeded the size cap while the read was in progress
+	}
+
+	// the cache is bypassed (e.g. in dry-run mode, or the cache exceeded the size cap), so filter the full list with the index function
+	var indexFunc options.IndexFunc
+
+	for _, cached := range adapter.CachedResources {
+		if cached.Type != resourceKind.Type() {
+			continue
+		}
+
+		if cached.Namespace == resourceKind.Namespace() || cached.NamespacePattern != "" && matchNamespace(cached.NamespacePattern, resourceKind.Namespace()) {
+			indexFunc = cached.Indexes[index]
+		}
+	}
+
+	if indexFunc == nil {
+		return resource.List{}, fmt.Errorf("index %q is not defined for %s/%s", index, resourceKind.Namespace(), resourceKind.Type())
+	}
+
+	list, err := adapter.list(ctx, true, resourceKind)
+	if err != nil {
+		return list, err
+	}
+
+	list.Items = slices.DeleteFunc(list.Items, func(r resource.Resource) bool {
+		return !slices.Contains(indexFunc(r), key)
+	})
+
+	return list, nil
+}
+
 // ContextWithTeardown implements controller.Runtime interface.
 func (adapter *StateAdapter) ContextWithTeardown(ctx context.Context, resourcePointer resource.Pointer) (context.Context, error) {
 	if err := adapter.checkReadAccess(resourcePointer.Namespace(), resourcePointer.Type(), optional.Some(resourcePointer.ID())); err != nil {
 		return nil, err
 	}
 
-	if cacheHandled := adapter.Cache.IsHandled(resourcePointer.Namespace(), resourcePointer.Type()); cacheHandled {
-		return adapter.Cache.ContextWithTeardown(ctx, resourcePointer)
+	if cacheHandled := adapter.Cache.ServesList(resourcePointer.Namespace(), resourcePointer.Type()); cacheHandled {
+		teardownCtx, err := adapter.Cache.ContextWithTeardown(ctx, resourcePointer)
+		if !cache.IsOverflowed(err) {
+			return teardownCtx, err
+		}
+
+		// the cache exceeded the size cap while the read was in progress
 	}
 
 	if adapter.WarnOnUncachedReads {
@@ -169,8 +481,17 @@ func (adapter *StateAdapter) ContextWithTeardown(ctx context.Context, resourcePo
 }
 
 // Create implements controller.Runtime interface.
-func (adapter *StateAdapter) Create(ctx context.Context, r resource.Resource, options ...controller.CreateOption) error {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+func (adapter *StateAdapter) Create(ctx context.Context, r resource.Resource, options ...controller.CreateOption) (err error) {
+	ctx, span := adapter.startSpan(ctx, "Create", r.Metadata().Namespace(), r.Metadata().Type(), r.Metadata().ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return fmt.Errorf("create paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return fmt.Errorf("create rate limited: %w", err)
 	}
 
@@ -179,12 +500,30 @@ func (adapter *StateAdapter) Create(ctx context.Context, r resource.Resource, op
 			r.Metadata().Namespace(), r.Metadata().Type(), adapter.Name, r.Metadata().ID())
 	}
 
-	return adapter.OwnedState.Create(ctx, r, options...)
+	if resource.IsMetadataOnly(r) {
+		return cache.ErrMetadataOnlyWrite(r)
+	}
+
+	commit := adapter.TraceRegistry.Record(ctx, r.Metadata())
+
+	err = adapter.OwnedState.Create(ctx, r, options...)
+	commit(r.Metadata().Version(), err)
+
+	return err
 }
 
 // Update implements controller.Runtime interface.
-func (adapter *StateAdapter) Update(ctx context.Context, newResource resource.Resource) error {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+func (adapter *StateAdapter) Update(ctx context.Context, newResource resource.Resource) (err error) {
+	ctx, span := adapter.startSpan(ctx, "Update", newResource.Metadata().Namespace(), newResource.Metadata().Type(), newResource.Metadata().ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return fmt.Errorf("update paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return fmt.Errorf("update rate limited: %w", err)
 	}
 
@@ -193,7 +532,16 @@ func (adapter *StateAdapter) Update(ctx context.Context, newResource resource.Re
 			newResource.Metadata().Namespace(), newResource.Metadata().Type(), adapter.Name, newResource.Metadata().ID())
 	}
 
-	return adapter.OwnedState.Update(ctx, newResource)
+	if resource.IsMetadataOnly(newResource) {
+		return cache.ErrMetadataOnlyWrite(newResource)
+	}
+
+	commit := adapter.TraceRegistry.Record(ctx, newResource.Metadata())
+
+	err = adapter.OwnedState.Update(ctx, newResource)
+	commit(newResource.Metadata().Version(), err)
+
+	return err
 }
 
 // Modify implements controller.Runtime interface.
@@ -214,8 +562,17 @@ func (adapter *StateAdapter) ModifyWithResult(
 
 func (adapter *StateAdapter) modify(
 	ctx context.Context, emptyResource resource.Resource, updateFunc func(resource.Resource) error, options ...controller.ModifyOption,
-) (resource.Resource, error) {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+) (result resource.Resource, err error) {
+	ctx, span := adapter.startSpan(ctx, "Modify", emptyResource.Metadata().Namespace(), emptyResource.Metadata().Type(), emptyResource.Metadata().ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return nil, fmt.Errorf("modify paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return nil, fmt.Errorf("modify rate limited: %w", err)
 	}
 
@@ -224,12 +581,57 @@ func (adapter *StateAdapter) modify(
 			emptyResource.Metadata().Namespace(), emptyResource.Metadata().Type(), adapter.Name, emptyResource.Metadata().ID())
 	}
 
+	if adapter.TraceRegistry != nil {
+		var commit func(resource.Version, error)
+
+		origUpdateFunc := updateFunc
+
+		// record the trace context only if the resource is created or actually changed, as otherwise there is no write
+		updateFunc = func(r resource.Resource) error {
+			created := r.Metadata().Version().Equal(resource.VersionUndefined)
+			orig := r.DeepCopy()
+
+			if err := origUpdateFunc(r); err != nil {
+				return err
+			}
+
+			if created || !resource.Equal(orig, r) {
+				commit = adapter.TraceRegistry.Record(ctx, r.Metadata())
+			}
+
+			return nil
+		}
+
+		defer func() {
+			if commit != nil {
+				commit(resultVersion(result), err)
+			}
+		}()
+	}
+
 	return adapter.OwnedState.ModifyWithResult(ctx, emptyResource, updateFunc, options...)
 }
 
+func resultVersion(r resource.Resource) resource.Version {
+	if r == nil {
+		return resource.VersionUndefined
+	}
+
+	return r.Metadata().Version()
+}
+
 // AddFinalizer implements controller.Runtime interface.
-func (adapter *StateAdapter) AddFinalizer(ctx context.Context, resourcePointer resource.Pointer, fins ...resource.Finalizer) error {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+func (adapter *StateAdapter) AddFinalizer(ctx context.Context, resourcePointer resource.Pointer, fins ...resource.Finalizer) (err error) {
+	ctx, span := adapter.startSpan(ctx, "AddFinalizer", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return fmt.Errorf("add finalizer paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return fmt.Errorf("add finalizer rate limited: %w", err)
 	}
 
@@ -241,8 +643,17 @@ func (adapter *StateAdapter) AddFinalizer(ctx context.Context, resourcePointer r
 }
 
 // RemoveFinalizer implements controller.Runtime interface.
-func (adapter *StateAdapter) RemoveFinalizer(ctx context.Context, resourcePointer resource.Pointer, fins ...resource.Finalizer) error {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+func (adapter *StateAdapter) RemoveFinalizer(ctx context.Context, resourcePointer resource.Pointer, fins ...resource.Finalizer) (err error) {
+	ctx, span := adapter.startSpan(ctx, "RemoveFinalizer", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return fmt.Errorf("remove finalizer paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return fmt.Errorf("remove finalizer rate limited: %w", err)
 	}
 
@@ -250,7 +661,7 @@ func (adapter *StateAdapter) RemoveFinalizer(ctx context.Context, resourcePointe
 		return err
 	}
 
-	err := adapter.OwnedState.RemoveFinalizer(ctx, resourcePointer, fins...)
+	err = adapter.OwnedState.RemoveFinalizer(ctx, resourcePointer, fins...)
 	if state.IsNotFoundError(err) {
 		err = nil
 	}
@@ -259,8 +670,17 @@ func (adapter *StateAdapter) RemoveFinalizer(ctx context.Context, resourcePointe
 }
 
 // Teardown implements controller.Runtime interface.
-func (adapter *StateAdapter) Teardown(ctx context.Context, resourcePointer resource.Pointer, opOpts ...controller.DeleteOption) (bool, error) {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+func (adapter *StateAdapter) Teardown(ctx context.Context, resourcePointer resource.Pointer, opOpts ...controller.DeleteOption) (_ bool, err error) {
+	ctx, span := adapter.startSpan(ctx, "Teardown", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return false, fmt.Errorf("teardown paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return false, fmt.Errorf("teardown rate limited: %w", err)
 	}
 
@@ -272,8 +692,17 @@ func (adapter *StateAdapter) Teardown(ctx context.Context, resourcePointer resou
 }
 
 // Destroy implements controller.Runtime interface.
-func (adapter *StateAdapter) Destroy(ctx context.Context, resourcePointer resource.Pointer, opOpts ...controller.DeleteOption) error {
-	if err := adapter.UpdateLimiter.Wait(ctx); err != nil {
+func (adapter *StateAdapter) Destroy(ctx context.Context, resourcePointer resource.Pointer, opOpts ...controller.DeleteOption) (err error) {
+	ctx, span := adapter.startSpan(ctx, "Destroy", resourcePointer.Namespace(), resourcePointer.Type(), resourcePointer.ID())
+	defer func() { endSpan(span, err) }()
+
+	if err := adapter.Gate.Enter(ctx); err != nil {
+		return fmt.Errorf("destroy paused: %w", err)
+	}
+
+	defer adapter.Gate.Leave()
+
+	if err := adapter.throttle(ctx, adapter.UpdateLimiter, metrics.ControllerWriteThrottled); err != nil {
 		return fmt.Errorf("destroy finalizer rate limited: %w", err)
 	}
 
@@ -283,3 +712,19 @@ func (adapter *StateAdapter) Destroy(ctx context.Context, resourcePointer resour
 
 	return adapter.OwnedState.Destroy(ctx, resourcePointer, opOpts...)
 }
+
+// Pause blocks controller writes, waiting for in-flight writes to finish.
+func (adapter *StateAdapter) Pause(ctx context.Context) error {
+	return adapter.Gate.Pause(ctx)
+}
+
+// Resume unblocks controller writes.
+func (adapter *StateAdapter) Resume() {
+	adapter.Gate.Resume()
+}
+
+func matchNamespace(pattern string, namespace resource.Namespace) bool {
+	matched, _ := path.Match(pattern, namespace) //nolint:errcheck // invalid pattern doesn't match anything
+
+	return matched
+}
@@ -0,0 +1,113 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllerstate
+
+import (
+	"context"
+	"sync"
+)
+
+// Gate blocks controller writes while paused.
+//
+// Zero value of Gate is ready to use (not paused).
+type Gate struct {
+	resumed chan struct{} // closed on resume
+	idle    chan struct{} // closed when there are no in-flight writes left
+
+	mu       sync.Mutex
+	inflight int
+	paused   bool
+}
+
+// Enter waits for the gate to be open and marks a write as in-flight.
+//
+// If Enter returns nil, Leave should be called once the write is done.
+func (gate *Gate) Enter(ctx context.Context) error {
+	for {
+		gate.mu.Lock()
+
+		if !gate.paused {
+			gate.inflight++
+			gate.mu.Unlock()
+
+			return nil
+		}
+
+		resumed := gate.resumed
+
+		gate.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-resumed:
+		}
+	}
+}
+
+// Leave marks an in-flight write as finished.
+func (gate *Gate) Leave() {
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+
+	gate.inflight--
+
+	if gate.inflight == 0 && gate.idle != nil {
+		close(gate.idle)
+		gate.idle = nil
+	}
+}
+
+// Pause closes the gate and waits for in-flight writes to finish.
+//
+// If the context is canceled while waiting, the gate stays paused.
+func (gate *Gate) Pause(ctx context.Context) error {
+	gate.mu.Lock()
+
+	if !gate.paused {
+		gate.paused = true
+		gate.resumed = make(chan struct{})
+	}
+
+	if gate.inflight == 0 {
+		gate.mu.Unlock()
+
+		return nil
+	}
+
+	if gate.idle == nil {
+		gate.idle = make(chan struct{})
+	}
+
+	idle := gate.idle
+
+	gate.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-idle:
+		return nil
+	}
+}
+
+// Resume opens the gate.
+func (gate *Gate) Resume() {
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+
+	if gate.paused {
+		gate.paused = false
+		close(gate.resumed)
+	}
+}
+
+// Paused returns true if the gate is paused.
+func (gate *Gate) Paused() bool {
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+
+	return gate.paused
+}
@@ -0,0 +1,126 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllerstate
+
+import (
+	"context"
+
+	"github.com/siderolabs/gen/optional"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/cache"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Snapshot implements controller.SnapshotReader interface.
+//
+// The snapshot captures the cached inputs and outputs of the controller.
+func (adapter *StateAdapter) Snapshot(ctx context.Context) (controller.Reader, error) { //nolint:ireturn
+	ctx, span := adapter.startSpan(ctx, "Snapshot", "", "", "")
+
+	types := make([]resource.Type, 0, len(adapter.Inputs)+len(adapter.Outputs))
+
+	for _, input := range adapter.Inputs {
+		types = append(types, input.Type)
+	}
+
+	for _, output := range adapter.Outputs {
+		types = append(types, output.Type)
+	}
+
+	snapshot, err := adapter.Cache.Snapshot(ctx, types...)
+
+	endSpan(span, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshotReader{
+		adapter:  adapter,
+		snapshot: snapshot,
+	}, nil
+}
+
+// snapshotReader serves the reads of the captured resources from the snapshot, and other reads from the adapter.
+type snapshotReader struct {
+	adapter  *StateAdapter
+	snapshot *cache.Snapshot
+}
+
+// Check interfaces.
+var (
+	_ controller.Reader         = (*snapshotReader)(nil)
+	_ controller.IndexReader    = (*snapshotReader)(nil)
+	_ controller.ReadOnlyReader = (*snapshotReader)(nil)
+)
+
+func (rdr *snapshotReader) Get(ctx context.Context, ptr resource.Pointer, opts ...state.GetOption) (resource.Resource, error) { //nolint:ireturn
+	if !rdr.snapshot.ServesGet(ptr.Namespace(), ptr.Type()) {
+		return rdr.adapter.Get(ctx, ptr, opts...)
+	}
+
+	if err := rdr.adapter.checkReadAccess(ptr.Namespace(), ptr.Type(), optional.Some(ptr.ID())); err != nil {
+		return nil, err
+	}
+
+	return rdr.snapshot.Get(ptr, opts...)
+}
+
+func (rdr *snapshotReader) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	// lists across the namespaces are not captured in the snapshot
+	if !rdr.snapshot.ServesList(kind.Namespace(), kind.Type()) || listNamespacePattern(opts) != "" {
+		return rdr.adapter.List(ctx, kind, opts...)
+	}
+
+	if err := rdr.adapter.checkReadAccess(kind.Namespace(), kind.Type(), optional.None[resource.ID]()); err != nil {
+		return resource.List{}, err
+	}
+
+	return rdr.snapshot.List(kind, opts...)
+}
+
+func (rdr *snapshotReader) GetReadOnly(ctx context.Context, ptr resource.Pointer) (resource.Resource, error) { //nolint:ireturn
+	if !rdr.snapshot.ServesGet(ptr.Namespace(), ptr.Type()) {
+		return rdr.adapter.GetReadOnly(ctx, ptr)
+	}
+
+	if err := rdr.adapter.checkReadAccess(ptr.Namespace(), ptr.Type(), optional.Some(ptr.ID())); err != nil {
+		return nil, err
+	}
+
+	return rdr.snapshot.GetReadOnly(ptr)
+}
+
+func (rdr *snapshotReader) ListReadOnly(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	if !rdr.snapshot.ServesList(kind.Namespace(), kind.Type()) || listNamespacePattern(opts) != "" {
+		return rdr.adapter.ListReadOnly(ctx, kind, opts...)
+	}
+
+	if err := rdr.adapter.checkReadAccess(kind.Namespace(), kind.Type(), optional.None[resource.ID]()); err != nil {
+		return resource.List{}, err
+	}
+
+	return rdr.snapshot.ListReadOnly(kind, opts...)
+}
+
+func (rdr *snapshotReader) ListByIndex(ctx context.Context, kind resource.Kind, index, key string) (resource.List, error) {
+	// the index is served from the snapshot by filtering the full resources
+	if !rdr.snapshot.ServesGet(kind.Namespace(), kind.Type()) {
+		return rdr.adapter.ListByIndex(ctx, kind, index, key)
+	}
+
+	if err := rdr.adapter.checkReadAccess(kind.Namespace(), kind.Type(), optional.None[resource.ID]()); err != nil {
+		return resource.List{}, err
+	}
+
+	return rdr.snapshot.ListByIndex(kind, index, key)
+}
+
+// ContextWithTeardown is not pinned to the snapshot, as it tracks the teardown of the resource.
+func (rdr *snapshotReader) ContextWithTeardown(ctx context.Context, ptr resource.Pointer) (context.Context, error) {
+	return rdr.adapter.ContextWithTeardown(ctx, ptr)
+}
@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package controllerstatus tracks the controller status and publishes it as ControllerStatus resources.
+package controllerstatus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/status"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Tracker keeps the status of a single controller.
+//
+// Tracker is safe for concurrent use, nil Tracker ignores all updates.
+type Tracker struct {
+	queueLength func() int64
+	notify      chan<- struct{}
+
+	spec status.ControllerStatusSpec
+
+	mu     sync.Mutex
+	paused bool
+}
+
+// NewTracker creates a new Tracker.
+//
+// Notify channel (optional) receives a non-blocking signal on every update.
+func NewTracker(notify chan<- struct{}) *Tracker {
+	return &Tracker{
+		notify: notify,
+		spec: status.ControllerStatusSpec{
+			State: status.StateStopped,
+		},
+	}
+}
+
+func (tracker *Tracker) update(f func(spec *status.ControllerStatusSpec)) {
+	if tracker == nil {
+		return
+	}
+
+	tracker.mu.Lock()
+	f(&tracker.spec)
+	tracker.mu.Unlock()
+
+	if tracker.notify != nil {
+		select {
+		case tracker.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetState sets the controller state.
+//
+// The backoff deadline is cleared when the controller leaves the backoff state.
+func (tracker *Tracker) SetState(state status.State) {
+	tracker.update(func(spec *status.ControllerStatusSpec) {
+		spec.State = state
+
+		if state != status.StateBackoff {
+			spec.BackoffUntil = time.Time{}
+		}
+	})
+}
+
+// Reconciled records a successful reconcile.
+func (tracker *Tracker) Reconciled() {
+	tracker.update(func(spec *status.ControllerStatusSpec) {
+		spec.LastReconcile = time.Now()
+	})
+}
+
+// Failed records a controller failure which restarts the controller, with the time of the restart.
+func (tracker *Tracker) Failed(err error, retryAt time.Time) {
+	tracker.update(func(spec *status.ControllerStatusSpec) {
+		spec.Restarts++
+		spec.LastError = err.Error()
+		spec.BackoffUntil = retryAt
+	})
+}
+
+// ReconcileFailed records a failed QController reconcile, with the time of the retry (zero if the item is not retried).
+func (tracker *Tracker) ReconcileFailed(err error, retryAt time.Time) {
+	tracker.update(func(spec *status.ControllerStatusSpec) {
+		spec.Failures++
+		spec.LastError = err.Error()
+		spec.BackoffUntil = retryAt
+	})
+}
+
+// SetPaused marks the controller as paused.
+func (tracker *Tracker) SetPaused(paused bool) {
+	tracker.update(func(*status.ControllerStatusSpec) {
+		tracker.paused = paused
+	})
+}
+
+// SetQueueLength sets the function which returns the controller queue length.
+func (tracker *Tracker) SetQueueLength(queueLength func() int64) {
+	if tracker == nil {
+		return
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.queueLength = queueLength
+}
+
+// Get returns the current status.
+func (tracker *Tracker) Get() status.ControllerStatusSpec {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	spec := tracker.spec
+
+	if tracker.paused && spec.State == status.StateRunning {
+		spec.State = status.StatePaused
+	}
+
+	if tracker.queueLength != nil {
+		spec.QueueLength = tracker.queueLength()
+	}
+
+	return spec
+}
+
+// Publisher writes ControllerStatus resources for the tracked controllers.
+type Publisher struct {
+	st        state.State
+	logger    *zap.Logger
+	published map[string]status.ControllerStatusSpec
+	namespace resource.Namespace
+
+	mu sync.Mutex
+}
+
+// NewPublisher creates a new Publisher.
+func NewPublisher(st state.State, logger *zap.Logger, namespace resource.Namespace) *Publisher {
+	return &Publisher{
+		st:        st,
+		logger:    logger,
+		namespace: namespace,
+		published: map[string]status.ControllerStatusSpec{},
+	}
+}
+
+// Publish the status of the controllers.
+//
+// Only statuses which changed since the last successful publish are written.
+// Trackers function is called with the publisher locked, so that the statuses of removed controllers
+// are not published again.
+func (publisher *Publisher) Publish(ctx context.Context, trackers func() map[string]*Tracker) {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	for name, tracker := range trackers() {
+		spec := tracker.Get()
+
+		if published, ok := publisher.published[name]; ok && published == spec {
+			continue
+		}
+
+		if err := safe.StateModify(ctx, publisher.st, status.NewControllerStatus(publisher.namespace, name), func(res *status.ControllerStatus) error {
+			*res.TypedSpec() = spec
+
+			return nil
+		}); err != nil {
+			if ctx.Err() == nil {
+				publisher.logger.Warn("failed to publish controller status", zap.String("controller", name), zap.Error(err))
+			}
+
+			continue
+		}
+
+		publisher.published[name] = spec
+	}
+}
+
+// Remove the status of the controller.
+func (publisher *Publisher) Remove(ctx context.Context, name string) error {
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	delete(publisher.published, name)
+
+	err := publisher.st.Destroy(ctx, status.NewControllerStatus(publisher.namespace, name).Metadata())
+	if err != nil && !state.IsNotFoundError(err) {
+		return err
+	}
+
+	return nil
+}
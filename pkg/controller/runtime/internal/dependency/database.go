@@ -8,6 +8,7 @@ package dependency
 import (
 	"cmp"
 	"fmt"
+	"path"
 	"slices"
 	"sync"
 
@@ -20,9 +21,10 @@ type Database struct {
 	exclusiveOutputs map[resource.Type]string
 	sharedOutputs    map[resource.Type][]string
 
-	inputLookup      map[namespaceType][]string
-	inputLookupID    map[namespaceTypeID][]string
-	controllerInputs map[string][]controller.Input
+	inputLookup        map[namespaceType][]string
+	inputLookupID      map[namespaceTypeID][]string
+	inputLookupPattern map[resource.Type][]namespacePattern
+	controllerInputs   map[string][]controller.Input
 
 	mu sync.Mutex
 }
@@ -37,15 +39,21 @@ type namespaceTypeID struct {
 	ID resource.ID
 }
 
+type namespacePattern struct {
+	Pattern        string
+	ControllerName string
+}
+
 // NewDatabase creates new Database.
 func NewDatabase() (*Database, error) {
 	return &Database{
 		exclusiveOutputs: make(map[resource.Type]string),
 		sharedOutputs:    make(map[resource.Type][]string),
 
-		inputLookup:      make(map[namespaceType][]string),
-		inputLookupID:    make(map[namespaceTypeID][]string),
-		controllerInputs: make(map[string][]controller.Input),
+		inputLookup:        make(map[namespaceType][]string),
+		inputLookupID:      make(map[namespaceTypeID][]string),
+		inputLookupPattern: make(map[resource.Type][]namespacePattern),
+		controllerInputs:   make(map[string][]controller.Input),
 	}, nil
 }
 
@@ -129,6 +137,16 @@ func (db *Database) GetResourceExclusiveController(resourceType resource.Type) (
 
 // AddControllerInput adds a dependency of controller on a resource.
 func (db *Database) AddControllerInput(controllerName string, dep controller.Input) error {
+	if dep.NamespacePattern != "" {
+		if dep.Namespace != "" || dep.ID.IsPresent() {
+			return fmt.Errorf("controller input with namespace pattern %q can't have namespace or ID set: %q -> %v", dep.NamespacePattern, controllerName, dep)
+		}
+
+		if _, err := path.Match(dep.NamespacePattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", dep.NamespacePattern, err)
+		}
+	}
+
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -147,6 +165,12 @@ func (db *Database) AddControllerInput(controllerName string, dep controller.Inp
 
 	db.controllerInputs[controllerName] = slices.Insert(existingInputs, idx, dep)
 
+	if dep.NamespacePattern != "" {
+		db.inputLookupPattern[dep.Type] = append(db.inputLookupPattern[dep.Type], namespacePattern{Pattern: dep.NamespacePattern, ControllerName: controllerName})
+
+		return nil
+	}
+
 	id, ok := dep.ID.Get()
 	if !ok {
 		key := namespaceType{
@@ -198,6 +222,12 @@ func (db *Database) DeleteControllerInput(controllerName string, dep controller.
 		return fmt.Errorf("controller %q does not have input %v", controllerName, dep)
 	}
 
+	if dep.NamespacePattern != "" {
+		db.deletePatternLookup(controllerName, dep)
+
+		return nil
+	}
+
 	id, ok := dep.ID.Get()
 	if !ok {
 		key := namespaceType{
@@ -225,6 +255,73 @@ func (db *Database) DeleteControllerInput(controllerName string, dep controller.
 	return nil
 }
 
+// DeleteController removes all inputs and outputs of the controller.
+//
+// Exclusive outputs owned by the controller are released, so that they can be claimed by other controllers.
+func (db *Database) DeleteController(controllerName string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for resourceType, exclusiveController := range db.exclusiveOutputs {
+		if exclusiveController == controllerName {
+			delete(db.exclusiveOutputs, resourceType)
+		}
+	}
+
+	for resourceType, sharedControllers := range db.sharedOutputs {
+		sharedControllers = slices.DeleteFunc(sharedControllers, func(s string) bool {
+			return s == controllerName
+		})
+
+		if len(sharedControllers) == 0 {
+			delete(db.sharedOutputs, resourceType)
+		} else {
+			db.sharedOutputs[resourceType] = sharedControllers
+		}
+	}
+
+	for _, dep := range db.controllerInputs[controllerName] {
+		if dep.NamespacePattern != "" {
+			db.deletePatternLookup(controllerName, dep)
+
+			continue
+		}
+
+		key := namespaceType{
+			Namespace: dep.Namespace,
+			Type:      dep.Type,
+		}
+
+		id, ok := dep.ID.Get()
+		if !ok {
+			db.inputLookup[key] = slices.DeleteFunc(db.inputLookup[key], func(s string) bool {
+				return s == controllerName
+			})
+
+			continue
+		}
+
+		keyID := namespaceTypeID{
+			namespaceType: key,
+			ID:            id,
+		}
+
+		db.inputLookupID[keyID] = slices.DeleteFunc(db.inputLookupID[keyID], func(s string) bool {
+			return s == controllerName
+		})
+	}
+
+	delete(db.controllerInputs, controllerName)
+
+	return nil
+}
+
+func (db *Database) deletePatternLookup(controllerName string, dep controller.Input) {
+	db.inputLookupPattern[dep.Type] = slices.DeleteFunc(db.inputLookupPattern[dep.Type], func(p namespacePattern) bool {
+		return p.ControllerName == controllerName && p.Pattern == dep.NamespacePattern
+	})
+}
+
 // GetControllerInputs returns a list of controller dependencies.
 func (db *Database) GetControllerInputs(controllerName string) ([]controller.Input, error) {
 	db.mu.Lock()
@@ -242,7 +339,7 @@ func (db *Database) GetDependentControllers(dep controller.Input) ([]string, err
 		return nil, fmt.Errorf("resource ID is not set")
 	}
 
-	return slices.Concat(
+	controllers := slices.Concat(
 		db.inputLookup[namespaceType{
 			Namespace: dep.Namespace,
 			Type:      dep.Type,
@@ -254,7 +351,15 @@ func (db *Database) GetDependentControllers(dep controller.Input) ([]string, err
 			},
 			ID: dep.ID.ValueOrZero(),
 		}],
-	), nil
+	)
+
+	for _, p := range db.inputLookupPattern[dep.Type] {
+		if matched, _ := path.Match(p.Pattern, dep.Namespace); matched { //nolint:errcheck // pattern is validated in AddControllerInput
+			controllers = append(controllers, p.ControllerName)
+		}
+	}
+
+	return controllers, nil
 }
 
 // Export dependency graph.
@@ -304,7 +409,7 @@ func (db *Database) Export() (*controller.DependencyGraph, error) {
 			graph.Edges = append(graph.Edges, controller.DependencyEdge{
 				ControllerName:    controllerName,
 				EdgeType:          edgeType,
-				ResourceNamespace: input.Namespace,
+				ResourceNamespace: cmp.Or(input.Namespace, input.NamespacePattern),
 				ResourceType:      input.Type,
 				ResourceID:        input.ID.ValueOrZero(),
 			})
@@ -184,6 +184,123 @@ func (suite *DatabaseSuite) TestControllerDependency() {
 	suite.Assert().Empty(ctrls)
 }
 
+func (suite *DatabaseSuite) TestControllerDependencyPattern() {
+	suite.Require().NoError(suite.db.AddControllerInput("TenantController", controller.Input{
+		NamespacePattern: "tenant-*",
+		Type:             "Config",
+		Kind:             controller.InputWeak,
+	}))
+
+	suite.Require().Error(suite.db.AddControllerInput("TenantController", controller.Input{
+		NamespacePattern: "tenant-*",
+		Type:             "Machine",
+		ID:               optional.Some[resource.ID]("system"),
+		Kind:             controller.InputWeak,
+	}))
+
+	suite.Require().Error(suite.db.AddControllerInput("TenantController", controller.Input{
+		NamespacePattern: "[",
+		Type:             "Machine",
+		Kind:             controller.InputWeak,
+	}))
+
+	ctrls, err := suite.db.GetDependentControllers(controller.Input{
+		Namespace: "tenant-a",
+		Type:      "Config",
+		ID:        optional.Some[resource.ID]("config"),
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]string{"TenantController"}, ctrls)
+
+	ctrls, err = suite.db.GetDependentControllers(controller.Input{
+		Namespace: "other",
+		Type:      "Config",
+		ID:        optional.Some[resource.ID]("config"),
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Empty(ctrls)
+
+	suite.Require().NoError(suite.db.DeleteControllerInput("TenantController", controller.Input{
+		NamespacePattern: "tenant-*",
+		Type:             "Config",
+	}))
+
+	ctrls, err = suite.db.GetDependentControllers(controller.Input{
+		Namespace: "tenant-a",
+		Type:      "Config",
+		ID:        optional.Some[resource.ID]("config"),
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Empty(ctrls)
+}
+
+func (suite *DatabaseSuite) TestDeleteController() {
+	suite.Require().NoError(suite.db.AddControllerOutput("ControllerBook", controller.Output{
+		Kind: controller.OutputExclusive,
+		Type: "Book",
+	}))
+	suite.Require().NoError(suite.db.AddControllerOutput("ControllerBook", controller.Output{
+		Kind: controller.OutputShared,
+		Type: "Magazine",
+	}))
+	suite.Require().NoError(suite.db.AddControllerOutput("ControllerTable", controller.Output{
+		Kind: controller.OutputShared,
+		Type: "Magazine",
+	}))
+	suite.Require().NoError(suite.db.AddControllerInput("ControllerBook", controller.Input{
+		Namespace: "user",
+		Type:      "Config",
+		Kind:      controller.InputWeak,
+	}))
+	suite.Require().NoError(suite.db.AddControllerInput("ControllerBook", controller.Input{
+		Namespace: "state",
+		Type:      "Machine",
+		ID:        optional.Some[resource.ID]("system"),
+		Kind:      controller.InputStrong,
+	}))
+
+	suite.Require().NoError(suite.db.DeleteController("ControllerBook"))
+
+	outputs, err := suite.db.GetControllerOutputs("ControllerBook")
+	suite.Require().NoError(err)
+	suite.Assert().Empty(outputs)
+
+	outputs, err = suite.db.GetControllerOutputs("ControllerTable")
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]controller.Output{
+		{
+			Kind: controller.OutputShared,
+			Type: "Magazine",
+		},
+	}, outputs)
+
+	inputs, err := suite.db.GetControllerInputs("ControllerBook")
+	suite.Require().NoError(err)
+	suite.Assert().Empty(inputs)
+
+	ctrls, err := suite.db.GetDependentControllers(controller.Input{
+		Namespace: "state",
+		Type:      "Machine",
+		ID:        optional.Some[resource.ID]("system"),
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Empty(ctrls)
+
+	ctrls, err = suite.db.GetDependentControllers(controller.Input{
+		Namespace: "user",
+		Type:      "Config",
+		ID:        optional.Some[resource.ID]("config"),
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Empty(ctrls)
+
+	// exclusive output is released
+	suite.Require().NoError(suite.db.AddControllerOutput("ControllerTable", controller.Output{
+		Kind: controller.OutputExclusive,
+		Type: "Book",
+	}))
+}
+
 func (suite *DatabaseSuite) TestExport() {
 	suite.Require().NoError(suite.db.AddControllerOutput("ControllerBook", controller.Output{
 		Kind: controller.OutputExclusive,
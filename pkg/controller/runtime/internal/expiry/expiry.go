@@ -0,0 +1,174 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package expiry implements tearing down and destroying resources past their expiration timestamp.
+package expiry
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// RetryInterval is the delay before retrying failed teardown or destroy of an expired resource.
+const RetryInterval = time.Second
+
+// Collector watches a single resource kind and collects expired resources.
+//
+// Expired resources are torn down first, and destroyed once all finalizers are removed.
+// Resources are torn down and destroyed on behalf of their owner.
+type Collector struct {
+	state     state.State
+	logger    *zap.Logger
+	deadlines map[resource.ID]time.Time
+	namespace resource.Namespace
+	typ       resource.Type
+}
+
+// NewCollector initializes a new Collector.
+func NewCollector(st state.State, logger *zap.Logger, namespace resource.Namespace, typ resource.Type) *Collector {
+	return &Collector{
+		state:     st,
+		logger:    logger.With(zap.String("namespace", namespace), zap.String("type", typ)),
+		deadlines: map[resource.ID]time.Time{},
+		namespace: namespace,
+		typ:       typ,
+	}
+}
+
+// Run the collector until the context is canceled.
+//
+// Run returns an error if the watch fails.
+func (collector *Collector) Run(ctx context.Context) error {
+	eventCh := make(chan state.Event)
+
+	if err := collector.state.WatchKind(ctx, resource.NewMetadata(collector.namespace, collector.typ, "", resource.VersionUndefined), eventCh, state.WithBootstrapContents(true)); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-eventCh:
+			switch event.Type {
+			case state.Created, state.Updated:
+				collector.track(event.Resource)
+			case state.Destroyed:
+				delete(collector.deadlines, event.Resource.Metadata().ID())
+			case state.Errored:
+				return event.Error
+			case state.Bootstrapped, state.Noop:
+			}
+		case <-timer.C:
+			collector.collect(ctx)
+		}
+
+		collector.resetTimer(timer)
+	}
+}
+
+func (collector *Collector) track(r resource.Resource) {
+	md := r.Metadata()
+
+	// resources without expiration and resources waiting for finalizers are not tracked,
+	// any further update will re-evaluate them
+	if md.Expires().IsZero() || (md.Phase() == resource.PhaseTearingDown && !md.Finalizers().Empty()) {
+		delete(collector.deadlines, md.ID())
+
+		return
+	}
+
+	collector.deadlines[md.ID()] = md.Expires()
+}
+
+func (collector *Collector) resetTimer(timer *time.Timer) {
+	var earliest time.Time
+
+	for _, deadline := range collector.deadlines {
+		if earliest.IsZero() || deadline.Before(earliest) {
+			earliest = deadline
+		}
+	}
+
+	if earliest.IsZero() {
+		timer.Stop()
+
+		return
+	}
+
+	timer.Reset(time.Until(earliest))
+}
+
+func (collector *Collector) collect(ctx context.Context) {
+	now := time.Now()
+
+	for id, deadline := range collector.deadlines {
+		if deadline.After(now) {
+			continue
+		}
+
+		if err := collector.expire(ctx, id, now); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			collector.logger.Error("failed to collect expired resource", zap.String("id", id), zap.Error(err))
+
+			collector.deadlines[id] = now.Add(RetryInterval)
+		}
+	}
+}
+
+func (collector *Collector) expire(ctx context.Context, id resource.ID, now time.Time) error {
+	r, err := collector.state.Get(ctx, resource.NewMetadata(collector.namespace, collector.typ, id, resource.VersionUndefined))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			delete(collector.deadlines, id)
+
+			return nil
+		}
+
+		return err
+	}
+
+	md := r.Metadata()
+
+	if !md.Expired(now) {
+		collector.track(r)
+
+		return nil
+	}
+
+	// once torn down (or destroyed), the resource is re-evaluated on the next watch event
+	delete(collector.deadlines, id)
+
+	ready, err := collector.state.Teardown(ctx, md, state.WithTeardownOwner(md.Owner()))
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if !ready {
+		return nil
+	}
+
+	collector.logger.Info("destroying expired resource", zap.String("id", id), zap.Time("expires", md.Expires()))
+
+	if err = collector.state.Destroy(ctx, md, state.WithDestroyOwner(md.Owner())); err != nil && !state.IsNotFoundError(err) {
+		return err
+	}
+
+	return nil
+}
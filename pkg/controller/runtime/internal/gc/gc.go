@@ -0,0 +1,416 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package gc implements cascading garbage collection of resources based on owner references.
+package gc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// FinalizerPrefix is the prefix of the finalizer put on the parent resources in the foreground cascade mode.
+const FinalizerPrefix = "GarbageCollector/"
+
+// Finalizer returns the finalizer put on the parent resources by the collector of the child resource kind.
+//
+// Each collector uses its own finalizer, so that the parent with children of several kinds is kept
+// until the children of all kinds are destroyed.
+func Finalizer(namespace resource.Namespace, typ resource.Type) resource.Finalizer {
+	return FinalizerPrefix + namespace + "/" + typ
+}
+
+// RetryInterval is the delay before retrying failed garbage collection operations.
+const RetryInterval = time.Second
+
+type kindKey struct {
+	Namespace resource.Namespace
+	Type      resource.Type
+}
+
+func kindKeyFor(kind resource.Kind) kindKey {
+	return kindKey{Namespace: kind.Namespace(), Type: kind.Type()}
+}
+
+type parentKey = resource.OwnerReference
+
+type child struct {
+	owner           resource.Owner
+	parents         []parentKey
+	phase           resource.Phase
+	finalizersEmpty bool
+}
+
+type parent struct {
+	phase        resource.Phase
+	hasFinalizer bool
+}
+
+// Collector watches a single (child) resource kind and cascades teardown of parent resources to children.
+//
+// Collector watches parent resource kinds as they are discovered via owner references of the children.
+type Collector struct {
+	state  state.State
+	logger *zap.Logger
+
+	events chan state.Event
+
+	children    map[resource.ID]child
+	byParent    map[parentKey]map[resource.ID]struct{}
+	parents     map[parentKey]parent
+	parentKinds map[kindKey]bool // value is true if the kind is bootstrapped
+	dirty       map[parentKey]struct{}
+
+	orphansMu sync.Mutex
+	orphans   map[resource.ID]struct{}
+
+	namespace resource.Namespace
+	typ       resource.Type
+	finalizer resource.Finalizer
+	mode      options.CascadeMode
+
+	childrenBootstrapped bool
+}
+
+// NewCollector initializes a new Collector.
+func NewCollector(st state.State, logger *zap.Logger, namespace resource.Namespace, typ resource.Type, mode options.CascadeMode) *Collector {
+	return &Collector{
+		state:       st,
+		logger:      logger.With(zap.String("namespace", namespace), zap.String("type", typ)),
+		events:      make(chan state.Event),
+		children:    map[resource.ID]child{},
+		byParent:    map[parentKey]map[resource.ID]struct{}{},
+		parents:     map[parentKey]parent{},
+		parentKinds: map[kindKey]bool{},
+		dirty:       map[parentKey]struct{}{},
+		orphans:     map[resource.ID]struct{}{},
+		namespace:   namespace,
+		typ:         typ,
+		finalizer:   Finalizer(namespace, typ),
+		mode:        mode,
+	}
+}
+
+// Orphans returns the list of children whose parents don't exist.
+//
+// Orphans are not persisted, they are recomputed from the state: on start, the children and their parents
+// are bootstrapped from the state, and the orphans are reported once the parent kinds are bootstrapped.
+// Orphans are updated as the children and the parents change, so that a child is no longer reported
+// once its parent is created again, or once it no longer refers to the missing parent.
+func (collector *Collector) Orphans() []resource.Pointer {
+	collector.orphansMu.Lock()
+	defer collector.orphansMu.Unlock()
+
+	result := make([]resource.Pointer, 0, len(collector.orphans))
+
+	for id := range collector.orphans {
+		result = append(result, resource.NewMetadata(collector.namespace, collector.typ, id, resource.VersionUndefined))
+	}
+
+	return result
+}
+
+// Run the collector until the context is canceled.
+//
+// Run returns an error if any of the watches fails.
+func (collector *Collector) Run(ctx context.Context) error {
+	childCh := make(chan state.Event)
+
+	if err := collector.state.WatchKind(ctx, resource.NewMetadata(collector.namespace, collector.typ, "", resource.VersionUndefined), childCh, state.WithBootstrapContents(true)); err != nil {
+		return err
+	}
+
+	retry := time.NewTimer(0)
+	defer retry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-childCh:
+			if err := collector.handleChildEvent(ctx, event); err != nil {
+				return err
+			}
+		case event := <-collector.events:
+			if err := collector.handleParentEvent(event); err != nil {
+				return err
+			}
+		case <-retry.C:
+		}
+
+		if !collector.childrenBootstrapped {
+			continue
+		}
+
+		if !collector.reconcile(ctx) {
+			retry.Reset(RetryInterval)
+		}
+	}
+}
+
+func (collector *Collector) handleChildEvent(ctx context.Context, event state.Event) error {
+	switch event.Type {
+	case state.Created, state.Updated:
+		md := event.Resource.Metadata()
+
+		collector.removeChild(md.ID())
+
+		if md.OwnerReferences().Empty() {
+			return nil
+		}
+
+		c := child{
+			owner:           md.Owner(),
+			phase:           md.Phase(),
+			finalizersEmpty: md.Finalizers().Empty(),
+		}
+
+		for _, key := range *md.OwnerReferences() {
+			c.parents = append(c.parents, key)
+
+			if collector.byParent[key] == nil {
+				collector.byParent[key] = map[resource.ID]struct{}{}
+			}
+
+			collector.byParent[key][md.ID()] = struct{}{}
+			collector.dirty[key] = struct{}{}
+
+			if err := collector.watchParentKind(ctx, kindKeyFor(key)); err != nil {
+				return err
+			}
+		}
+
+		collector.children[md.ID()] = c
+	case state.Destroyed:
+		collector.removeChild(event.Resource.Metadata().ID())
+	case state.Bootstrapped:
+		collector.childrenBootstrapped = true
+	case state.Errored:
+		return event.Error
+	case state.Noop:
+	}
+
+	return nil
+}
+
+func (collector *Collector) removeChild(id resource.ID) {
+	c, ok := collector.children[id]
+	if !ok {
+		return
+	}
+
+	for _, key := range c.parents {
+		delete(collector.byParent[key], id)
+
+		if len(collector.byParent[key]) == 0 {
+			delete(collector.byParent, key)
+		}
+
+		collector.dirty[key] = struct{}{}
+	}
+
+	delete(collector.children, id)
+
+	collector.orphansMu.Lock()
+	delete(collector.orphans, id)
+	collector.orphansMu.Unlock()
+}
+
+func (collector *Collector) watchParentKind(ctx context.Context, kind kindKey) error {
+	if _, watched := collector.parentKinds[kind]; watched {
+		return nil
+	}
+
+	collector.parentKinds[kind] = false
+
+	return collector.state.WatchKind(ctx, resource.NewMetadata(kind.Namespace, kind.Type, "", resource.VersionUndefined), collector.events, state.WithBootstrapContents(true))
+}
+
+func (collector *Collector) handleParentEvent(event state.Event) error {
+	switch event.Type {
+	case state.Created, state.Updated:
+		md := event.Resource.Metadata()
+		key := resource.NewOwnerReference(md)
+
+		collector.parents[key] = parent{
+			phase:        md.Phase(),
+			hasFinalizer: md.Finalizers().Has(collector.finalizer),
+		}
+
+		collector.dirty[key] = struct{}{}
+	case state.Destroyed:
+		key := resource.NewOwnerReference(event.Resource.Metadata())
+
+		delete(collector.parents, key)
+
+		collector.dirty[key] = struct{}{}
+	case state.Bootstrapped:
+		kind := kindKeyFor(event.Resource.Metadata())
+
+		collector.parentKinds[kind] = true
+
+		for key := range collector.byParent {
+			if kindKeyFor(key) == kind {
+				collector.dirty[key] = struct{}{}
+			}
+		}
+	case state.Errored:
+		return event.Error
+	case state.Noop:
+	}
+
+	return nil
+}
+
+// reconcile processes all dirty parents, returning false if some of them should be retried.
+func (collector *Collector) reconcile(ctx context.Context) bool {
+	success := true
+
+	for key := range collector.dirty {
+		if !collector.parentKinds[kindKeyFor(key)] && len(collector.byParent[key]) > 0 {
+			// parent kind is not bootstrapped yet, wait for Bootstrapped event
+			continue
+		}
+
+		if err := collector.reconcileParent(ctx, key); err != nil {
+			if ctx.Err() != nil {
+				return true
+			}
+
+			collector.logger.Error("garbage collection failed", zap.Stringer("parent", key), zap.Error(err))
+
+			success = false
+
+			continue
+		}
+
+		delete(collector.dirty, key)
+	}
+
+	return success
+}
+
+func (collector *Collector) reconcileParent(ctx context.Context, key parentKey) error {
+	p, exists := collector.parents[key]
+	children := collector.byParent[key]
+
+	for id := range children {
+		collector.refreshOrphan(id)
+	}
+
+	switch {
+	case exists && p.phase == resource.PhaseRunning:
+		switch {
+		case collector.mode == options.CascadeForeground && len(children) > 0 && !p.hasFinalizer:
+			return collector.state.AddFinalizer(ctx, key, collector.finalizer)
+		case len(children) == 0 && p.hasFinalizer:
+			return collector.state.RemoveFinalizer(ctx, key, collector.finalizer)
+		}
+
+		return nil
+	case collector.mode == options.CascadeOrphan:
+		if exists && p.hasFinalizer {
+			return collector.state.RemoveFinalizer(ctx, key, collector.finalizer)
+		}
+
+		return nil
+	}
+
+	// parent is either tearing down or gone, collect the children
+	for id := range children {
+		if err := collector.collectChild(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if exists && len(children) == 0 && p.hasFinalizer {
+		return collector.state.RemoveFinalizer(ctx, key, collector.finalizer)
+	}
+
+	return nil
+}
+
+// refreshOrphan recomputes whether the child is an orphan.
+//
+// Child is an orphan if any of its parents doesn't exist, parents of kinds which are not bootstrapped yet are skipped.
+func (collector *Collector) refreshOrphan(id resource.ID) {
+	var (
+		missing  parentKey
+		orphaned bool
+	)
+
+	for _, key := range collector.children[id].parents {
+		if _, exists := collector.parents[key]; !exists && collector.parentKinds[kindKeyFor(key)] {
+			missing, orphaned = key, true
+
+			break
+		}
+	}
+
+	collector.orphansMu.Lock()
+	defer collector.orphansMu.Unlock()
+
+	_, reported := collector.orphans[id]
+
+	switch {
+	case orphaned && !reported:
+		collector.orphans[id] = struct{}{}
+
+		collector.logger.Warn("orphaned resource", zap.String("id", id), zap.Stringer("parent", missing))
+	case !orphaned && reported:
+		delete(collector.orphans, id)
+	}
+}
+
+// collectChild tears down and destroys the child if all of its parents are either tearing down or gone.
+func (collector *Collector) collectChild(ctx context.Context, id resource.ID) error {
+	c := collector.children[id]
+
+	for _, key := range c.parents {
+		if p, exists := collector.parents[key]; exists && p.phase == resource.PhaseRunning {
+			return nil
+		}
+	}
+
+	ptr := resource.NewMetadata(collector.namespace, collector.typ, id, resource.VersionUndefined)
+
+	if c.phase == resource.PhaseTearingDown && !c.finalizersEmpty {
+		// wait for finalizers to be removed
+		return nil
+	}
+
+	ready := c.phase == resource.PhaseTearingDown
+
+	if !ready {
+		var err error
+
+		ready, err = collector.state.Teardown(ctx, ptr, state.WithTeardownOwner(c.owner))
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	if !ready {
+		return nil
+	}
+
+	collector.logger.Info("destroying resource after its parents", zap.String("id", id))
+
+	if err := collector.state.Destroy(ctx, ptr, state.WithDestroyOwner(c.owner)); err != nil && !state.IsNotFoundError(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package gc_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/siderolabs/gen/xslices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/gc"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func runCollector(ctx context.Context, t *testing.T, st state.State, mode options.CascadeMode) *gc.Collector {
+	t.Helper()
+
+	return runKindCollector(ctx, t, st, "children", conformance.StrResourceType, mode)
+}
+
+func runKindCollector(ctx context.Context, t *testing.T, st state.State, namespace resource.Namespace, typ resource.Type, mode options.CascadeMode) *gc.Collector {
+	t.Helper()
+
+	collector := gc.NewCollector(st, zaptest.NewLogger(t), namespace, typ, mode)
+
+	ctx, cancel := context.WithCancel(ctx)
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- collector.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+
+		assert.NoError(t, <-errCh)
+	})
+
+	return collector
+}
+
+func orphanIDs(collector *gc.Collector) []resource.ID {
+	ids := xslices.Map(collector.Orphans(), resource.Pointer.ID)
+	slices.Sort(ids)
+
+	return ids
+}
+
+func newChild(id string, parents ...resource.Pointer) *conformance.StrResource {
+	child := conformance.NewStrResource("children", id, id)
+
+	for _, parent := range parents {
+		child.Metadata().OwnerReferences().Add(parent)
+	}
+
+	return child
+}
+
+func TestCollectorOrphans(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	parentA := conformance.NewIntResource("parents", "a", 1)
+	parentB := conformance.NewIntResource("parents", "b", 2)
+
+	require.NoError(t, st.Create(ctx, parentA))
+
+	for _, r := range []resource.Resource{
+		newChild("1", parentA.Metadata()),
+		newChild("2", parentB.Metadata()),
+		newChild("3", parentA.Metadata(), parentB.Metadata()),
+		newChild("4"),
+	} {
+		require.NoError(t, st.Create(ctx, r))
+	}
+
+	t.Run("recompute", func(t *testing.T) {
+		collectorCtx, collectorCancel := context.WithCancel(ctx)
+		defer collectorCancel()
+
+		collector := runCollector(collectorCtx, t, st, options.CascadeOrphan)
+
+		// orphans are recomputed from the state on start
+		assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+			assert.Equal(collect, []resource.ID{"2", "3"}, orphanIDs(collector))
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// parent is created, children are no longer orphans
+		require.NoError(t, st.Create(ctx, parentB))
+
+		assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+			assert.Empty(collect, orphanIDs(collector))
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, st.TeardownAndDestroy(ctx, parentA.Metadata()))
+
+		assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+			assert.Equal(collect, []resource.ID{"1", "3"}, orphanIDs(collector))
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// child drops the reference to the missing parent
+		_, err := st.UpdateWithConflicts(ctx, newChild("3").Metadata(), func(r resource.Resource) error {
+			r.Metadata().OwnerReferences().Remove(parentA.Metadata())
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+			assert.Equal(collect, []resource.ID{"1"}, orphanIDs(collector))
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// orphaned children are not touched
+		_, err = st.Get(ctx, newChild("1").Metadata())
+		require.NoError(t, err)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		// new collector finds the same orphans
+		collector := runCollector(ctx, t, st, options.CascadeOrphan)
+
+		assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+			assert.Equal(collect, []resource.ID{"1"}, orphanIDs(collector))
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestCollectorCascade(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		mode options.CascadeMode
+	}{
+		{name: "foreground", mode: options.CascadeForeground},
+		{name: "background", mode: options.CascadeBackground},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+			st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			parentA := conformance.NewIntResource("parents", "a", 1)
+			parentB := conformance.NewIntResource("parents", "b", 2)
+
+			require.NoError(t, st.Create(ctx, parentA))
+			require.NoError(t, st.Create(ctx, parentB))
+
+			single := newChild("single", parentA.Metadata())
+			shared := newChild("shared", parentA.Metadata(), parentB.Metadata())
+
+			require.NoError(t, st.Create(ctx, single))
+			require.NoError(t, st.Create(ctx, shared))
+
+			collector := runCollector(ctx, t, st, tt.mode)
+
+			if tt.mode == options.CascadeForeground {
+				_, err := st.WatchFor(ctx, parentA.Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+					return r.Metadata().Finalizers().Has(gc.Finalizer("children", conformance.StrResourceType)), nil
+				}))
+				require.NoError(t, err)
+			}
+
+			_, err := st.Teardown(ctx, parentA.Metadata())
+			require.NoError(t, err)
+
+			_, err = st.WatchFor(ctx, single.Metadata(), state.WithEventTypes(state.Destroyed))
+			require.NoError(t, err)
+
+			// shared child is kept while one of the parents is running
+			r, err := st.Get(ctx, shared.Metadata())
+			require.NoError(t, err)
+			assert.Equal(t, resource.PhaseRunning, r.Metadata().Phase())
+
+			_, err = st.Teardown(ctx, parentB.Metadata())
+			require.NoError(t, err)
+
+			_, err = st.WatchFor(ctx, shared.Metadata(), state.WithEventTypes(state.Destroyed))
+			require.NoError(t, err)
+
+			// finalizers are removed from the parents once the children are gone
+			for _, parent := range []resource.Pointer{parentA.Metadata(), parentB.Metadata()} {
+				_, err = st.WatchFor(ctx, parent, state.WithFinalizerEmpty())
+				require.NoError(t, err)
+
+				require.NoError(t, st.Destroy(ctx, parent))
+			}
+
+			assert.Empty(t, collector.Orphans())
+		})
+	}
+}
+
+func TestCollectorForegroundMultipleKinds(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	parent := conformance.NewIntResource("parents", "parent", 1)
+	require.NoError(t, st.Create(ctx, parent))
+
+	// child of the first kind is kept by another finalizer
+	strChild := newChild("str", parent.Metadata())
+	strChild.Metadata().Finalizers().Add("hold")
+	require.NoError(t, st.Create(ctx, strChild))
+
+	intChild := conformance.NewIntResource("int-children", "int", 2)
+	intChild.Metadata().OwnerReferences().Add(parent.Metadata())
+	require.NoError(t, st.Create(ctx, intChild))
+
+	runCollector(ctx, t, st, options.CascadeForeground)
+	runKindCollector(ctx, t, st, "int-children", conformance.IntResourceType, options.CascadeForeground)
+
+	strFinalizer := gc.Finalizer("children", conformance.StrResourceType)
+	intFinalizer := gc.Finalizer("int-children", conformance.IntResourceType)
+
+	_, err := st.WatchFor(ctx, parent.Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+		return r.Metadata().Finalizers().Has(strFinalizer) && r.Metadata().Finalizers().Has(intFinalizer), nil
+	}))
+	require.NoError(t, err)
+
+	_, err = st.Teardown(ctx, parent.Metadata())
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, intChild.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, parent.Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+		return !r.Metadata().Finalizers().Has(intFinalizer), nil
+	}))
+	require.NoError(t, err)
+
+	// parent is kept while the child of the other kind exists
+	r, err := st.Get(ctx, parent.Metadata())
+	require.NoError(t, err)
+	assert.True(t, r.Metadata().Finalizers().Has(strFinalizer))
+
+	err = st.Destroy(ctx, parent.Metadata())
+	require.Error(t, err)
+	assert.True(t, state.IsConflictError(err))
+
+	require.NoError(t, st.RemoveFinalizer(ctx, strChild.Metadata(), "hold"))
+
+	_, err = st.WatchFor(ctx, strChild.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, parent.Metadata(), state.WithFinalizerEmpty())
+	require.NoError(t, err)
+
+	require.NoError(t, st.Destroy(ctx, parent.Metadata()))
+}
@@ -5,23 +5,65 @@
 package qruntime
 
 import (
+	"errors"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/adapter"
+	"github.com/cosi-project/runtime/pkg/resource"
 )
 
-func (adapter *Adapter) getBackoffInterval(item QKey) time.Duration {
+type itemBackoff struct {
+	*backoff.ExponentialBackOff
+
+	attempts int
+}
+
+func newItemBackoff(policy controller.BackoffPolicy) *itemBackoff {
+	return &itemBackoff{
+		ExponentialBackOff: adapter.NewBackOff(policy),
+	}
+}
+
+// parkedItem keeps the details of the failure for the item in the queue dead letters.
+type parkedItem struct {
+	lastError string
+	attempts  int
+}
+
+// getBackoffInterval records the item failure and returns the backoff interval.
+//
+// If the item exhausted the backoff attempts or failed with a permanent error, getBackoffInterval returns false,
+// and the item should be moved to the queue dead letters.
+func (adapter *Adapter) getBackoffInterval(item QKey, err error) (time.Duration, bool) {
 	adapter.backoffsMu.Lock()
 	defer adapter.backoffsMu.Unlock()
 
 	bckoff, ok := adapter.backoffs[item]
 	if !ok {
-		bckoff = backoff.NewExponentialBackOff()
-		bckoff.MaxElapsedTime = 0
+		bckoff = newItemBackoff(adapter.backoffPolicy)
+
 		adapter.backoffs[item] = bckoff
 	}
 
-	return bckoff.NextBackOff()
+	bckoff.attempts++
+
+	var permanentError *controller.PermanentError
+
+	if errors.As(err, &permanentError) || (adapter.backoffPolicy.MaxAttempts > 0 && bckoff.attempts >= adapter.backoffPolicy.MaxAttempts) {
+		delete(adapter.backoffs, item)
+
+		adapter.parked[item] = parkedItem{
+			lastError: err.Error(),
+			attempts:  bckoff.attempts,
+		}
+
+		return 0, false
+	}
+
+	return bckoff.NextBackOff(), true
 }
 
 func (adapter *Adapter) clearBackoff(item QKey) {
@@ -30,3 +72,60 @@ func (adapter *Adapter) clearBackoff(item QKey) {
 
 	delete(adapter.backoffs, item)
 }
+
+// unpark removes the failure details of the item, as it is being processed again.
+func (adapter *Adapter) unpark(item QKey) {
+	adapter.backoffsMu.Lock()
+	defer adapter.backoffsMu.Unlock()
+
+	delete(adapter.parked, item)
+}
+
+// ParkedItems implements adapter.ParkingAdapter interface.
+func (adapter *Adapter) ParkedItems() []controller.ParkedItem {
+	adapter.backoffsMu.Lock()
+	defer adapter.backoffsMu.Unlock()
+
+	deadLetters := adapter.queue.DeadLetters()
+	result := make([]controller.ParkedItem, 0, len(deadLetters))
+
+	for _, deadLetter := range deadLetters {
+		item := adapter.parked[deadLetter.Key]
+
+		result = append(result, controller.ParkedItem{
+			ParkedAt:  deadLetter.Since,
+			Pointer:   resource.NewMetadata(deadLetter.Key.Namespace(), deadLetter.Key.Type(), deadLetter.Key.ID(), resource.VersionUndefined),
+			LastError: item.lastError,
+			Attempts:  item.attempts,
+		})
+	}
+
+	return result
+}
+
+// RetryParked implements adapter.ParkingAdapter interface.
+func (adapter *Adapter) RetryParked(ptrs ...resource.Pointer) int {
+	var retry []QKey
+
+	for _, deadLetter := range adapter.queue.DeadLetters() {
+		if len(ptrs) > 0 && !matchesAny(deadLetter.Key, ptrs) {
+			continue
+		}
+
+		retry = append(retry, deadLetter.Key)
+	}
+
+	return adapter.queue.Readmit(retry...)
+}
+
+func matchesAny(key QKey, ptrs []resource.Pointer) bool {
+	for _, ptr := range ptrs {
+		if key.Namespace() == ptr.Namespace() && key.Type() == ptr.Type() && key.ID() == ptr.ID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+var _ adapter.ParkingAdapter = (*Adapter)(nil)
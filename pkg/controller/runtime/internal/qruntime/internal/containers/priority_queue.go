@@ -5,6 +5,8 @@
 package containers
 
 import (
+	"cmp"
+	"container/heap"
 	"slices"
 	"time"
 
@@ -15,13 +17,34 @@ type itemWithBackoff[K comparable, V any] struct {
 	Key          K
 	Value        V
 	ReleaseAfter time.Time
+	Priority     int
+
+	// seq orders the items pushed with the same release time and priority
+	seq uint64
+	// index in the ready heap, -1 if the item is delayed
+	index int
 }
 
 // PriorityQueue keeps a priority queue of items with backoff (release after).
 //
 // PriorityQueue deduplicates by key (K), while it overwrites the value (V) on duplication.
+//
+// Items which are ready to be released are ordered by priority (higher first), and by release time
+// for the same priority. If Aging is set, the priority of the ready item is raised by one for each Aging interval
+// it has been waiting, so that low priority items still progress.
+//
+// Items which are not ready yet are kept sorted by release time, and they are moved to the ready heap
+// once their release time comes. As all ready items age at the same rate, aging doesn't change their relative order,
+// so the heap order stays valid.
 type PriorityQueue[K comparable, V any] struct {
-	items []itemWithBackoff[K, V]
+	items    map[K]*itemWithBackoff[K, V]
+	promoted time.Time
+	delayed  []*itemWithBackoff[K, V]
+	ready    readyHeap[K, V]
+	seq      uint64
+
+	// Aging should be set before the first item is pushed.
+	Aging time.Duration
 }
 
 // Push item to the queue with releaseAfter time.
@@ -34,27 +57,66 @@ type PriorityQueue[K comparable, V any] struct {
 //
 // Push returns true if the item was added, and false if the existing item in the queue was updated (or skipped).
 func (queue *PriorityQueue[K, V]) Push(key K, value V, releaseAfter time.Time, overwriteValue bool) bool {
-	idx := slices.IndexFunc(queue.items, func(queueItem itemWithBackoff[K, V]) bool {
-		return queueItem.Key == key
-	})
+	return queue.PushWithPriority(key, value, releaseAfter, 0, overwriteValue)
+}
 
-	if idx != -1 { // the item is already in the queue
-		// update the value
-		if overwriteValue {
-			queue.items[idx].Value = value
-		}
+// PushWithPriority pushes the item to the queue with releaseAfter time and priority.
+//
+// If the item (by key) is in the queue, its priority is raised to the new priority if it is higher.
+// Otherwise PushWithPriority works the same way as Push.
+func (queue *PriorityQueue[K, V]) PushWithPriority(key K, value V, releaseAfter time.Time, priority int, overwriteValue bool) bool {
+	if queue.items == nil {
+		queue.items = map[K]*itemWithBackoff[K, V]{}
+		queue.ready.aging = queue.Aging
+	}
+
+	item, found := queue.items[key]
+	if !found {
+		item = &itemWithBackoff[K, V]{Key: key, Value: value, ReleaseAfter: releaseAfter, Priority: priority, index: -1}
+		queue.items[key] = item
+		queue.insertDelayed(item)
+
+		return true
+	}
+
+	// update the value
+	if overwriteValue {
+		item.Value = value
+	}
+
+	item.Priority = max(priority, item.Priority)
+
+	// if new releaseAfter > existing releaseAfter, keep the release time
+	if releaseAfter.Compare(item.ReleaseAfter) <= 0 {
+		item.ReleaseAfter = releaseAfter
+
+		// re-order the queue by removing the existing item, it is re-added as the last one of the equal items
+		if item.index == -1 {
+			idx := slices.Index(queue.delayed, item)
+
+			queue.delayed = slices.Delete(queue.delayed, idx, idx+1)
+			queue.insertDelayed(item)
 
-		// if new releaseAfter > existing releaseAfter, do nothing
-		if releaseAfter.Compare(queue.items[idx].ReleaseAfter) > 0 {
 			return false
 		}
 
-		// re-order the queue by deleting existing item from the queue, it will be re-added below
-		queue.items = slices.Delete(queue.items, idx, idx+1)
+		queue.seq++
+		item.seq = queue.seq
 	}
 
+	if item.index != -1 {
+		heap.Fix(&queue.ready, item.index)
+	}
+
+	return false
+}
+
+func (queue *PriorityQueue[K, V]) insertDelayed(item *itemWithBackoff[K, V]) {
+	queue.seq++
+	item.seq = queue.seq
+
 	// find a position and add an item to the queue
-	newIdx, _ := slices.BinarySearchFunc(queue.items, releaseAfter, func(queueItem itemWithBackoff[K, V], releaseAfter time.Time) int {
+	idx, _ := slices.BinarySearchFunc(queue.delayed, item.ReleaseAfter, func(queueItem *itemWithBackoff[K, V], releaseAfter time.Time) int {
 		c := queueItem.ReleaseAfter.Compare(releaseAfter)
 		if c == 0 {
 			// force the binary search to insert to the "tail" if it encounters the same releaseAfter value
@@ -65,37 +127,156 @@ func (queue *PriorityQueue[K, V]) Push(key K, value V, releaseAfter time.Time, o
 		return c
 	})
 
-	queue.items = slices.Insert(queue.items, newIdx, itemWithBackoff[K, V]{Key: key, Value: value, ReleaseAfter: releaseAfter})
+	queue.delayed = slices.Insert(queue.delayed, idx, item)
+}
+
+// promote moves the items which are ready to be released at now to the ready heap.
+func (queue *PriorityQueue[K, V]) promote(now time.Time) {
+	if now.Before(queue.promoted) {
+		// the clock went backwards, so move back the items which are no longer ready
+		for i := 0; i < len(queue.ready.items); {
+			if item := queue.ready.items[i]; item.ReleaseAfter.After(now) {
+				heap.Remove(&queue.ready, i)
+				queue.insertDelayed(item)
 
-	return idx == -1
+				continue
+			}
+
+			i++
+		}
+	}
+
+	queue.promoted = now
+
+	// items are ordered by release time, so the ready items are at the head of the queue
+	for len(queue.delayed) > 0 && !queue.delayed[0].ReleaseAfter.After(now) {
+		heap.Push(&queue.ready, queue.delayed[0])
+
+		queue.delayed[0] = nil
+		queue.delayed = queue.delayed[1:]
+	}
 }
 
 // Peek returns the top item from the queue if it is ready to be released at now.
 //
+// The top item is the ready item with the highest priority (see PriorityQueue), Pop, ReleaseAfter and Priority
+// refer to the item returned by the last call to Peek.
+//
 // If Peek returns optional.None, it also returns delay to get the next item from the queue.
 // If there are no items in the queue, Peek returns optional.None and zero delay.
 func (queue *PriorityQueue[K, V]) Peek(now time.Time) (key optional.Optional[K], value optional.Optional[V], nextDelay time.Duration) {
-	if len(queue.items) > 0 {
-		delay := queue.items[0].ReleaseAfter.Sub(now)
+	queue.promote(now)
 
-		if delay <= 0 {
-			return optional.Some(queue.items[0].Key), optional.Some(queue.items[0].Value), 0
-		}
+	if len(queue.ready.items) > 0 {
+		return optional.Some(queue.ready.items[0].Key), optional.Some(queue.ready.items[0].Value), 0
+	}
 
-		return optional.None[K](), optional.None[V](), delay
+	if len(queue.delayed) == 0 {
+		return optional.None[K](), optional.None[V](), 0
 	}
 
-	return optional.None[K](), optional.None[V](), 0
+	return optional.None[K](), optional.None[V](), queue.delayed[0].ReleaseAfter.Sub(now)
+}
+
+func (queue *PriorityQueue[K, V]) top() *itemWithBackoff[K, V] {
+	switch {
+	case len(queue.ready.items) > 0:
+		return queue.ready.items[0]
+	case len(queue.delayed) > 0:
+		return queue.delayed[0]
+	default:
+		return nil
+	}
+}
+
+// ReleaseAfter returns the release time of the top item in the queue.
+//
+// If there are no items in the queue, ReleaseAfter returns zero time.
+func (queue *PriorityQueue[K, V]) ReleaseAfter() time.Time {
+	if top := queue.top(); top != nil {
+		return top.ReleaseAfter
+	}
+
+	return time.Time{}
+}
+
+// Priority returns the priority of the top item in the queue.
+//
+// If there are no items in the queue, Priority returns zero.
+func (queue *PriorityQueue[K, V]) Priority() int {
+	if top := queue.top(); top != nil {
+		return top.Priority
+	}
+
+	return 0
 }
 
 // Pop removes the top item from the queue.
 //
 // Pop should only be called if Peek returned optional.Some.
 func (queue *PriorityQueue[K, V]) Pop() {
-	queue.items = slices.Delete(queue.items, 0, 1)
+	item := heap.Pop(&queue.ready).(*itemWithBackoff[K, V]) //nolint:forcetypeassert,errcheck
+
+	delete(queue.items, item.Key)
 }
 
 // Len returns the number of items in the queue.
 func (queue *PriorityQueue[K, V]) Len() int {
 	return len(queue.items)
 }
+
+// readyHeap implements heap.Interface for the items ready to be released.
+type readyHeap[K comparable, V any] struct {
+	items []*itemWithBackoff[K, V]
+	aging time.Duration
+}
+
+func (h *readyHeap[K, V]) Len() int { return len(h.items) }
+
+// Less orders the items by the effective priority.
+//
+// With aging, the effective priority at time now is Priority + (now - ReleaseAfter) / aging,
+// so the comparison of two items doesn't depend on now.
+func (h *readyHeap[K, V]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+
+	var c int
+
+	if h.aging > 0 {
+		c = cmp.Compare(time.Duration(a.Priority-b.Priority)*h.aging, a.ReleaseAfter.Sub(b.ReleaseAfter))
+	} else {
+		c = cmp.Compare(a.Priority, b.Priority)
+	}
+
+	if c == 0 {
+		c = b.ReleaseAfter.Compare(a.ReleaseAfter)
+	}
+
+	if c == 0 {
+		return a.seq < b.seq
+	}
+
+	return c > 0
+}
+
+func (h *readyHeap[K, V]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *readyHeap[K, V]) Push(x any) {
+	item := x.(*itemWithBackoff[K, V]) //nolint:forcetypeassert,errcheck
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *readyHeap[K, V]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	item.index = -1
+
+	return item
+}
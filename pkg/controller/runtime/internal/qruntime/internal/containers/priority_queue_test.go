@@ -74,6 +74,7 @@ func TestPriorityQueueReverse(t *testing.T) {
 		require.True(t, ok)
 		assert.Equal(t, i, queueItem)
 		assert.Zero(t, delay)
+		assert.Equal(t, base.Add(time.Duration(10-i)), q.ReleaseAfter())
 
 		q.Pop()
 	}
@@ -82,6 +83,7 @@ func TestPriorityQueueReverse(t *testing.T) {
 	_, ok := item.Get()
 	require.False(t, ok)
 	assert.Zero(t, delay)
+	assert.Zero(t, q.ReleaseAfter())
 
 	assert.Equal(t, 0, q.Len())
 }
@@ -206,6 +208,104 @@ func TestPriorityQueueDeduplicateWithReleaseAfterLess(t *testing.T) {
 	assert.Equal(t, 0, q.Len())
 }
 
+func TestPriorityQueuePriority(t *testing.T) {
+	var q containers.PriorityQueue[int, struct{}]
+
+	var base time.Time
+
+	// 1 and 2 are ready, 3 is high priority, but not ready yet
+	assert.True(t, q.PushWithPriority(1, struct{}{}, base, 0, true))
+	assert.True(t, q.PushWithPriority(2, struct{}{}, base.Add(1), 1, true))
+	assert.True(t, q.PushWithPriority(3, struct{}{}, base.Add(10), 5, true))
+
+	// priority is only raised on duplicate push
+	assert.False(t, q.PushWithPriority(2, struct{}{}, base.Add(1), 0, true))
+
+	for _, i := range []int{2, 1} {
+		item, _, delay := q.Peek(base.Add(5))
+
+		queueItem, ok := item.Get()
+		require.True(t, ok)
+		assert.Equal(t, i, queueItem)
+		assert.Zero(t, delay)
+		assert.Equal(t, base.Add(time.Duration(i-1)), q.ReleaseAfter())
+		assert.Equal(t, i-1, q.Priority())
+
+		q.Pop()
+	}
+
+	assert.False(t, q.PushWithPriority(3, struct{}{}, base.Add(10), 7, true))
+
+	item, _, delay := q.Peek(base.Add(5))
+	require.False(t, item.IsPresent())
+	assert.Equal(t, time.Duration(5), delay)
+
+	item, _, _ = q.Peek(base.Add(10))
+
+	queueItem, ok := item.Get()
+	require.True(t, ok)
+	assert.Equal(t, 3, queueItem)
+	assert.Equal(t, 7, q.Priority())
+
+	q.Pop()
+
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestPriorityQueueAging(t *testing.T) {
+	q := containers.PriorityQueue[int, struct{}]{
+		Aging: time.Second,
+	}
+
+	var base time.Time
+
+	assert.True(t, q.PushWithPriority(1, struct{}{}, base, 0, true))
+	assert.True(t, q.PushWithPriority(2, struct{}{}, base.Add(5*time.Second), 10, true))
+
+	// high priority item wins while the low priority one is not waiting long enough
+	item, _, _ := q.Peek(base.Add(6 * time.Second))
+	assert.Equal(t, 2, item.ValueOrZero())
+
+	q.Pop()
+
+	// fresh high priority item
+	assert.True(t, q.PushWithPriority(3, struct{}{}, base.Add(12*time.Second), 10, true))
+
+	// low priority item has been waiting long enough to take over
+	item, _, _ = q.Peek(base.Add(12 * time.Second))
+	assert.Equal(t, 1, item.ValueOrZero())
+
+	q.Pop()
+
+	item, _, _ = q.Peek(base.Add(12 * time.Second))
+	assert.Equal(t, 3, item.ValueOrZero())
+}
+
+func TestPriorityQueueRaiseReady(t *testing.T) {
+	var q containers.PriorityQueue[int, struct{}]
+
+	var base time.Time
+
+	for _, i := range []int{1, 2, 3} {
+		assert.True(t, q.Push(i, struct{}{}, base, true))
+	}
+
+	item, _, _ := q.Peek(base)
+	assert.Equal(t, 1, item.ValueOrZero())
+
+	// the item which is already ready is re-ordered on priority raise
+	assert.False(t, q.PushWithPriority(3, struct{}{}, base.Add(time.Second), 5, true))
+
+	for _, i := range []int{3, 1, 2} {
+		item, _, _ = q.Peek(base)
+		assert.Equal(t, i, item.ValueOrZero())
+
+		q.Pop()
+	}
+
+	assert.Equal(t, 0, q.Len())
+}
+
 func BenchmarkPriorityQueue(b *testing.B) {
 	var q containers.PriorityQueue[int, string]
 
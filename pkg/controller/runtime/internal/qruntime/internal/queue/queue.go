@@ -7,6 +7,7 @@ package queue
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,31 +20,76 @@ import (
 // Queue is goroutine safe, Run() method should be called
 // in a separate goroutine for the queue to operate.
 type Queue[K comparable, V any] struct {
-	getCh     chan *Item[K, V]
-	releaseCh chan keyAndValueWithBackoff[K, V]
-	putCh     chan keyAndValue[K, V]
-	doneCh    chan struct{}
-	length    atomic.Int64
+	getCh       chan *Item[K, V]
+	releaseCh   chan keyAndValueWithBackoff[K, V]
+	putCh       chan keyAndValue[K, V]
+	doneCh      chan struct{}
+	deadLetters map[K]DeadLetter[K, V]
+	length      atomic.Int64
+	aging       time.Duration
+
+	deadLettersMu sync.Mutex
+}
+
+// DeadLetter is an item moved aside from the queue.
+type DeadLetter[K comparable, V any] struct {
+	Since    time.Time
+	Key      K
+	Value    V
+	Priority int
 }
 
 type keyAndValue[K comparable, V any] struct {
-	Key   K
-	Value V
+	Key      K
+	Value    V
+	Priority int
 }
 
 type keyAndValueWithBackoff[K comparable, V any] struct {
 	Key          K
 	Value        V
 	ReleaseAfter time.Time
+	Priority     int
+	DeadLetter   bool
+}
+
+// Option configures the Queue.
+type Option func(*options)
+
+type options struct {
+	aging time.Duration
+}
+
+// DefaultAging is the default aging interval of the queue.
+const DefaultAging = time.Second
+
+// WithAging raises the priority of the items waiting in the queue by one for each aging interval.
+//
+// Aging guarantees that low priority items are eventually processed even if the queue is flooded with high priority items.
+// Aging defaults to DefaultAging, zero interval disables aging.
+func WithAging(aging time.Duration) Option {
+	return func(opts *options) {
+		opts.aging = aging
+	}
 }
 
 // NewQueue creates a new queue.
-func NewQueue[K comparable, V any]() *Queue[K, V] {
+func NewQueue[K comparable, V any](opts ...Option) *Queue[K, V] {
+	queueOptions := options{
+		aging: DefaultAging,
+	}
+
+	for _, opt := range opts {
+		opt(&queueOptions)
+	}
+
 	return &Queue[K, V]{
-		getCh:     make(chan *Item[K, V]),
-		releaseCh: make(chan keyAndValueWithBackoff[K, V]),
-		putCh:     make(chan keyAndValue[K, V]),
-		doneCh:    make(chan struct{}),
+		getCh:       make(chan *Item[K, V]),
+		releaseCh:   make(chan keyAndValueWithBackoff[K, V]),
+		putCh:       make(chan keyAndValue[K, V]),
+		doneCh:      make(chan struct{}),
+		deadLetters: map[K]DeadLetter[K, V]{},
+		aging:       queueOptions.aging,
 	}
 }
 
@@ -57,9 +103,11 @@ func (queue *Queue[K, V]) Run(ctx context.Context) {
 		timer       timer.ResettableTimer
 		pqueue      containers.PriorityQueue[K, V]
 		onHold      containers.SliceSet[K]
-		onHoldQueue = map[K]V{}
+		onHoldQueue = map[K]keyAndValue[K, V]{}
 	)
 
+	pqueue.Aging = queue.aging
+
 	for {
 		var (
 			getCh              chan *Item[K, V]
@@ -72,7 +120,7 @@ func (queue *Queue[K, V]) Run(ctx context.Context) {
 
 		if topOfQueueKey, ok := topOfQueueK.Get(); ok {
 			getCh = queue.getCh
-			topOfQueueReleaser = newItem(topOfQueueKey, topOfQueueV.ValueOrZero(), queue)
+			topOfQueueReleaser = newItem(topOfQueueKey, topOfQueueV.ValueOrZero(), pqueue.ReleaseAfter(), pqueue.Priority(), queue)
 		}
 
 		select {
@@ -91,29 +139,43 @@ func (queue *Queue[K, V]) Run(ctx context.Context) {
 			//
 			// 1. any on-hold item equal to the released item should be put to the queue
 			// 2. if releaseAfter is non-zero, put the item back to the queue with the specified backoff
+			// 3. if the item is dead-lettered, and there is no fresh on-hold value, move it to the dead letters
 			onHold.Remove(released.Key)
 
+			if _, wasOnHold := onHoldQueue[released.Key]; released.DeadLetter && !wasOnHold {
+				queue.deadLettersMu.Lock()
+				queue.deadLetters[released.Key] = DeadLetter[K, V]{Since: time.Now(), Key: released.Key, Value: released.Value, Priority: released.Priority}
+				queue.deadLettersMu.Unlock()
+
+				continue
+			}
+
 			if !released.ReleaseAfter.IsZero() {
 				// released value might be stale, so don't overwrite if we have a fresh one
-				if pqueue.Push(released.Key, released.Value, released.ReleaseAfter, false) {
+				if pqueue.PushWithPriority(released.Key, released.Value, released.ReleaseAfter, released.Priority, false) {
 					queue.length.Add(1)
 				}
 			}
 
-			if onHoldValue, wasOnHold := onHoldQueue[released.Key]; wasOnHold {
+			if onHoldItem, wasOnHold := onHoldQueue[released.Key]; wasOnHold {
 				delete(onHoldQueue, released.Key)
 
 				// on hold value is fresh, so overwrite any previous value
-				if !pqueue.Push(released.Key, onHoldValue, time.Now(), true) {
+				if !pqueue.PushWithPriority(released.Key, onHoldItem.Value, time.Now(), onHoldItem.Priority, true) {
 					queue.length.Add(-1)
 				}
 			}
 		case item := <-queue.putCh:
-			// new item was Put to the queue
+			// new item was Put to the queue, so dead-lettered item is re-admitted
+			queue.deadLettersMu.Lock()
+			delete(queue.deadLetters, item.Key)
+			queue.deadLettersMu.Unlock()
+
 			if onHold.Contains(item.Key) {
 				// the item is on-hold
-				_, alreadyOnHold := onHoldQueue[item.Key]
-				onHoldQueue[item.Key] = item.Value
+				onHoldItem, alreadyOnHold := onHoldQueue[item.Key]
+				item.Priority = max(item.Priority, onHoldItem.Priority)
+				onHoldQueue[item.Key] = item
 
 				if !alreadyOnHold {
 					queue.length.Add(1)
@@ -123,7 +185,7 @@ func (queue *Queue[K, V]) Run(ctx context.Context) {
 			}
 
 			// new item has fresh value, overwrite
-			if pqueue.Push(item.Key, item.Value, time.Now(), true) {
+			if pqueue.PushWithPriority(item.Key, item.Value, time.Now(), item.Priority, true) {
 				queue.length.Add(1)
 			}
 		}
@@ -140,24 +202,83 @@ func (queue *Queue[K, V]) Get() <-chan *Item[K, V] {
 // Put should deduplicate Items, i.e. if the same Item is Put twice,
 // only one Item should be returned by Get.
 func (queue *Queue[K, V]) Put(key K, value V) {
+	queue.PutWithPriority(key, value, 0)
+}
+
+// PutWithPriority puts the Item to the queue with the specified priority.
+//
+// Items with higher priority are returned by Get first, if the same Item is Put
+// multiple times, the highest priority is kept.
+func (queue *Queue[K, V]) PutWithPriority(key K, value V, priority int) {
 	select {
-	case queue.putCh <- keyAndValue[K, V]{Key: key, Value: value}:
+	case queue.putCh <- keyAndValue[K, V]{Key: key, Value: value, Priority: priority}:
 	case <-queue.doneCh:
 	}
 }
 
 // Len returns the number of items in the queue.
 //
-// Len includes items which are on-hold.
+// Len includes items which are on-hold, but not the dead letters.
 func (queue *Queue[K, V]) Len() int64 {
 	return queue.length.Load()
 }
 
-func newItem[K comparable, V any](key K, value V, queue *Queue[K, V]) *Item[K, V] {
+// DeadLetters returns the items moved aside from the queue.
+//
+// Dead-lettered item is re-admitted to the queue when the item with the same key is Put to the queue.
+func (queue *Queue[K, V]) DeadLetters() []DeadLetter[K, V] {
+	queue.deadLettersMu.Lock()
+	defer queue.deadLettersMu.Unlock()
+
+	result := make([]DeadLetter[K, V], 0, len(queue.deadLetters))
+
+	for _, deadLetter := range queue.deadLetters {
+		result = append(result, deadLetter)
+	}
+
+	return result
+}
+
+// DeadLettersLen returns the number of dead letters.
+func (queue *Queue[K, V]) DeadLettersLen() int64 {
+	queue.deadLettersMu.Lock()
+	defer queue.deadLettersMu.Unlock()
+
+	return int64(len(queue.deadLetters))
+}
+
+// Readmit puts the dead-lettered items back to the queue.
+//
+// Readmit returns the number of items re-admitted.
+func (queue *Queue[K, V]) Readmit(keys ...K) int {
+	queue.deadLettersMu.Lock()
+
+	readmitted := make([]DeadLetter[K, V], 0, len(keys))
+
+	for _, key := range keys {
+		if deadLetter, ok := queue.deadLetters[key]; ok {
+			readmitted = append(readmitted, deadLetter)
+
+			delete(queue.deadLetters, key)
+		}
+	}
+
+	queue.deadLettersMu.Unlock()
+
+	for _, deadLetter := range readmitted {
+		queue.PutWithPriority(deadLetter.Key, deadLetter.Value, deadLetter.Priority)
+	}
+
+	return len(readmitted)
+}
+
+func newItem[K comparable, V any](key K, value V, readyAt time.Time, priority int, queue *Queue[K, V]) *Item[K, V] {
 	return &Item[K, V]{
-		key:   key,
-		value: value,
-		queue: queue,
+		key:      key,
+		value:    value,
+		readyAt:  readyAt,
+		priority: priority,
+		queue:    queue,
 	}
 }
 
@@ -165,9 +286,11 @@ func newItem[K comparable, V any](key K, value V, queue *Queue[K, V]) *Item[K, V
 //
 // Once the Value is processed, either Release() or Requeue() must be called.
 type Item[K comparable, V any] struct {
+	readyAt  time.Time
 	key      K
 	value    V
 	queue    *Queue[K, V]
+	priority int
 	released bool
 }
 
@@ -176,6 +299,16 @@ func (item *Item[K, V]) Get() (K, V) {
 	return item.key, item.value
 }
 
+// ReadyAt returns the time the Item became ready to be processed.
+func (item *Item[K, V]) ReadyAt() time.Time {
+	return item.readyAt
+}
+
+// Priority returns the priority the Item was queued with.
+func (item *Item[K, V]) Priority() int {
+	return item.priority
+}
+
 // Key returns just the key.
 func (item *Item[K, V]) Key() K {
 	return item.key
@@ -183,13 +316,34 @@ func (item *Item[K, V]) Key() K {
 
 // Release removes the Item from the queue.
 //
-// Calling Release after Requeue is a no-op.
+// Calling Release after Requeue or DeadLetter is a no-op.
 func (item *Item[K, V]) Release() {
 	item.Requeue(time.Time{})
 }
 
 // Requeue puts the Item back to the queue with specified backoff.
 func (item *Item[K, V]) Requeue(requeueAfter time.Time) {
+	item.release(keyAndValueWithBackoff[K, V]{
+		Key:          item.key,
+		Value:        item.value,
+		ReleaseAfter: requeueAfter,
+		Priority:     item.priority,
+	})
+}
+
+// DeadLetter moves the Item aside from the queue (see Queue.DeadLetters).
+//
+// If the Item was Put to the queue while being processed, it is queued again instead.
+func (item *Item[K, V]) DeadLetter() {
+	item.release(keyAndValueWithBackoff[K, V]{
+		Key:        item.key,
+		Value:      item.value,
+		Priority:   item.priority,
+		DeadLetter: true,
+	})
+}
+
+func (item *Item[K, V]) release(released keyAndValueWithBackoff[K, V]) {
 	if item.released {
 		return
 	}
@@ -197,11 +351,7 @@ func (item *Item[K, V]) Requeue(requeueAfter time.Time) {
 	item.released = true
 
 	select {
-	case item.queue.releaseCh <- keyAndValueWithBackoff[K, V]{
-		Key:          item.key,
-		Value:        item.value,
-		ReleaseAfter: requeueAfter,
-	}:
+	case item.queue.releaseCh <- released:
 	case <-item.queue.doneCh:
 	}
 }
@@ -127,3 +127,151 @@ waitLoop:
 		assert.GreaterOrEqual(t, tracker.processed[i], 50)
 	}
 }
+
+func TestQueueDeadLetter(t *testing.T) {
+	q := queue.NewQueue[int, string]()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error {
+		q.Run(ctx)
+
+		return nil
+	})
+
+	get := func() *queue.Item[int, string] {
+		select {
+		case item := <-q.Get():
+			return item
+		case <-ctx.Done():
+			require.FailNow(t, "timeout")
+		}
+
+		return nil
+	}
+
+	waitDeadLetters := func(expected int64) {
+		require.Eventually(t, func() bool { return q.DeadLettersLen() == expected }, time.Second, time.Millisecond)
+	}
+
+	q.Put(1, "a")
+
+	item := get()
+	item.DeadLetter()
+	item.Release() // no-op
+
+	waitDeadLetters(1)
+	assert.Equal(t, int64(0), q.Len())
+
+	deadLetters := q.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 1, deadLetters[0].Key)
+	assert.Equal(t, "a", deadLetters[0].Value)
+	assert.False(t, deadLetters[0].Since.IsZero())
+
+	// explicit readmit
+	assert.Zero(t, q.Readmit(2))
+	assert.Equal(t, 1, q.Readmit(1))
+	assert.Equal(t, int64(0), q.DeadLettersLen())
+
+	item = get()
+	assert.Equal(t, 1, item.Key())
+	item.DeadLetter()
+
+	waitDeadLetters(1)
+
+	// new value re-admits the item
+	q.Put(1, "b")
+
+	item = get()
+	k, v := item.Get()
+	assert.Equal(t, 1, k)
+	assert.Equal(t, "b", v)
+	assert.Equal(t, int64(0), q.DeadLettersLen())
+
+	// fresh value while processing prevents dead-lettering
+	q.Put(1, "c")
+
+	require.Eventually(t, func() bool { return q.Len() == 1 }, time.Second, time.Millisecond)
+
+	item.DeadLetter()
+
+	item = get()
+	_, v = item.Get()
+	assert.Equal(t, "c", v)
+	assert.Equal(t, int64(0), q.DeadLettersLen())
+
+	item.Release()
+
+	cancel()
+
+	require.NoError(t, eg.Wait())
+}
+
+func TestQueuePriority(t *testing.T) {
+	q := queue.NewQueue[int, string]()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error {
+		q.Run(ctx)
+
+		return nil
+	})
+
+	get := func() *queue.Item[int, string] {
+		select {
+		case item := <-q.Get():
+			return item
+		case <-ctx.Done():
+			require.FailNow(t, "timeout")
+		}
+
+		return nil
+	}
+
+	// Put is synchronous, so all items are in the queue before the first Get
+	q.PutWithPriority(1, "a", 0)
+	q.PutWithPriority(2, "b", 2)
+	q.PutWithPriority(3, "c", 1)
+	q.PutWithPriority(4, "d", 2)
+
+	for _, expected := range []int{2, 4, 3} {
+		item := get()
+		assert.Equal(t, expected, item.Key())
+		item.Release()
+	}
+
+	item := get()
+	assert.Equal(t, 1, item.Key())
+	assert.Equal(t, 0, item.Priority())
+
+	// on-hold item keeps the highest priority
+	q.PutWithPriority(1, "e", 3)
+	q.PutWithPriority(1, "f", 1)
+
+	item.Release()
+
+	item = get()
+	k, v := item.Get()
+	assert.Equal(t, 1, k)
+	assert.Equal(t, "f", v)
+	assert.Equal(t, 3, item.Priority())
+
+	// requeued item keeps the priority
+	item.Requeue(time.Now())
+
+	item = get()
+	assert.Equal(t, 3, item.Priority())
+	item.Release()
+
+	cancel()
+
+	require.NoError(t, eg.Wait())
+}
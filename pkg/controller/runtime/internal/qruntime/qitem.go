@@ -31,6 +31,17 @@ func (job QJob) String() string {
 	}
 }
 
+func (job QJob) spanName() string {
+	switch job {
+	case QJobReconcile:
+		return "Reconcile"
+	case QJobMap:
+		return "MapInput"
+	default:
+		return "Unknown"
+	}
+}
+
 // LogLevel returns the log level used for reconcile-outcome logs of this job kind.
 func (job QJob) LogLevel() zapcore.Level {
 	if job == QJobMap {
@@ -11,45 +11,56 @@ import (
 	"expvar"
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"sync"
 	"time"
+	"weak"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/siderolabs/gen/xerrors"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"golang.org/x/time/rate"
 
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/generic/qtransform"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/adapter"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/controllerstate"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/controllerstatus"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/qruntime/internal/queue"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/scheduler"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/watchdog"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/status"
 	"github.com/cosi-project/runtime/pkg/logging"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
 	"github.com/cosi-project/runtime/pkg/state/owned"
+	"github.com/cosi-project/runtime/pkg/tracing"
 )
 
-type resourceNamespaceType struct {
-	ns  resource.Namespace
-	typ resource.Type
-}
-
 // Adapter implements QRuntime interface for the QController.
 type Adapter struct {
 	queue          *queue.Queue[QKey, QValue]
 	logger         *zap.Logger
 	controller     controller.QController
 	queueLenExpVar *expvar.Int
+	status         *controllerstatus.Tracker
+	collector      *metrics.Collector
+	watchdog       watchdog.Watchdog
+	scheduler      *scheduler.Scheduler
+	itemLimiters   *itemLimiters
 
-	backoffs      map[QKey]*backoff.ExponentialBackOff
-	primaryInputs map[resourceNamespaceType]struct{}
+	backoffs      map[QKey]*itemBackoff
+	parked        map[QKey]parkedItem // protected by backoffsMu
+	backoffPolicy controller.BackoffPolicy
+	primaryInputs []controller.Input
 
 	controllerstate.StateAdapter
 	backoffsMu sync.Mutex
 
 	concurrency    uint
+	classPriority  int
 	metricsEnabled bool
 }
 
@@ -60,13 +71,24 @@ func NewAdapter(
 ) (*Adapter, error) {
 	name := ctrl.Name()
 	settings := ctrl.Settings()
-	primaryInputs := map[resourceNamespaceType]struct{}{}
+	var primaryInputs []controller.Input
 
 	concurrency := settings.Concurrency.ValueOr(DefaultConcurrency)
 	if concurrency == 0 {
 		return nil, fmt.Errorf("invalid concurrency: %d", concurrency)
 	}
 
+	var classPriority int
+
+	if settings.PriorityClass != "" {
+		var ok bool
+
+		classPriority, ok = adapterOptions.RuntimeOptions.PriorityClasses[settings.PriorityClass]
+		if !ok {
+			return nil, fmt.Errorf("unknown priority class %q for controller %q", settings.PriorityClass, name)
+		}
+	}
+
 	for _, output := range settings.Outputs {
 		if err := adapterOptions.DepDB.AddControllerOutput(name, output); err != nil {
 			return nil, err
@@ -79,7 +101,7 @@ func NewAdapter(
 			// allowed only for Controllers
 			return nil, fmt.Errorf("invalid input kind %d for controller %q", input.Kind, name)
 		case controller.InputQPrimary: // allowed only for QControllers
-			primaryInputs[resourceNamespaceType{ns: input.Namespace, typ: input.Type}] = struct{}{}
+			primaryInputs = append(primaryInputs, input)
 		case controller.InputQMapped, controller.InputQMappedDestroyReady: // allowed only for QControllers
 		}
 
@@ -87,44 +109,89 @@ func NewAdapter(
 			return nil, err
 		}
 
-		if err := adapterOptions.RegisterWatch(input.Namespace, input.Type); err != nil {
+		if err := adapterOptions.RegisterWatch(input); err != nil {
 			return nil, err
 		}
 	}
 
 	state := adapterOptions.State
 
-	var queueLenExpVar *expvar.Int
+	var (
+		queueLenExpVar *expvar.Int
+		collector      *metrics.Collector
+	)
 
 	if adapterOptions.RuntimeOptions.MetricsEnabled {
-		state = metrics.WrapState(name, adapterOptions.State)
+		collector = adapterOptions.RuntimeOptions.MetricsCollector
+		state = collector.WrapState(name, adapterOptions.State)
 
 		queueLenExpVar = &expvar.Int{}
 		metrics.QControllerQueueLength.Set(name, queueLenExpVar)
 	}
 
+	state, resourceCache, dryRunOverlay := adapterOptions.DryRun(name, state)
+
+	// in dry-run mode the reads go through the overlay which serves the cached resources from the cache
+	warnOnUncachedReads := adapterOptions.RuntimeOptions.WarnOnUncachedReads && dryRunOverlay == nil
+
 	logger := adapterOptions.Logger.With(zap.String("controller", name))
 
-	return &Adapter{
+	adapter := &Adapter{
 		StateAdapter: controllerstate.StateAdapter{
 			OwnedState:          owned.New(state, name),
-			Cache:               adapterOptions.Cache,
+			Cache:               resourceCache,
+			DryRun:              dryRunOverlay,
 			Name:                name,
-			UpdateLimiter:       rate.NewLimiter(adapterOptions.RuntimeOptions.ChangeRateLimit, adapterOptions.RuntimeOptions.ChangeBurst),
+			UpdateLimiter:       adapterOptions.NewUpdateLimiter(settings.RateLimits.Writes),
+			ReadLimiter:         adapter.NewLimiter(settings.RateLimits.UncachedReads),
 			Logger:              logger,
+			Tracer:              adapterOptions.Tracer(),
+			TraceRegistry:       adapterOptions.RuntimeOptions.TraceRegistry,
 			Inputs:              settings.Inputs,
 			Outputs:             settings.Outputs,
-			WarnOnUncachedReads: adapterOptions.RuntimeOptions.WarnOnUncachedReads,
+			CachedResources:     adapterOptions.RuntimeOptions.CachedResources,
+			WarnOnUncachedReads: warnOnUncachedReads,
+			MetricsEnabled:      adapterOptions.RuntimeOptions.MetricsEnabled,
 		},
-		queue:          queue.NewQueue[QKey, QValue](),
-		backoffs:       map[QKey]*backoff.ExponentialBackOff{},
+		queue:          queue.NewQueue[QKey, QValue](queue.WithAging(adapterOptions.RuntimeOptions.PriorityAging)),
+		backoffs:       map[QKey]*itemBackoff{},
+		parked:         map[QKey]parkedItem{},
+		backoffPolicy:  settings.Backoff,
 		logger:         logger,
 		controller:     ctrl,
 		queueLenExpVar: queueLenExpVar,
+		collector:      collector,
+		watchdog: watchdog.Watchdog{
+			Logger:         logger,
+			Name:           name,
+			Settings:       settings.ReconcileTimeout,
+			MetricsEnabled: adapterOptions.RuntimeOptions.MetricsEnabled,
+		},
+		scheduler:      adapterOptions.Scheduler,
+		itemLimiters:   newItemLimiters(settings.RateLimits.ItemReconciles),
 		concurrency:    concurrency,
+		classPriority:  classPriority,
 		primaryInputs:  primaryInputs,
 		metricsEnabled: adapterOptions.RuntimeOptions.MetricsEnabled,
-	}, nil
+		status:         adapterOptions.Status,
+	}
+
+	adapter.status.SetQueueLength(adapter.queue.Len)
+
+	if adapter.metricsEnabled {
+		// the queue is referenced weakly, so that the metric doesn't keep the unregistered controller alive
+		queueRef := weak.Make(adapter.queue)
+
+		metrics.QControllerDeadLetters.Set(name, expvar.Func(func() any {
+			if q := queueRef.Value(); q != nil {
+				return q.DeadLettersLen()
+			}
+
+			return 0
+		}))
+	}
+
+	return adapter, nil
 }
 
 // DefaultConcurrency is the default concurrency for the QController.
@@ -134,6 +201,14 @@ const DefaultConcurrency = 1
 func (adapter *Adapter) Run(ctx context.Context) {
 	adapter.logger.Debug("controller starting")
 
+	adapter.status.SetState(status.StateRunning)
+	defer adapter.status.SetState(status.StateStopped)
+
+	// the plan of the previous run is dropped, as it might be stale
+	if adapter.DryRun != nil {
+		adapter.DryRun.Reset()
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 
 	eg.Go(func() error {
@@ -164,7 +239,7 @@ func (adapter *Adapter) Run(ctx context.Context) {
 				continue
 			}
 
-			if err := adapter.listPrimary(ctx, input.Namespace, input.Type); err != nil {
+			if err := adapter.listPrimary(ctx, input); err != nil {
 				if errors.Is(err, context.Canceled) {
 					return nil
 				}
@@ -185,13 +260,22 @@ func (adapter *Adapter) Run(ctx context.Context) {
 	adapter.logger.Debug("controller finished")
 }
 
-func (adapter *Adapter) listPrimary(ctx context.Context, resourceNamespace resource.Namespace, resourceType resource.Type) error {
+func (adapter *Adapter) listPrimary(ctx context.Context, input controller.Input) error {
+	resourceNamespace, resourceType := input.Namespace, input.Type
+
+	var listOpts []state.ListOption
+
+	if input.NamespacePattern != "" {
+		resourceNamespace = input.NamespacePattern
+		listOpts = append(listOpts, state.WithListNamespacePattern(input.NamespacePattern))
+	}
+
 	backoff := backoff.NewExponentialBackOff()
 	backoff.MaxElapsedTime = 0
 
 	for {
 		// use StateAdapter.List here, so that if the resource is cached, it would be listed from the cache
-		items, err := adapter.List(ctx, resource.NewMetadata(resourceNamespace, resourceType, "", resource.VersionUndefined))
+		items, err := adapter.List(ctx, resource.NewMetadata(resourceNamespace, resourceType, "", resource.VersionUndefined), listOpts...)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				return err
@@ -218,7 +302,7 @@ func (adapter *Adapter) listPrimary(ctx context.Context, resourceNamespace resou
 
 		for _, item := range items.Items {
 			qitem := NewQItem(item.Metadata(), QJobReconcile)
-			adapter.queue.Put(qitem.QKey, qitem.QValue)
+			adapter.queue.PutWithPriority(qitem.QKey, qitem.QValue, input.Priority)
 		}
 
 		adapter.logger.Debug(
@@ -247,8 +331,29 @@ func (adapter *Adapter) runReconcile(ctx context.Context) {
 		case item = <-adapter.queue.Get():
 		}
 
+		if delay := adapter.itemLimiters.delay(item.Key(), time.Now()); delay > 0 {
+			// the item is reconciled too often, put it back to the queue until the rate limit allows it
+			if adapter.metricsEnabled {
+				metrics.QControllerReconcileThrottled.AddFloat(adapter.Name, delay.Seconds())
+			}
+
+			item.Requeue(time.Now().Add(delay))
+
+			continue
+		}
+
+		adapter.collector.ObserveQueueWait(adapter.Name, time.Since(item.ReadyAt()))
+		adapter.unpark(item.Key())
+
+		if err := adapter.scheduler.Acquire(ctx, adapter.classPriority); err != nil {
+			item.Release()
+
+			return
+		}
+
 		func() {
 			defer item.Release()
+			defer adapter.scheduler.Release()
 
 			logger := adapter.logger.With(
 				zap.String("namespace", item.Key().Namespace()),
@@ -260,7 +365,7 @@ func (adapter *Adapter) runReconcile(ctx context.Context) {
 			start := time.Now()
 
 			itemKey, itemValue := item.Get()
-			reconcileError := adapter.runOnce(ctx, logger, QItem{itemKey, itemValue})
+			reconcileError := adapter.runOnce(ctx, logger, QItem{itemKey, itemValue}, item.Priority())
 
 			busy := time.Since(start)
 
@@ -290,8 +395,10 @@ func (adapter *Adapter) runReconcile(ctx context.Context) {
 
 				if item.Key().job == QJobReconcile {
 					metrics.QControllerReconcileBusy.AddFloat(adapter.Name, busy.Seconds())
+					adapter.collector.ObserveReconcile(adapter.Name, busy)
 				} else {
 					metrics.QControllerMapBusy.AddFloat(adapter.Name, busy.Seconds())
+					adapter.collector.ObserveMap(adapter.Name, busy)
 				}
 			}
 
@@ -307,10 +414,30 @@ func (adapter *Adapter) runReconcile(ctx context.Context) {
 					zapSkipIfZero(requeued, zap.Bool("requeued", requeued)),
 				)
 			case reconcileError != nil:
+				backoffInterval, retry := adapter.getBackoffInterval(itemKey, reconcileError)
+
+				if !retry {
+					interval = 0
+
+					item.DeadLetter()
+
+					adapter.status.ReconcileFailed(reconcileError, time.Time{})
+
+					logger.Error(
+						"reconcile failed, item parked",
+						zap.Error(reconcileError),
+						zap.Duration("busy", busy),
+					)
+
+					break
+				}
+
 				if interval == 0 {
-					interval = adapter.getBackoffInterval(item.Key())
+					interval = backoffInterval
 				}
 
+				adapter.status.ReconcileFailed(reconcileError, time.Now().Add(interval))
+
 				logger.Error(
 					"reconcile failed",
 					zap.Error(reconcileError),
@@ -321,6 +448,10 @@ func (adapter *Adapter) runReconcile(ctx context.Context) {
 			default:
 				adapter.clearBackoff(item.Key())
 
+				if item.Key().job == QJobReconcile {
+					adapter.status.Reconciled()
+				}
+
 				logger.Log(
 					item.Key().job.LogLevel(), "reconcile succeeded",
 					zap.Duration("busy", busy),
@@ -346,7 +477,30 @@ func zapSkipIfZero[T comparable](val T, f zap.Field) zap.Field {
 	return f
 }
 
-func (adapter *Adapter) runOnce(ctx context.Context, logger *zap.Logger, item QItem) (err error) {
+func (adapter *Adapter) runOnce(ctx context.Context, logger *zap.Logger, item QItem, priority int) (err error) {
+	if adapter.Tracer != nil {
+		var span trace.Span
+
+		ctx, span = adapter.Tracer.Start(ctx, "qcontroller."+item.job.spanName(),
+			trace.WithAttributes(tracing.AttributeController.String(adapter.Name)),
+			trace.WithAttributes(tracing.ResourceAttributes(item.Namespace(), item.Type(), item.ID())...),
+			trace.WithLinks(tracing.Links(item.value.TraceParent)...),
+		)
+
+		defer func() { tracing.End(span, err) }()
+	}
+
+	ctx, stopWatchdog := adapter.watchdog.Arm(ctx)
+
+	defer func() {
+		stopWatchdog()
+
+		// reconcile canceled by the watchdog is a failure, so that it's retried with backoff
+		if timeoutErr := adapter.watchdog.TimedOut(ctx); timeoutErr != nil {
+			err = timeoutErr
+		}
+	}()
+
 	defer func() {
 		if err != nil && errors.Is(err, context.Canceled) {
 			err = nil
@@ -378,13 +532,23 @@ func (adapter *Adapter) runOnce(ctx context.Context, logger *zap.Logger, item QI
 
 		if err == nil {
 			for _, mappedItem := range mappedItems {
-				if _, valid := adapter.primaryInputs[resourceNamespaceType{ns: mappedItem.Namespace(), typ: mappedItem.Type()}]; !valid {
+				if !slices.ContainsFunc(adapter.primaryInputs, func(in controller.Input) bool { return in.Matches(mappedItem.Namespace(), mappedItem.Type()) }) {
 					panic(fmt.Sprintf("unexpected primary input in the mapped output: %s/%s", mappedItem.Namespace(), mappedItem.Type()))
 				}
 
 				mappedMd := resource.NewMetadata(mappedItem.Namespace(), mappedItem.Type(), mappedItem.ID(), resource.VersionUndefined)
 				qitem := NewQItem(&mappedMd, QJobReconcile)
-				adapter.queue.Put(qitem.QKey, qitem.QValue)
+				// link the reconcile of the mapped item to the map call
+				qitem.value.TraceParent = tracing.TraceParent(ctx)
+
+				// mapped items inherit the priority of the mapped input, unless overridden
+				mappedPriority := priority
+
+				if prioritized, ok := mappedItem.(controller.PrioritizedPointer); ok {
+					mappedPriority = prioritized.Priority
+				}
+
+				adapter.queue.PutWithPriority(qitem.QKey, qitem.QValue, mappedPriority)
 			}
 		}
 	}
@@ -417,6 +581,8 @@ func (adapter *Adapter) runWithBackoff(ctx context.Context, hook func(context.Co
 
 		logger.Sugar().Debugf("restarting the run hook in %s", interval)
 
+		adapter.status.Failed(err, time.Now().Add(interval))
+
 		select {
 		case <-ctx.Done():
 			return
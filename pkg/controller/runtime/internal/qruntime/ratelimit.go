@@ -0,0 +1,76 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package qruntime
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/adapter"
+)
+
+// itemLimiterSweepInterval is the interval to drop the rate limiters of the items which are idle.
+const itemLimiterSweepInterval = time.Minute
+
+// itemLimiters keeps a rate limiter per queue item.
+type itemLimiters struct {
+	lastSweep time.Time
+	limiters  map[QKey]*rate.Limiter
+	limit     controller.RateLimit
+
+	mu sync.Mutex
+}
+
+func newItemLimiters(limit controller.RateLimit) *itemLimiters {
+	return &itemLimiters{
+		limit:    limit,
+		limiters: map[QKey]*rate.Limiter{},
+	}
+}
+
+// delay returns the interval the item should be delayed by, or zero if the item can be processed now.
+func (limiters *itemLimiters) delay(key QKey, now time.Time) time.Duration {
+	if limiters.limit.IsZero() {
+		return 0
+	}
+
+	limiters.mu.Lock()
+	defer limiters.mu.Unlock()
+
+	limiters.sweep(now)
+
+	limiter, ok := limiters.limiters[key]
+	if !ok {
+		limiter = adapter.NewLimiter(limiters.limit)
+
+		limiters.limiters[key] = limiter
+	}
+
+	if limiter.AllowN(now, 1) {
+		return 0
+	}
+
+	interval := time.Duration((1 - limiter.TokensAt(now)) / float64(limiter.Limit()) * float64(time.Second))
+
+	return max(interval, time.Millisecond)
+}
+
+// sweep drops the limiters which are full, as they are equivalent to the new ones.
+func (limiters *itemLimiters) sweep(now time.Time) {
+	if now.Sub(limiters.lastSweep) < itemLimiterSweepInterval {
+		return
+	}
+
+	limiters.lastSweep = now
+
+	for key, limiter := range limiters.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(limiters.limiters, key)
+		}
+	}
+}
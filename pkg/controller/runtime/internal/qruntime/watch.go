@@ -13,18 +13,18 @@ import (
 func (adapter *Adapter) WatchTrigger(md *reduced.Metadata) {
 	// figure out the type: primary or mapped, and queue accordingly
 	for _, in := range adapter.Inputs {
-		if in.Namespace == md.Namespace && in.Type == md.Typ {
+		if in.Matches(md.Namespace, md.Typ) {
 			switch in.Kind {
 			case controller.InputQPrimary:
 				item := NewQItemFromReduced(md, QJobReconcile)
-				adapter.queue.Put(item.QKey, item.QValue)
+				adapter.queue.PutWithPriority(item.QKey, item.QValue, in.Priority)
 			case controller.InputQMapped:
 				item := NewQItemFromReduced(md, QJobMap)
-				adapter.queue.Put(item.QKey, item.QValue)
+				adapter.queue.PutWithPriority(item.QKey, item.QValue, in.Priority)
 			case controller.InputQMappedDestroyReady:
 				if reduced.FilterDestroyReady(md) {
 					item := NewQItemFromReduced(md, QJobMap)
-					adapter.queue.Put(item.QKey, item.QValue)
+					adapter.queue.PutWithPriority(item.QKey, item.QValue, in.Priority)
 				}
 			}
 		}
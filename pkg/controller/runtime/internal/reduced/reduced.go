@@ -5,7 +5,9 @@
 // Package reduced implements reducing resource metadata to a comparable value.
 package reduced
 
-import "github.com/cosi-project/runtime/pkg/resource"
+import (
+	"github.com/cosi-project/runtime/pkg/resource"
+)
 
 // Metadata reduces resource metadata for deduplication.
 //
@@ -27,6 +29,7 @@ type Key struct {
 // Value is a reduced representation of resource metadata.
 type Value struct {
 	Labels          *resource.Labels
+	TraceParent     string
 	Phase           resource.Phase
 	FinalizersEmpty bool
 }
@@ -9,19 +9,21 @@ import (
 	"fmt"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/adapter"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/controllerstate"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/controllerstatus"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/dependency"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/reduced"
-	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/watchdog"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
-	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/logging"
 	"github.com/cosi-project/runtime/pkg/state/owned"
 )
 
@@ -29,7 +31,8 @@ import (
 type Adapter struct {
 	logger    *zap.Logger
 	depDB     *dependency.Database
-	watchFunc func(resource.Namespace, resource.Type) error
+	status    *controllerstatus.Tracker
+	watchFunc func(controller.Input) error
 
 	ctrl controller.Controller
 
@@ -39,6 +42,13 @@ type Adapter struct {
 
 	watchFilters map[watchKey]reduced.WatchFilter
 
+	// span of the current controller iteration (if tracing is enabled)
+	iterationSpan  trace.Span
+	iterationLinks []trace.Link
+
+	// time the reconcile event was put to the channel
+	pendingSince time.Time
+
 	// output tracker (optional)
 	//
 	// if nil, tracking is not enabled
@@ -46,9 +56,15 @@ type Adapter struct {
 
 	controllerstate.StateAdapter
 
+	watchdog watchdog.Watchdog
+
 	runtimeOptions options.Options
 	// watchFilterMu protects watchFilters
 	watchFilterMu sync.Mutex
+	// iterationMu protects iterationSpan and iterationLinks
+	iterationMu sync.Mutex
+	// pendingMu protects pendingSince
+	pendingMu sync.Mutex
 }
 
 // NewAdapter creates a new Adapter for the Controller.
@@ -58,31 +74,70 @@ func NewAdapter(
 ) (*Adapter, error) {
 	state := adapterOptions.State
 
+	var backoffPolicy controller.BackoffPolicy
+
+	if backoffCtrl, ok := ctrl.(controller.BackoffController); ok {
+		backoffPolicy = backoffCtrl.BackoffPolicy()
+	}
+
+	restartBackoff := adapter.NewBackOff(backoffPolicy)
+
+	var rateLimits controller.RateLimits
+
+	if rateLimitedCtrl, ok := ctrl.(controller.RateLimitedController); ok {
+		rateLimits = rateLimitedCtrl.RateLimits()
+	}
+
+	updateLimiter := adapterOptions.NewUpdateLimiter(rateLimits.Writes)
+	readLimiter := adapter.NewLimiter(rateLimits.UncachedReads)
+
 	if adapterOptions.RuntimeOptions.MetricsEnabled {
-		state = metrics.WrapState(ctrl.Name(), adapterOptions.State)
+		state = adapterOptions.RuntimeOptions.MetricsCollector.WrapState(ctrl.Name(), adapterOptions.State)
 	}
 
+	state, resourceCache, dryRunOverlay := adapterOptions.DryRun(ctrl.Name(), state)
+
+	// in dry-run mode the reads go through the overlay which serves the cached resources from the cache
+	warnOnUncachedReads := adapterOptions.RuntimeOptions.WarnOnUncachedReads && dryRunOverlay == nil
+
 	adapter := &Adapter{
 		StateAdapter: controllerstate.StateAdapter{
 			Name:                ctrl.Name(),
 			OwnedState:          owned.New(state, ctrl.Name()),
-			Cache:               adapterOptions.Cache,
+			Cache:               resourceCache,
+			DryRun:              dryRunOverlay,
 			Outputs:             slices.Clone(ctrl.Outputs()),
-			UpdateLimiter:       rate.NewLimiter(adapterOptions.RuntimeOptions.ChangeRateLimit, adapterOptions.RuntimeOptions.ChangeBurst),
+			UpdateLimiter:       updateLimiter,
+			ReadLimiter:         readLimiter,
 			Logger:              adapterOptions.Logger,
-			WarnOnUncachedReads: adapterOptions.RuntimeOptions.WarnOnUncachedReads,
+			Tracer:              adapterOptions.Tracer(),
+			TraceRegistry:       adapterOptions.RuntimeOptions.TraceRegistry,
+			CachedResources:     adapterOptions.RuntimeOptions.CachedResources,
+			WarnOnUncachedReads: warnOnUncachedReads,
+			MetricsEnabled:      adapterOptions.RuntimeOptions.MetricsEnabled,
 		},
 		runtimeOptions: adapterOptions.RuntimeOptions,
 		logger:         adapterOptions.Logger,
 		depDB:          adapterOptions.DepDB,
+		status:         adapterOptions.Status,
 		ctrl:           ctrl,
 		ch:             make(chan controller.ReconcileEvent, 1),
-		backoff:        backoff.NewExponentialBackOff(),
+		backoff:        restartBackoff,
 		watchFunc:      adapterOptions.RegisterWatch,
 	}
 
-	// disable number of retries limit
-	adapter.backoff.MaxElapsedTime = 0
+	if adapter.Tracer != nil {
+		adapter.TraceContext = adapter.iterationContext
+	}
+
+	if timeoutCtrl, ok := ctrl.(controller.TimeoutController); ok {
+		adapter.watchdog = watchdog.Watchdog{
+			Logger:         adapterOptions.Logger.With(logging.Controller(ctrl.Name())),
+			Name:           ctrl.Name(),
+			Settings:       timeoutCtrl.ReconcileTimeout(),
+			MetricsEnabled: adapterOptions.RuntimeOptions.MetricsEnabled,
+		}
+	}
 
 	for _, output := range adapter.Outputs {
 		if err := adapter.depDB.AddControllerOutput(adapter.Name, output); err != nil {
@@ -113,6 +168,8 @@ func (adapter *Adapter) QueueReconcile() {
 // ResetRestartBackoff implements controller.Runtime interface.
 func (adapter *Adapter) ResetRestartBackoff() {
 	adapter.backoff.Reset()
+	adapter.status.Reconciled()
+	adapter.endIteration(nil)
 }
 
 // UpdateInputs implements controller.Runtime interface.
@@ -127,6 +184,11 @@ func (adapter *Adapter) UpdateInputs(deps []controller.Input) error {
 			// allowed only for QControllers
 			return fmt.Errorf("invalid input kind %d for controller %q", dep.Kind, adapter.Name)
 		}
+
+		// watch filters are kept per namespace
+		if dep.Kind == controller.InputDestroyReady && dep.NamespacePattern != "" {
+			return fmt.Errorf("namespace pattern is not supported for DestroyReady input of controller %q", adapter.Name)
+		}
 	}
 
 	dbDeps, err := adapter.depDB.GetControllerInputs(adapter.Name)
@@ -181,7 +243,7 @@ func (adapter *Adapter) UpdateInputs(deps []controller.Input) error {
 				adapter.addWatchFilter(deps[i].Namespace, deps[i].Type, reduced.FilterDestroyReady)
 			}
 
-			if err := adapter.watchFunc(deps[i].Namespace, deps[i].Type); err != nil {
+			if err := adapter.watchFunc(deps[i]); err != nil {
 				return fmt.Errorf("error watching resources: %w", err)
 			}
 
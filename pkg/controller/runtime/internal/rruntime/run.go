@@ -14,6 +14,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/status"
 	"github.com/cosi-project/runtime/pkg/logging"
 )
 
@@ -21,7 +22,16 @@ import (
 func (adapter *Adapter) Run(ctx context.Context) {
 	logger := adapter.logger.With(logging.Controller(adapter.Name))
 
+	defer adapter.status.SetState(status.StateStopped)
+
 	for {
+		adapter.status.SetState(status.StateRunning)
+
+		// the plan of the previous run is dropped, as it might be stale
+		if adapter.DryRun != nil {
+			adapter.DryRun.Reset()
+		}
+
 		err := adapter.runOnce(ctx, logger)
 		if err == nil {
 			return
@@ -35,6 +45,9 @@ func (adapter *Adapter) Run(ctx context.Context) {
 
 		logger.Sugar().Debugf("restarting controller in %s", interval)
 
+		adapter.status.Failed(err, time.Now().Add(interval))
+		adapter.status.SetState(status.StateBackoff)
+
 		select {
 		case <-ctx.Done():
 			return
@@ -60,8 +73,35 @@ func (adapter *Adapter) runOnce(ctx context.Context, logger *zap.Logger) (err er
 
 		// clean up output tracker on any exit from Run method
 		adapter.outputTracker = nil
+
+		adapter.endIteration(err)
 	}()
 
+	if adapter.watchdog.Enabled() {
+		var (
+			cancel       context.CancelCauseFunc
+			watchdogDone = make(chan struct{})
+		)
+
+		ctx, cancel = context.WithCancelCause(ctx)
+
+		go func() {
+			defer close(watchdogDone)
+
+			adapter.runWatchdog(ctx, cancel)
+		}()
+
+		defer func() {
+			cancel(nil)
+			<-watchdogDone
+
+			// controller canceled by the watchdog is restarted with backoff
+			if timeoutErr := adapter.watchdog.TimedOut(ctx); timeoutErr != nil {
+				err = timeoutErr
+			}
+		}()
+	}
+
 	defer func() {
 		if p := recover(); p != nil {
 			err = fmt.Errorf("controller %q panicked: %s\n\n%s", adapter.Name, p, string(debug.Stack()))
@@ -0,0 +1,71 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package rruntime
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cosi-project/runtime/pkg/tracing"
+)
+
+// maxIterationLinks limits the number of links to the triggering writes recorded for a single iteration.
+const maxIterationLinks = 32
+
+// iterationContext returns the context with the span of the current controller iteration.
+//
+// Controller iteration starts with the first read or write after the previous iteration ended,
+// and it ends with a call to ResetRestartBackoff or on controller exit.
+func (adapter *Adapter) iterationContext(ctx context.Context) context.Context {
+	// controller manages spans on its own
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	adapter.iterationMu.Lock()
+	defer adapter.iterationMu.Unlock()
+
+	if adapter.iterationSpan == nil {
+		_, adapter.iterationSpan = adapter.Tracer.Start(ctx, "controller.Run",
+			trace.WithAttributes(tracing.AttributeController.String(adapter.Name)),
+			trace.WithLinks(adapter.iterationLinks...),
+		)
+
+		adapter.iterationLinks = nil
+	}
+
+	return trace.ContextWithSpan(ctx, adapter.iterationSpan)
+}
+
+// endIteration ends the span of the current controller iteration.
+func (adapter *Adapter) endIteration(err error) {
+	if adapter.Tracer == nil {
+		return
+	}
+
+	adapter.iterationMu.Lock()
+	defer adapter.iterationMu.Unlock()
+
+	if adapter.iterationSpan != nil {
+		tracing.End(adapter.iterationSpan, err)
+
+		adapter.iterationSpan = nil
+	}
+}
+
+// linkIteration records the write which triggered the next iteration.
+func (adapter *Adapter) linkIteration(traceParent string) {
+	if adapter.Tracer == nil || traceParent == "" {
+		return
+	}
+
+	adapter.iterationMu.Lock()
+	defer adapter.iterationMu.Unlock()
+
+	if len(adapter.iterationLinks) < maxIterationLinks {
+		adapter.iterationLinks = append(adapter.iterationLinks, tracing.Links(traceParent)...)
+	}
+}
@@ -5,6 +5,8 @@
 package rruntime
 
 import (
+	"time"
+
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/reduced"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
@@ -46,14 +48,20 @@ func (adapter *Adapter) WatchTrigger(md *reduced.Metadata) {
 		}
 	}
 
+	adapter.linkIteration(md.TraceParent)
 	adapter.triggerReconcile()
 }
 
 func (adapter *Adapter) triggerReconcile() {
+	adapter.pendingMu.Lock()
+	defer adapter.pendingMu.Unlock()
+
 	// schedule reconcile if channel is empty
 	// otherwise channel is not empty, and reconcile is anyway scheduled
 	select {
 	case adapter.ch <- controller.ReconcileEvent{}:
+		adapter.pendingSince = time.Now()
+
 		if adapter.runtimeOptions.MetricsEnabled {
 			metrics.ControllerWakeups.Add(adapter.Name, 1)
 		}
@@ -0,0 +1,62 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package rruntime
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+)
+
+// runWatchdog watches the controller loop iterations until the context is canceled.
+//
+// The iteration is considered to be stuck if the reconcile event is not picked up by the controller within the timeout.
+func (adapter *Adapter) runWatchdog(ctx context.Context, cancel context.CancelCauseFunc) {
+	started := time.Now()
+
+	ticker := time.NewTicker(adapter.watchdog.Settings.Timeout / 4)
+	defer ticker.Stop()
+
+	var reported time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pendingSince, pending := adapter.pendingEvent()
+		if !pending || pendingSince.Equal(reported) {
+			continue
+		}
+
+		// events queued before the controller was (re)started don't count
+		elapsed := time.Since(pendingSince)
+		elapsed = min(elapsed, time.Since(started))
+
+		if elapsed < adapter.watchdog.Settings.Timeout {
+			continue
+		}
+
+		reported = pendingSince
+
+		adapter.watchdog.Expired(elapsed)
+
+		if adapter.watchdog.Settings.Cancel {
+			cancel(controller.ErrReconcileTimeout)
+
+			return
+		}
+	}
+}
+
+func (adapter *Adapter) pendingEvent() (time.Time, bool) {
+	adapter.pendingMu.Lock()
+	defer adapter.pendingMu.Unlock()
+
+	return adapter.pendingSince, len(adapter.ch) > 0
+}
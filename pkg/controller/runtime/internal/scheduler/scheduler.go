@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package scheduler limits the number of concurrent reconciles across controllers.
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Scheduler grants a limited number of reconcile slots to the controllers by priority.
+//
+// Waiters with the same priority are granted the slots in the order of arrival.
+// If aging is set, the priority of a waiter is raised by one for each aging interval
+// it has been waiting, so that low priority controllers still progress.
+//
+// Nil Scheduler doesn't limit the reconciles.
+type Scheduler struct {
+	waiters []*waiter
+	aging   time.Duration
+	free    uint
+
+	mu sync.Mutex
+}
+
+type waiter struct {
+	since    time.Time
+	ready    chan struct{}
+	priority int
+}
+
+// New creates a new Scheduler with the specified number of slots.
+func New(slots uint, aging time.Duration) *Scheduler {
+	return &Scheduler{
+		free:  slots,
+		aging: aging,
+	}
+}
+
+// Acquire waits for a free slot.
+//
+// Acquire returns an error if the context is canceled before the slot is granted.
+// Once the slot is no longer needed, Release should be called.
+func (scheduler *Scheduler) Acquire(ctx context.Context, priority int) error {
+	if scheduler == nil {
+		return nil
+	}
+
+	scheduler.mu.Lock()
+
+	if scheduler.free > 0 && len(scheduler.waiters) == 0 {
+		scheduler.free--
+		scheduler.mu.Unlock()
+
+		return nil
+	}
+
+	w := &waiter{
+		since:    time.Now(),
+		ready:    make(chan struct{}),
+		priority: priority,
+	}
+
+	scheduler.waiters = append(scheduler.waiters, w)
+	scheduler.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	scheduler.mu.Lock()
+
+	idx := slices.Index(scheduler.waiters, w)
+	if idx != -1 {
+		scheduler.waiters = slices.Delete(scheduler.waiters, idx, idx+1)
+	}
+
+	scheduler.mu.Unlock()
+
+	if idx == -1 {
+		// the slot was granted concurrently with the cancellation, pass it on
+		scheduler.Release()
+	}
+
+	return ctx.Err()
+}
+
+// Release returns the slot acquired with Acquire.
+func (scheduler *Scheduler) Release() {
+	if scheduler == nil {
+		return
+	}
+
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+
+	if len(scheduler.waiters) == 0 {
+		scheduler.free++
+
+		return
+	}
+
+	now := time.Now()
+	top, topPriority := 0, scheduler.effectivePriority(scheduler.waiters[0], now)
+
+	for idx := 1; idx < len(scheduler.waiters); idx++ {
+		if priority := scheduler.effectivePriority(scheduler.waiters[idx], now); priority > topPriority {
+			top, topPriority = idx, priority
+		}
+	}
+
+	w := scheduler.waiters[top]
+	scheduler.waiters = slices.Delete(scheduler.waiters, top, top+1)
+
+	close(w.ready)
+}
+
+func (scheduler *Scheduler) effectivePriority(w *waiter, now time.Time) int {
+	priority := w.priority
+
+	if scheduler.aging > 0 {
+		priority += int(now.Sub(w.since) / scheduler.aging)
+	}
+
+	return priority
+}
@@ -0,0 +1,119 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/scheduler"
+)
+
+func TestSchedulerPriority(t *testing.T) {
+	s := scheduler.New(1, 0)
+
+	require.NoError(t, s.Acquire(t.Context(), 0))
+
+	var (
+		mu    sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+
+	for _, priority := range []int{1, 3, 2} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := s.Acquire(t.Context(), priority); err != nil {
+				return
+			}
+
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+
+			s.Release()
+		}()
+
+		// make sure waiters are queued in order
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.Release()
+	wg.Wait()
+
+	assert.Equal(t, []int{3, 2, 1}, order)
+}
+
+func TestSchedulerAging(t *testing.T) {
+	s := scheduler.New(1, 10*time.Millisecond)
+
+	require.NoError(t, s.Acquire(t.Context(), 0))
+
+	lowCh := make(chan struct{})
+
+	go func() {
+		if s.Acquire(t.Context(), 0) == nil {
+			close(lowCh)
+		}
+	}()
+
+	// low priority waiter ages above the high priority one
+	time.Sleep(100 * time.Millisecond)
+
+	highCh := make(chan struct{})
+
+	go func() {
+		if s.Acquire(t.Context(), 5) == nil {
+			close(highCh)
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.Release()
+
+	select {
+	case <-lowCh:
+	case <-time.After(time.Second):
+		require.FailNow(t, "low priority waiter starved")
+	}
+
+	s.Release()
+
+	<-highCh
+
+	s.Release()
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := scheduler.New(1, 0)
+
+	require.NoError(t, s.Acquire(t.Context(), 0))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, s.Acquire(ctx, 0), context.DeadlineExceeded)
+
+	s.Release()
+
+	// the slot is free again
+	require.NoError(t, s.Acquire(t.Context(), 0))
+}
+
+func TestSchedulerNil(t *testing.T) {
+	var s *scheduler.Scheduler
+
+	require.NoError(t, s.Acquire(t.Context(), 0))
+	s.Release()
+}
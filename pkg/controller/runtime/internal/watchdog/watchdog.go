@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package watchdog detects controller reconciles which exceed their deadline.
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+)
+
+// Watchdog reports stuck reconciles of a single controller.
+type Watchdog struct {
+	Logger         *zap.Logger
+	Name           string
+	Settings       controller.TimeoutSettings
+	MetricsEnabled bool
+}
+
+// Enabled returns true if the deadline is configured.
+func (wd *Watchdog) Enabled() bool {
+	return wd.Settings.Timeout > 0
+}
+
+// Arm the watchdog for a single reconcile.
+//
+// Returned context is canceled with ErrReconcileTimeout cause if the deadline is exceeded and cancellation is enabled.
+// Stop function should be called once the reconcile is done.
+func (wd *Watchdog) Arm(ctx context.Context) (context.Context, func()) {
+	if !wd.Enabled() {
+		return ctx, func() {}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	timer := time.AfterFunc(wd.Settings.Timeout, func() {
+		wd.Expired(wd.Settings.Timeout)
+
+		if wd.Settings.Cancel {
+			cancel(controller.ErrReconcileTimeout)
+		}
+	})
+
+	return ctx, func() {
+		timer.Stop()
+		cancel(nil)
+	}
+}
+
+// Expired reports the reconcile which has been running for the elapsed time.
+func (wd *Watchdog) Expired(elapsed time.Duration) {
+	if wd.MetricsEnabled {
+		metrics.ControllerReconcileTimeouts.Add(wd.Name, 1)
+	}
+
+	wd.Logger.Error("reconcile deadline exceeded",
+		zap.Duration("timeout", wd.Settings.Timeout),
+		zap.Duration("elapsed", elapsed),
+		zap.Bool("cancel", wd.Settings.Cancel),
+		zap.String("stacks", goroutineStacks()),
+	)
+}
+
+// TimedOut returns an error wrapping ErrReconcileTimeout if the context returned by Arm was canceled by the watchdog.
+func (wd *Watchdog) TimedOut(ctx context.Context) error {
+	if cause := context.Cause(ctx); errors.Is(cause, controller.ErrReconcileTimeout) {
+		return fmt.Errorf("%w after %s", cause, wd.Settings.Timeout)
+	}
+
+	return nil
+}
+
+func goroutineStacks() string {
+	buf := make([]byte, 64*1024)
+
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -2,7 +2,7 @@
 // License, v. 2.0. If a copy of the MPL was not distributed with this
 // file, You can obtain one at http://mozilla.org/MPL/2.0/.
 
-// Package metrics expose various controller runtime metrics using expvar.
+// Package metrics expose various controller runtime metrics using expvar and Prometheus.
 package metrics
 
 import (
@@ -26,6 +26,15 @@ var (
 	// Each call to controller.Writer is counted as a single write.
 	ControllerWrites = expvar.NewMap("controller_writes")
 
+	// ControllerReconcileTimeouts counts the number of reconciles which exceeded the deadline per controller (both Controller and QController).
+	ControllerReconcileTimeouts = expvar.NewMap("controller_reconcile_timeouts")
+
+	// ControllerWriteThrottled reports the number of seconds controller writes were throttled by the rate limiter.
+	ControllerWriteThrottled = expvar.NewMap("controller_write_throttled")
+
+	// ControllerReadThrottled reports the number of seconds controller uncached reads were throttled by the rate limiter.
+	ControllerReadThrottled = expvar.NewMap("controller_read_throttled")
+
 	// QControllerCrashes counts the number of crashes per QController.
 	QControllerCrashes = expvar.NewMap("qcontroller_crashes")
 
@@ -47,12 +56,24 @@ var (
 	// QControllerQueueLength reports the outstanding queue length per QController (both map and reconcile events).
 	QControllerQueueLength = expvar.NewMap("qcontroller_queue_length")
 
+	// QControllerDeadLetters reports the number of parked (dead-lettered) items per QController.
+	QControllerDeadLetters = expvar.NewMap("qcontroller_dead_letters")
+
+	// QControllerReconcileThrottled reports the number of seconds QController items were delayed by the item reconcile rate limiter.
+	QControllerReconcileThrottled = expvar.NewMap("qcontroller_reconcile_throttled")
+
 	// QControllerMapBusy reports the number of seconds QController was busy processing map events.
 	QControllerMapBusy = expvar.NewMap("qcontroller_map_busy")
 
 	// QControllerReconcileBusy reports the number of seconds QController was busy processing reconcile events.
 	QControllerReconcileBusy = expvar.NewMap("qcontroller_reconcile_busy")
 
+	// WatchRestarts counts the number of times the runtime watch was re-established per resource type.
+	WatchRestarts = expvar.NewMap("watch_restarts")
+
 	// CachedResources reports the number of cached resources per resource type.
 	CachedResources = expvar.NewMap("cached_resources")
+
+	// CacheOverflows counts the number of times the resource cache exceeded the size cap per resource type.
+	CacheOverflows = expvar.NewMap("cache_overflows")
 )
@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metrics
+
+import (
+	"expvar"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "cosi"
+
+type expvarMetric struct {
+	m         *expvar.Map
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+}
+
+// Collector exposes controller runtime metrics to Prometheus.
+//
+// Collector reports the expvar metrics of this package with proper labels,
+// and keeps latency histograms which are recorded by the runtime configured with the Collector
+// (see options.WithMetricsCollector).
+//
+// Collector should be registered with the Prometheus registry by the caller.
+// Nil Collector ignores all observations.
+type Collector struct {
+	reconcileDuration *prometheus.HistogramVec
+	mapDuration       *prometheus.HistogramVec
+	queueWait         *prometheus.HistogramVec
+	stateOperation    *prometheus.HistogramVec
+
+	expvars []expvarMetric
+}
+
+// NewCollector creates a new Collector.
+func NewCollector() *Collector {
+	controllerLabel := []string{"controller"}
+
+	newExpvar := func(m *expvar.Map, name, help string, valueType prometheus.ValueType, label string) expvarMetric {
+		return expvarMetric{
+			m:         m,
+			desc:      prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, []string{label}, nil),
+			valueType: valueType,
+		}
+	}
+
+	return &Collector{
+		reconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "qcontroller",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Duration of QController reconcile events.",
+			Buckets:   prometheus.DefBuckets,
+		}, controllerLabel),
+		mapDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "qcontroller",
+			Name:      "map_duration_seconds",
+			Help:      "Duration of QController map events.",
+			Buckets:   prometheus.DefBuckets,
+		}, controllerLabel),
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "qcontroller",
+			Name:      "queue_wait_seconds",
+			Help:      "Time QController events spent in the queue after becoming ready to be processed.",
+			Buckets:   prometheus.DefBuckets,
+		}, controllerLabel),
+		stateOperation: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "controller",
+			Name:      "state_operation_duration_seconds",
+			Help:      "Latency of state operations performed by controllers.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"controller", "operation"}),
+		expvars: []expvarMetric{
+			newExpvar(ControllerCrashes, "controller_crashes_total", "Number of crashes per Controller.", prometheus.CounterValue, "controller"),
+			newExpvar(ControllerWakeups, "controller_wakeups_total", "Number of wakeups per Controller.", prometheus.CounterValue, "controller"),
+			newExpvar(ControllerReads, "controller_reads_total", "Number of reads per controller.", prometheus.CounterValue, "controller"),
+			newExpvar(ControllerWrites, "controller_writes_total", "Number of writes per controller.", prometheus.CounterValue, "controller"),
+			newExpvar(ControllerReconcileTimeouts, "controller_reconcile_timeouts_total", "Number of reconciles which exceeded the deadline per controller.", prometheus.CounterValue, "controller"),
+			newExpvar(ControllerWriteThrottled, "controller_write_throttled_seconds_total", "Number of seconds controller writes were throttled.", prometheus.CounterValue, "controller"),
+			newExpvar(ControllerReadThrottled, "controller_read_throttled_seconds_total", "Number of seconds controller uncached reads were throttled.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerCrashes, "qcontroller_crashes_total", "Number of crashes per QController.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerSkips, "qcontroller_skips_total", "Number of skipped reconcile events per QController.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerRequeues, "qcontroller_requeues_total", "Number of requeue events per QController.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerProcessed, "qcontroller_processed_total", "Number of processed reconcile events per QController.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerMappedIn, "qcontroller_mapped_in_total", "Number of map events per QController.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerMappedOut, "qcontroller_mapped_out_total", "Number of outputs for map events per QController.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerQueueLength, "qcontroller_queue_length", "Outstanding queue length per QController.", prometheus.GaugeValue, "controller"),
+			newExpvar(QControllerDeadLetters, "qcontroller_dead_letters", "Number of parked (dead-lettered) items per QController.", prometheus.GaugeValue, "controller"),
+			newExpvar(QControllerReconcileThrottled, "qcontroller_reconcile_throttled_seconds_total", "Number of seconds QController items were delayed by the item rate limiter.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerMapBusy, "qcontroller_map_busy_seconds_total", "Number of seconds QController was busy processing map events.", prometheus.CounterValue, "controller"),
+			newExpvar(QControllerReconcileBusy, "qcontroller_reconcile_busy_seconds_total", "Number of seconds QController was busy processing reconcile events.", prometheus.CounterValue, "controller"),
+			newExpvar(WatchRestarts, "watch_restarts_total", "Number of times the runtime watch was re-established per resource type.", prometheus.CounterValue, "resource_type"),
+			newExpvar(CachedResources, "cached_resources", "Number of cached resources per resource type.", prometheus.GaugeValue, "resource_type"),
+			newExpvar(CacheOverflows, "cache_overflows_total", "Number of times the resource cache exceeded the size cap per resource type.", prometheus.CounterValue, "resource_type"),
+		},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range collector.expvars {
+		ch <- metric.desc
+	}
+
+	collector.reconcileDuration.Describe(ch)
+	collector.mapDuration.Describe(ch)
+	collector.queueWait.Describe(ch)
+	collector.stateOperation.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, metric := range collector.expvars {
+		metric.m.Do(func(kv expvar.KeyValue) {
+			// expvar.Int and expvar.Float are both formatted as plain numbers
+			value, err := strconv.ParseFloat(kv.Value.String(), 64)
+			if err != nil {
+				return
+			}
+
+			ch <- prometheus.MustNewConstMetric(metric.desc, metric.valueType, value, kv.Key)
+		})
+	}
+
+	collector.reconcileDuration.Collect(ch)
+	collector.mapDuration.Collect(ch)
+	collector.queueWait.Collect(ch)
+	collector.stateOperation.Collect(ch)
+}
+
+// ObserveReconcile records the duration of a QController reconcile event.
+func (collector *Collector) ObserveReconcile(controllerName string, duration time.Duration) {
+	if collector == nil {
+		return
+	}
+
+	collector.reconcileDuration.WithLabelValues(controllerName).Observe(duration.Seconds())
+}
+
+// ObserveMap records the duration of a QController map event.
+func (collector *Collector) ObserveMap(controllerName string, duration time.Duration) {
+	if collector == nil {
+		return
+	}
+
+	collector.mapDuration.WithLabelValues(controllerName).Observe(duration.Seconds())
+}
+
+// ObserveQueueWait records the time a QController event spent in the queue.
+func (collector *Collector) ObserveQueueWait(controllerName string, duration time.Duration) {
+	if collector == nil {
+		return
+	}
+
+	collector.queueWait.WithLabelValues(controllerName).Observe(duration.Seconds())
+}
+
+// ObserveStateOperation records the latency of a state operation performed by a controller.
+func (collector *Collector) ObserveStateOperation(controllerName, operation string, duration time.Duration) {
+	if collector == nil {
+		return
+	}
+
+	collector.stateOperation.WithLabelValues(controllerName, operation).Observe(duration.Seconds())
+}
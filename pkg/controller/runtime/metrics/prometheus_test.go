@@ -0,0 +1,110 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metrics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	collector := metrics.NewCollector()
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	metrics.QControllerCrashes.Add("TestCollector", 3)
+	metrics.QControllerReconcileBusy.AddFloat("TestCollector", 1.5)
+	metrics.CachedResources.Add("TestCollectorType", 7)
+
+	collector.ObserveReconcile("TestCollector", time.Second)
+	collector.ObserveMap("TestCollector", time.Millisecond)
+	collector.ObserveQueueWait("TestCollector", time.Millisecond)
+
+	st := collector.WrapState("TestCollector", state.WrapCore(namespaced.NewState(inmem.Build)))
+
+	_, err := st.Get(context.Background(), resource.NewMetadata("default", "type", "id", resource.VersionUndefined))
+	require.Error(t, err)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	find := func(name string, labels map[string]string) *dto.Metric {
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+
+		metricLoop:
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+						continue metricLoop
+					}
+				}
+
+				return metric
+			}
+		}
+
+		return nil
+	}
+
+	crashes := find("cosi_qcontroller_crashes_total", map[string]string{"controller": "TestCollector"})
+	require.NotNil(t, crashes)
+	assert.EqualValues(t, 3, crashes.GetCounter().GetValue())
+
+	busy := find("cosi_qcontroller_reconcile_busy_seconds_total", map[string]string{"controller": "TestCollector"})
+	require.NotNil(t, busy)
+	assert.EqualValues(t, 1.5, busy.GetCounter().GetValue())
+
+	cached := find("cosi_cached_resources", map[string]string{"resource_type": "TestCollectorType"})
+	require.NotNil(t, cached)
+	assert.EqualValues(t, 7, cached.GetGauge().GetValue())
+
+	reconcile := find("cosi_qcontroller_reconcile_duration_seconds", map[string]string{"controller": "TestCollector"})
+	require.NotNil(t, reconcile)
+	assert.EqualValues(t, 1, reconcile.GetHistogram().GetSampleCount())
+	assert.EqualValues(t, 1, reconcile.GetHistogram().GetSampleSum())
+
+	for _, name := range []string{"cosi_qcontroller_map_duration_seconds", "cosi_qcontroller_queue_wait_seconds"} {
+		histogram := find(name, map[string]string{"controller": "TestCollector"})
+		require.NotNil(t, histogram, name)
+		assert.EqualValues(t, 1, histogram.GetHistogram().GetSampleCount(), name)
+	}
+
+	stateOp := find("cosi_controller_state_operation_duration_seconds", map[string]string{"controller": "TestCollector", "operation": "get"})
+	require.NotNil(t, stateOp)
+	assert.EqualValues(t, 1, stateOp.GetHistogram().GetSampleCount())
+
+	reads := find("cosi_controller_reads_total", map[string]string{"controller": "TestCollector"})
+	require.NotNil(t, reads)
+	assert.EqualValues(t, 1, reads.GetCounter().GetValue())
+}
+
+func TestCollectorNil(t *testing.T) {
+	t.Parallel()
+
+	var collector *metrics.Collector
+
+	assert.NotPanics(t, func() {
+		collector.ObserveReconcile("TestCollectorNil", time.Second)
+		collector.ObserveStateOperation("TestCollectorNil", "get", time.Second)
+	})
+}
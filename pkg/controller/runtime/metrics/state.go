@@ -6,6 +6,7 @@ package metrics
 
 import (
 	"context"
+	"time"
 
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
@@ -13,61 +14,90 @@ import (
 
 type metricsWrapper struct {
 	innerState     state.CoreState
+	collector      *Collector
 	controllerName string
 }
 
+func (m *metricsWrapper) observe(operation string, start time.Time) {
+	m.collector.ObserveStateOperation(m.controllerName, operation, time.Since(start))
+}
+
 func (m *metricsWrapper) Get(ctx context.Context, pointer resource.Pointer, option ...state.GetOption) (resource.Resource, error) {
 	ControllerReads.Add(m.controllerName, 1)
 
+	defer m.observe("get", time.Now())
+
 	return m.innerState.Get(ctx, pointer, option...)
 }
 
 func (m *metricsWrapper) List(ctx context.Context, kind resource.Kind, option ...state.ListOption) (resource.List, error) {
 	ControllerReads.Add(m.controllerName, 1)
 
+	defer m.observe("list", time.Now())
+
 	return m.innerState.List(ctx, kind, option...)
 }
 
 func (m *metricsWrapper) Create(ctx context.Context, resource resource.Resource, option ...state.CreateOption) error {
 	ControllerWrites.Add(m.controllerName, 1)
 
+	defer m.observe("create", time.Now())
+
 	return m.innerState.Create(ctx, resource, option...)
 }
 
 func (m *metricsWrapper) Update(ctx context.Context, newResource resource.Resource, opts ...state.UpdateOption) error {
 	ControllerWrites.Add(m.controllerName, 1)
 
+	defer m.observe("update", time.Now())
+
 	return m.innerState.Update(ctx, newResource, opts...)
 }
 
 func (m *metricsWrapper) Destroy(ctx context.Context, pointer resource.Pointer, option ...state.DestroyOption) error {
 	ControllerWrites.Add(m.controllerName, 1)
 
+	defer m.observe("destroy", time.Now())
+
 	return m.innerState.Destroy(ctx, pointer, option...)
 }
 
 func (m *metricsWrapper) Watch(ctx context.Context, pointer resource.Pointer, events chan<- state.Event, option ...state.WatchOption) error {
 	ControllerReads.Add(m.controllerName, 1)
 
+	defer m.observe("watch", time.Now())
+
 	return m.innerState.Watch(ctx, pointer, events, option...)
 }
 
 func (m *metricsWrapper) WatchKind(ctx context.Context, kind resource.Kind, events chan<- state.Event, option ...state.WatchKindOption) error {
 	ControllerReads.Add(m.controllerName, 1)
 
+	defer m.observe("watch_kind", time.Now())
+
 	return m.innerState.WatchKind(ctx, kind, events, option...)
 }
 
 func (m *metricsWrapper) WatchKindAggregated(ctx context.Context, kind resource.Kind, c chan<- []state.Event, option ...state.WatchKindOption) error {
 	ControllerReads.Add(m.controllerName, 1)
 
+	defer m.observe("watch_kind_aggregated", time.Now())
+
 	return m.innerState.WatchKindAggregated(ctx, kind, c, option...)
 }
 
 // WrapState wraps state.State with metrics for the given controller name.
 func WrapState(controllerName string, st state.State) state.State {
+	return (*Collector)(nil).WrapState(controllerName, st)
+}
+
+// WrapState wraps state.State with metrics for the given controller name.
+//
+// In addition to WrapState, the latency of state operations is recorded by the Collector.
+func (collector *Collector) WrapState(controllerName string, st state.State) state.State {
 	return state.WrapCore(&metricsWrapper{
 		controllerName: controllerName,
+		collector:      collector,
 		innerState:     st,
 	})
 }
@@ -6,36 +6,189 @@
 package options
 
 import (
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/time/rate"
 
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/tracing"
 )
 
 // Options configures controller runtime.
 type Options struct {
 	// CachedResources is a list of resources that should be cached by controller runtime.
 	CachedResources []CachedResource
+	// ExpiringResources is a list of resources which are torn down and destroyed by controller runtime once expired.
+	ExpiringResources []ExpiringResource
+	// GarbageCollectedResources is a list of resources which are garbage collected by controller runtime based on owner references.
+	GarbageCollectedResources []GarbageCollectedResource
+	// LeaderElection if set, makes the runtime run controllers only while holding the lease.
+	LeaderElection *LeaderElection
+	// DryRun if set, makes the controllers plan their writes instead of applying them.
+	DryRun *DryRun
+	// TracerProvider if set, enables OpenTelemetry tracing of the controllers.
+	TracerProvider trace.TracerProvider
+	// TraceRegistry keeps the trace context of the writes, if not set, the runtime creates its own registry.
+	TraceRegistry *tracing.Registry
+	// ControllerStatusNamespace if set, enables publishing of ControllerStatus resources to the namespace.
+	ControllerStatusNamespace resource.Namespace
 	// ChangeRateLimit and ChangeBurst configure rate limiting of changes performed by controllers.
 	ChangeRateLimit rate.Limit
 	ChangeBurst     int
 	// MetricsEnabled enables runtime metrics to be exposed via metrics package.
 	MetricsEnabled bool
+	// MetricsCollector if set, records latency histograms for the Prometheus collector (requires MetricsEnabled).
+	MetricsCollector *metrics.Collector
+	// PriorityClasses maps the QController priority class names to the priorities (see controller.QSettings.PriorityClass).
+	PriorityClasses map[string]int
+	// ReconcileSlots if set, limits the number of QController reconciles running concurrently across all controllers.
+	ReconcileSlots uint
+	// PriorityAging raises the priority of the waiting QController items by one for each interval, zero disables aging.
+	PriorityAging time.Duration
+	// WatchRecovery configures re-establishing of the failed runtime watches.
+	WatchRecovery WatchRecovery
 	// WarnOnUncachedReads adds a warning log when a controller reads an uncached resource.
 	WarnOnUncachedReads bool
+	// ReadOnlyChecks enables verification that the resources returned by the read-only cached reads are not modified.
+	ReadOnlyChecks bool
+}
+
+// WatchRecovery configures re-establishing of the failed runtime watches.
+type WatchRecovery struct {
+	// Backoff is the backoff policy between the attempts to re-establish the watch (MaxAttempts is ignored).
+	Backoff controller.BackoffPolicy
+	// Budget is the number of consecutive watch failures tolerated before Run fails, zero disables the recovery.
+	Budget int
 }
 
 // CachedResource is a resource that should be cached by controller runtime.
 type CachedResource struct {
+	// Indexes maps the index names to the index functions (see WithIndex).
+	Indexes   map[string]IndexFunc
+	Namespace resource.Namespace
+	// NamespacePattern (if set) caches the resources across the namespaces matching the pattern (see WithCachedResourcePattern).
+	NamespacePattern string
+	Type             resource.Type
+	// MaxSize (if set) caps the number of cached resources (see WithMaxSize).
+	MaxSize int
+	// MetadataOnly caches only the metadata of the resources (see WithMetadataOnly).
+	MetadataOnly bool
+}
+
+// IndexFunc returns the index keys of the resource.
+//
+// Resource might have any number of keys in the index (including none).
+type IndexFunc func(resource.Resource) []string
+
+// CachedResourceOption is a functional option for the cached resource.
+type CachedResourceOption func(*CachedResource)
+
+// WithIndex declares a named index of the cached resource.
+//
+// Controllers list the resources by the index key with controller.IndexReader (see safe.ReaderListByIndex),
+// which is served from the cache without a full scan.
+func WithIndex(name string, fn IndexFunc) CachedResourceOption {
+	return func(cached *CachedResource) {
+		if cached.Indexes == nil {
+			cached.Indexes = map[string]IndexFunc{}
+		}
+
+		cached.Indexes[name] = fn
+	}
+}
+
+// WithMetadataOnly caches only the metadata of the resources (labels, phase, finalizers, version, etc.), dropping the spec.
+//
+// List (and ListByIndex) are served from the cache, returning the resources which carry only the metadata
+// (*resource.MetadataOnly), which is enough e.g. for mapping the inputs to the outputs.
+// Such resources should be read as resource.Resource (the typed safe helpers return an error), and they can't be written.
+// Get falls back to the uncached read of the full resource.
+// The index functions (see WithIndex) are called on the full resource.
+func WithMetadataOnly() CachedResourceOption {
+	return func(cached *CachedResource) {
+		cached.MetadataOnly = true
+	}
+}
+
+// WithMaxSize caps the number of the cached resources (per namespace for WithCachedResourcePattern).
+//
+// Once the cap is exceeded, the cached resources are dropped, and the reads of the resource fall back
+// to the uncached reads (see metrics.CacheOverflows). Once the number of the resources drops to half of the cap,
+// the watch of the resource is bootstrapped again to recover the cache.
+func WithMaxSize(size int) CachedResourceOption {
+	return func(cached *CachedResource) {
+		cached.MaxSize = size
+	}
+}
+
+// ExpiringResource is a resource kind which is garbage collected by controller runtime based on the expiration timestamp.
+type ExpiringResource struct {
 	Namespace resource.Namespace
 	Type      resource.Type
 }
 
+// CascadeMode defines how the teardown of a parent resource is propagated to its children.
+type CascadeMode int
+
+// CascadeMode constants.
+const (
+	// CascadeBackground tears down children once the parent is torn down or destroyed, without blocking the parent.
+	CascadeBackground CascadeMode = iota
+	// CascadeForeground blocks parent destruction (via a finalizer) until all children are destroyed.
+	CascadeForeground
+	// CascadeOrphan leaves children in place and only reports them as orphans once the parent is destroyed.
+	CascadeOrphan
+)
+
+// GarbageCollectedResource is a resource kind which is garbage collected by controller runtime based on owner references.
+type GarbageCollectedResource struct {
+	Namespace resource.Namespace
+	Type      resource.Type
+	Mode      CascadeMode
+}
+
+// LeaderElection configures the lease which should be held by the runtime to run controllers.
+type LeaderElection struct {
+	// Namespace and ID of the lease resource (see lease.Lease).
+	Namespace resource.Namespace
+	ID        resource.ID
+	// Holder is the identity of this runtime instance.
+	Holder string
+	// LeaseDuration is the duration of the lease since the last renewal.
+	LeaseDuration time.Duration
+	// RenewInterval is the interval between lease renewals (and acquire attempts), should be less than LeaseDuration.
+	//
+	// If the lease is not renewed within LeaseDuration - RenewInterval since the last renewal, controllers are stopped,
+	// so that they are stopped before the lease might be acquired by another holder.
+	RenewInterval time.Duration
+}
+
+// DryRun configures the controllers which run in dry-run mode.
+type DryRun struct {
+	// Controllers is the list of the controller names, empty list means all controllers.
+	Controllers []string
+}
+
+// Enabled returns true if the controller runs in dry-run mode.
+func (dryRun *DryRun) Enabled(controllerName string) bool {
+	if dryRun == nil {
+		return false
+	}
+
+	return len(dryRun.Controllers) == 0 || slices.Contains(dryRun.Controllers, controllerName)
+}
+
 // Option is a functional option for controller runtime.
 type Option func(*Options)
 
 // WithChangeRateLimit sets rate limit for changes performed by controllers.
 //
 // This might be used to rate limit ill-behaving controllers from overloading the system with changes.
+// The limit applies to each controller separately, and it might be overridden per controller (see controller.RateLimits).
 func WithChangeRateLimit(limit rate.Limit, burst int) Option {
 	return func(options *Options) {
 		options.ChangeRateLimit = limit
@@ -50,16 +203,146 @@ func WithMetrics(enabled bool) Option {
 	}
 }
 
+// WithMetricsCollector enables recording of the latency histograms exposed by the Prometheus collector.
+//
+// The collector should be registered with the Prometheus registry by the caller,
+// it exposes the metrics of the metrics package along with the histograms.
+func WithMetricsCollector(collector *metrics.Collector) Option {
+	return func(options *Options) {
+		options.MetricsCollector = collector
+	}
+}
+
 // WithCachedResource adds a resource to the list of resources that should be cached by controller runtime.
-func WithCachedResource(namespace resource.Namespace, typ resource.Type) Option {
+func WithCachedResource(namespace resource.Namespace, typ resource.Type, opts ...CachedResourceOption) Option {
+	return func(options *Options) {
+		cached := CachedResource{
+			Namespace: namespace,
+			Type:      typ,
+		}
+
+		for _, opt := range opts {
+			opt(&cached)
+		}
+
+		options.CachedResources = append(options.CachedResources, cached)
+	}
+}
+
+// WithCachedResourcePattern adds a resource type across the namespaces matching the pattern to the list of resources
+// that should be cached by controller runtime.
+//
+// The pattern uses the path.Match syntax, "*" caches the resource type across all namespaces,
+// including the namespaces created after the runtime is started.
+// The resources are watched with a single watch (see state.WithNamespacePattern), which should be supported by the state.
+func WithCachedResourcePattern(pattern string, typ resource.Type, opts ...CachedResourceOption) Option {
 	return func(options *Options) {
-		options.CachedResources = append(options.CachedResources, CachedResource{
+		cached := CachedResource{
+			NamespacePattern: pattern,
+			Type:             typ,
+		}
+
+		for _, opt := range opts {
+			opt(&cached)
+		}
+
+		options.CachedResources = append(options.CachedResources, cached)
+	}
+}
+
+// WithExpiringResource enables collection of expired resources of the specified kind.
+//
+// Resources with expiration timestamp (see resource.Metadata.SetExpires) in the past are torn down,
+// and destroyed once all finalizers are removed.
+func WithExpiringResource(namespace resource.Namespace, typ resource.Type) Option {
+	return func(options *Options) {
+		options.ExpiringResources = append(options.ExpiringResources, ExpiringResource{
 			Namespace: namespace,
 			Type:      typ,
 		})
 	}
 }
 
+// WithGarbageCollection enables cascading garbage collection of resources of the specified kind.
+//
+// Resources of the kind which have owner references (see resource.Metadata.OwnerReferences) are torn down and destroyed
+// once all of their parents are torn down or destroyed, according to the cascade mode.
+// Children whose parents don't exist are reported as orphans.
+func WithGarbageCollection(namespace resource.Namespace, typ resource.Type, mode CascadeMode) Option {
+	return func(options *Options) {
+		options.GarbageCollectedResources = append(options.GarbageCollectedResources, GarbageCollectedResource{
+			Namespace: namespace,
+			Type:      typ,
+			Mode:      mode,
+		})
+	}
+}
+
+// WithLeaderElection makes the runtime start controllers only after acquiring the lease.
+//
+// Run waits for the lease to be acquired, and it returns an error if the lease is lost.
+// The lease is released when Run returns.
+//
+// The lease is renewed every renewInterval, which should be less than leaseDuration.
+func WithLeaderElection(namespace resource.Namespace, id resource.ID, holder string, leaseDuration, renewInterval time.Duration) Option {
+	return func(options *Options) {
+		options.LeaderElection = &LeaderElection{
+			Namespace:     namespace,
+			ID:            id,
+			Holder:        holder,
+			LeaseDuration: leaseDuration,
+			RenewInterval: renewInterval,
+		}
+	}
+}
+
+// WithDryRun makes the controllers (or all controllers if none are specified) run in dry-run mode.
+//
+// Writes of the controller in dry-run mode are applied to a copy-on-write overlay over the state (see dryrun package),
+// the state itself is never modified. Controller reads see its own writes, but other controllers don't,
+// and the overlay changes don't trigger any watches. Cached reads are served from the state bypassing the cache.
+//
+// Planned changes are logged, and they can be retrieved with Runtime.DryRunPlan.
+func WithDryRun(controllers ...string) Option {
+	return func(options *Options) {
+		options.DryRun = &DryRun{
+			Controllers: controllers,
+		}
+	}
+}
+
+// WithTracerProvider enables OpenTelemetry tracing of the controllers.
+//
+// Spans are created for each QController reconcile and map call, Controller run iteration (between
+// calls to ResetRestartBackoff) and each controller read and write. Writes record the trace context
+// in the trace registry (see tracing.Registry), so that the spans of the controllers which react to
+// the change are linked to the span of the write.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(options *Options) {
+		options.TracerProvider = tp
+	}
+}
+
+// WithTraceRegistry sets the registry which keeps the trace context of the writes.
+//
+// The registry might be shared with the other writers in the same process (e.g. the State gRPC server),
+// so that the controllers link their spans to the spans of those writes.
+func WithTraceRegistry(registry *tracing.Registry) Option {
+	return func(options *Options) {
+		options.TraceRegistry = registry
+	}
+}
+
+// WithControllerStatus enables publishing of the status of each controller as a ControllerStatus resource
+// (see status package) to the specified namespace.
+//
+// Statuses are published while the runtime is running, status of an unregistered controller is destroyed.
+func WithControllerStatus(namespace resource.Namespace) Option {
+	return func(options *Options) {
+		options.ControllerStatusNamespace = namespace
+	}
+}
+
 // WithWarnOnUncachedReads adds a warning log when a controller reads an uncached resource.
 func WithWarnOnUncachedReads(warn bool) Option {
 	return func(options *Options) {
@@ -67,11 +350,76 @@ func WithWarnOnUncachedReads(warn bool) Option {
 	}
 }
 
+// WithReadOnlyChecks enables verification that the resources returned by the read-only cached reads are not modified.
+//
+// Controllers read the shared cached resources with controller.ReadOnlyReader (see safe.ReaderListReadOnly).
+// With the checks enabled, the checksum of each resource is recorded when it is returned for the first time,
+// and it is verified on the subsequent reads, so that once the modification is detected, the read-only reads
+// of the resource type fail. Checks are expensive, they are supposed to be used in tests and for debugging.
+func WithReadOnlyChecks(enabled bool) Option {
+	return func(options *Options) {
+		options.ReadOnlyChecks = enabled
+	}
+}
+
+// WithPriorityClass defines a priority class for QControllers.
+//
+// QControllers assign themselves to a priority class via controller.QSettings.PriorityClass.
+// When the reconcile slots are limited (see WithReconcileSlots), free slots are granted to the controllers
+// with higher priority class first.
+func WithPriorityClass(name string, priority int) Option {
+	return func(options *Options) {
+		if options.PriorityClasses == nil {
+			options.PriorityClasses = map[string]int{}
+		}
+
+		options.PriorityClasses[name] = priority
+	}
+}
+
+// WithReconcileSlots limits the number of QController reconciles (and map calls) running concurrently across all controllers.
+//
+// Slots are granted by the priority class of the controller, zero value (default) means no limit.
+func WithReconcileSlots(slots uint) Option {
+	return func(options *Options) {
+		options.ReconcileSlots = slots
+	}
+}
+
+// WithPriorityAging sets the interval which raises the priority of the waiting QController items by one.
+//
+// Aging applies both to the items in the controller queue and to the controllers waiting for the reconcile slots,
+// so that low priority work still progresses. Aging defaults to DefaultPriorityAging, zero interval disables aging.
+func WithPriorityAging(interval time.Duration) Option {
+	return func(options *Options) {
+		options.PriorityAging = interval
+	}
+}
+
+// WithWatchRecovery configures re-establishing of the failed runtime watches.
+//
+// A failed watch is resumed from the last received bookmark. If the watch can't be resumed, it is started again
+// with the bootstrap contents: the cache is resynced and the controllers are notified about the current resources.
+// Run fails once the watch of a resource kind fails more than budget times in a row, zero budget (default) makes Run fail
+// on the first watch failure.
+func WithWatchRecovery(budget int, policy controller.BackoffPolicy) Option {
+	return func(options *Options) {
+		options.WatchRecovery = WatchRecovery{
+			Backoff: policy,
+			Budget:  budget,
+		}
+	}
+}
+
+// DefaultPriorityAging is the default priority aging interval.
+const DefaultPriorityAging = time.Second
+
 // DefaultOptions returns default value of Options.
 func DefaultOptions() Options {
 	return Options{
 		ChangeRateLimit: rate.Inf,
 		ChangeBurst:     0,
 		MetricsEnabled:  true,
+		PriorityAging:   DefaultPriorityAging,
 	}
 }
@@ -0,0 +1,163 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// tenantSumController sums IntResources across the namespaces matching the pattern.
+type tenantSumController struct {
+	pattern string
+}
+
+func (ctrl *tenantSumController) Name() string {
+	return "TenantSumController"
+}
+
+func (ctrl *tenantSumController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			NamespacePattern: ctrl.pattern,
+			Type:             conformance.IntResourceType,
+			Kind:             controller.InputWeak,
+		},
+	}
+}
+
+func (ctrl *tenantSumController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: conformance.StrResourceType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+func (ctrl *tenantSumController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		list, err := safe.ReaderList[*conformance.IntResource](ctx, r,
+			resource.NewMetadata(ctrl.pattern, conformance.IntResourceType, "", resource.VersionUndefined),
+			state.WithListNamespacePattern(ctrl.pattern),
+		)
+		if err != nil {
+			return err
+		}
+
+		var sum int
+
+		for res := range list.All() {
+			sum += res.Value()
+		}
+
+		if err := safe.WriterModify(ctx, r, conformance.NewStrResource("strings", "sum", ""), func(res *conformance.StrResource) error {
+			res.SetValue(strconv.Itoa(sum))
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestRuntimeCachePattern(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		opts []options.Option
+	}{
+		{
+			name: "uncached",
+		},
+		{
+			name: "cached",
+			opts: []options.Option{
+				options.WithCachedResourcePattern("tenant-*", conformance.IntResourceType),
+			},
+		},
+		{
+			name: "cached exactly and by pattern",
+			opts: []options.Option{
+				options.WithCachedResource("tenant-a", conformance.IntResourceType),
+				options.WithCachedResourcePattern("tenant-*", conformance.IntResourceType),
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			testRuntimeCachePattern(t, test.opts...)
+		})
+	}
+}
+
+func testRuntimeCachePattern(t *testing.T, opts ...options.Option) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("tenant-a", "one", 1)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("tenant-a", "two", 2)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("other", "ten", 10)))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t), opts...)
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&tenantSumController{pattern: "tenant-*"}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	waitSum := func(expected string) {
+		_, err := st.WatchFor(ctx, conformance.NewStrResource("strings", "sum", "").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+			str, ok := r.(*conformance.StrResource)
+
+			return ok && str.Value() == expected, nil
+		}))
+		require.NoError(t, err)
+	}
+
+	waitSum("3")
+
+	// namespace created after the runtime is started
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("tenant-b", "three", 3)))
+
+	waitSum("6")
+
+	require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("tenant-a", "one", 1).Metadata()))
+
+	waitSum("5")
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
@@ -7,8 +7,10 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/siderolabs/gen/channel"
 	"github.com/siderolabs/gen/optional"
@@ -16,15 +18,23 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/dryrun"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/adapter"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/cache"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/controllerstatus"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/dependency"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/expiry"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/gc"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/qruntime"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/reduced"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/rruntime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/scheduler"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/lease"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/tracing"
 )
 
 var _ controller.Engine = (*Runtime)(nil)
@@ -37,7 +47,7 @@ type Runtime struct { //nolint:govet
 	cache  *cache.ResourceCache
 	logger *zap.Logger
 
-	watchCh     chan []state.Event
+	watchCh     chan watchEvents
 	watchErrors chan error
 	watchedMu   sync.Mutex
 	watched     map[watchKey]bool // value is true if the watch populates the cache
@@ -45,7 +55,14 @@ type Runtime struct { //nolint:govet
 	group errgroup.Group
 
 	controllersMu sync.RWMutex
-	controllers   map[string]adapter.Adapter
+	controllers   map[string]*registeredController
+
+	garbageCollectors []*gc.Collector
+
+	scheduler *scheduler.Scheduler
+
+	statusPublisher *controllerstatus.Publisher
+	statusNotify    chan struct{}
 
 	runCtx       context.Context //nolint:containedctx
 	runCtxCancel context.CancelFunc
@@ -53,9 +70,22 @@ type Runtime struct { //nolint:govet
 	options options.Options
 }
 
+type registeredController struct {
+	adapter adapter.Adapter
+	status  *controllerstatus.Tracker
+
+	// set when the adapter is running
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	unregistering bool
+}
+
 type watchKey struct {
 	Namespace resource.Namespace
 	Type      resource.Type
+	// Pattern is set if the Namespace is a namespace pattern
+	Pattern bool
 }
 
 // watchBuffer provides a buffer to aggregate multiple match events.
@@ -63,13 +93,19 @@ type watchKey struct {
 // This improves efficiency of a deduplication algorithm.
 const watchBuffer = 16
 
+// statusPublishInterval is the minimum interval between publishing controller statuses.
+const statusPublishInterval = 100 * time.Millisecond
+
+// statusRefreshInterval is the interval of publishing the controller statuses without any updates (e.g. queue length).
+const statusRefreshInterval = 5 * time.Second
+
 // NewRuntime initializes controller runtime object.
 func NewRuntime(st state.State, logger *zap.Logger, opt ...options.Option) (*Runtime, error) {
 	runtime := &Runtime{
 		state:       st,
 		logger:      logger,
-		controllers: map[string]adapter.Adapter{},
-		watchCh:     make(chan []state.Event, watchBuffer),
+		controllers: map[string]*registeredController{},
+		watchCh:     make(chan watchEvents, watchBuffer),
 		watchErrors: make(chan error, 1),
 		watched:     map[watchKey]bool{},
 		options:     options.DefaultOptions(),
@@ -79,6 +115,10 @@ func NewRuntime(st state.State, logger *zap.Logger, opt ...options.Option) (*Run
 		o(&runtime.options)
 	}
 
+	if runtime.options.TracerProvider != nil && runtime.options.TraceRegistry == nil {
+		runtime.options.TraceRegistry = tracing.NewRegistry(tracing.DefaultRegistrySize)
+	}
+
 	var err error
 
 	runtime.depDB, err = dependency.NewDatabase()
@@ -86,14 +126,33 @@ func NewRuntime(st state.State, logger *zap.Logger, opt ...options.Option) (*Run
 		return nil, fmt.Errorf("error creating dependency database: %w", err)
 	}
 
-	runtime.cache = cache.NewResourceCache(runtime.options.CachedResources)
+	runtime.cache = cache.NewResourceCache(runtime.options.CachedResources, cache.WithReadOnlyChecks(runtime.options.ReadOnlyChecks))
+
+	if runtime.options.ReconcileSlots > 0 {
+		runtime.scheduler = scheduler.New(runtime.options.ReconcileSlots, runtime.options.PriorityAging)
+	}
+
+	for _, collected := range runtime.options.GarbageCollectedResources {
+		runtime.garbageCollectors = append(runtime.garbageCollectors, gc.NewCollector(st, logger, collected.Namespace, collected.Type, collected.Mode))
+	}
+
+	if runtime.options.ControllerStatusNamespace != "" {
+		runtime.statusPublisher = controllerstatus.NewPublisher(st, logger, runtime.options.ControllerStatusNamespace)
+		runtime.statusNotify = make(chan struct{}, 1)
+	}
 
 	for _, cachedRead := range runtime.options.CachedResources {
 		// mark the cached resources as watched & cached
-		runtime.watched[watchKey{
+		key := watchKey{
 			Namespace: cachedRead.Namespace,
 			Type:      cachedRead.Type,
-		}] = true
+		}
+
+		if cachedRead.NamespacePattern != "" {
+			key.Namespace, key.Pattern = cachedRead.NamespacePattern, true
+		}
+
+		runtime.watched[key] = true
 	}
 
 	return runtime, nil
@@ -110,6 +169,8 @@ func (runtime *Runtime) RegisterController(ctrl controller.Controller) error {
 		return fmt.Errorf("controller %q already registered", name)
 	}
 
+	tracker := controllerstatus.NewTracker(runtime.statusNotify)
+
 	adapter, err := rruntime.NewAdapter(
 		ctrl,
 		adapter.Options{
@@ -117,6 +178,8 @@ func (runtime *Runtime) RegisterController(ctrl controller.Controller) error {
 			State:          runtime.state,
 			Cache:          runtime.cache,
 			DepDB:          runtime.depDB,
+			Status:         tracker,
+			Scheduler:      runtime.scheduler,
 			RuntimeOptions: runtime.options,
 			RegisterWatch:  runtime.watch,
 		},
@@ -125,7 +188,7 @@ func (runtime *Runtime) RegisterController(ctrl controller.Controller) error {
 		return fmt.Errorf("error initializing controller %q adapter: %w", name, err)
 	}
 
-	runtime.registerAdapter(name, adapter)
+	runtime.registerAdapter(name, adapter, tracker)
 
 	return nil
 }
@@ -141,6 +204,8 @@ func (runtime *Runtime) RegisterQController(ctrl controller.QController) error {
 		return fmt.Errorf("controller %q already registered", name)
 	}
 
+	tracker := controllerstatus.NewTracker(runtime.statusNotify)
+
 	adapter, err := qruntime.NewAdapter(
 		ctrl,
 		adapter.Options{
@@ -148,6 +213,8 @@ func (runtime *Runtime) RegisterQController(ctrl controller.QController) error {
 			State:          runtime.state,
 			Cache:          runtime.cache,
 			DepDB:          runtime.depDB,
+			Status:         tracker,
+			Scheduler:      runtime.scheduler,
 			RuntimeOptions: runtime.options,
 			RegisterWatch:  runtime.watch,
 		},
@@ -156,7 +223,7 @@ func (runtime *Runtime) RegisterQController(ctrl controller.QController) error {
 		return fmt.Errorf("error initializing controller %q adapter: %w", name, err)
 	}
 
-	runtime.registerAdapter(name, adapter)
+	runtime.registerAdapter(name, adapter, tracker)
 
 	return nil
 }
@@ -166,16 +233,331 @@ func (runtime *Runtime) CachedState() state.CoreState {
 	return runtime.cache.WrapState(runtime.state)
 }
 
-func (runtime *Runtime) registerAdapter(name string, adapter adapter.Adapter) {
-	runtime.controllers[name] = adapter
+func (runtime *Runtime) registerAdapter(name string, adapter adapter.Adapter, tracker *controllerstatus.Tracker) {
+	ctrl := &registeredController{
+		adapter: adapter,
+		status:  tracker,
+	}
+
+	runtime.controllers[name] = ctrl
 
 	if runtime.runCtx != nil {
-		goFunc(&runtime.group, func() { adapter.Run(runtime.runCtx) })
+		runtime.startController(ctrl)
+	}
+}
+
+// startController should be called with controllersMu locked.
+func (runtime *Runtime) startController(ctrl *registeredController) {
+	var ctx context.Context
+
+	ctx, ctrl.cancel = context.WithCancel(runtime.runCtx)
+	ctrl.done = make(chan struct{})
+
+	goFunc(&runtime.group, func() {
+		defer close(ctrl.done)
+
+		ctrl.adapter.Run(ctx)
+	})
+}
+
+// UnregisterController stops the controller and removes it from the runtime.
+//
+// UnregisterController waits for the controller to finish, and then removes controller inputs and outputs
+// from the dependency database, so that the exclusive outputs of the controller can be claimed by other controllers.
+// Resources created by the controller are not touched.
+func (runtime *Runtime) UnregisterController(name string) error {
+	runtime.controllersMu.Lock()
+
+	ctrl, exists := runtime.controllers[name]
+	if !exists || ctrl.unregistering {
+		runtime.controllersMu.Unlock()
+
+		return fmt.Errorf("controller %q is not registered", name)
+	}
+
+	// keep the controller in the map until it's drained, so that the name can't be reused
+	ctrl.unregistering = true
+	cancel, done := ctrl.cancel, ctrl.done
+	runCtx := runtime.runCtx
+
+	runtime.controllersMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+
+		<-done
+	}
+
+	err := runtime.depDB.DeleteController(name)
+
+	runtime.controllersMu.Lock()
+	delete(runtime.controllers, name)
+	runtime.controllersMu.Unlock()
+
+	if runtime.options.MetricsEnabled {
+		metrics.QControllerDeadLetters.Delete(name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error removing controller %q dependencies: %w", name, err)
+	}
+
+	if runtime.statusPublisher != nil && runCtx != nil && runCtx.Err() == nil {
+		if err = runtime.statusPublisher.Remove(runCtx, name); err != nil {
+			return fmt.Errorf("error removing controller %q status: %w", name, err)
+		}
 	}
+
+	return nil
+}
+
+// PauseController blocks the controller writes.
+//
+// PauseController waits for in-flight writes of the controller to finish, so once it returns,
+// the controller doesn't modify any resources until it's resumed with ResumeController.
+// The controller still receives events and runs the reconcile loop, but any write blocks until the controller is resumed.
+// If ctx is canceled while waiting for in-flight writes, the controller stays paused.
+func (runtime *Runtime) PauseController(ctx context.Context, name string) error {
+	runtime.controllersMu.RLock()
+	ctrl, exists := runtime.controllers[name]
+	runtime.controllersMu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("controller %q is not registered", name)
+	}
+
+	// the gate is closed before waiting for in-flight writes, and it stays closed if ctx is canceled,
+	// so the status is reported as paused right away
+	ctrl.status.SetPaused(true)
+
+	return ctrl.adapter.Pause(ctx)
+}
+
+// ResumeController unblocks the controller writes paused with PauseController.
+func (runtime *Runtime) ResumeController(name string) error {
+	runtime.controllersMu.RLock()
+	ctrl, exists := runtime.controllers[name]
+	runtime.controllersMu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("controller %q is not registered", name)
+	}
+
+	ctrl.adapter.Resume()
+	ctrl.status.SetPaused(false)
+
+	return nil
+}
+
+// ParkedItems returns the items of the QController parked after exhausting the backoff attempts.
+//
+// See controller.BackoffPolicy.
+func (runtime *Runtime) ParkedItems(name string) ([]controller.ParkedItem, error) {
+	parking, err := runtime.parkingAdapter(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return parking.ParkedItems(), nil
+}
+
+// RetryParkedItems queues the parked items of the QController matching the pointers for reconcile.
+//
+// If no pointers are given, all parked items are retried.
+// RetryParkedItems returns the number of items queued.
+func (runtime *Runtime) RetryParkedItems(name string, ptrs ...resource.Pointer) (int, error) {
+	parking, err := runtime.parkingAdapter(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return parking.RetryParked(ptrs...), nil
+}
+
+// DryRunPlan returns the changes planned by the controller running in dry-run mode.
+//
+// See options.WithDryRun.
+func (runtime *Runtime) DryRunPlan(name string) ([]dryrun.Change, error) {
+	dryRunAdapter, err := runtime.dryRunAdapter(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return dryRunAdapter.DryRunPlan()
+}
+
+// ResetDryRunPlan drops the changes planned by the controller running in dry-run mode.
+//
+// Planned changes shadow the resources in the state, so the plan should be reset to let the controller
+// observe the changes made to the state since. The plan is also reset each time the controller is (re)started.
+func (runtime *Runtime) ResetDryRunPlan(name string) error {
+	dryRunAdapter, err := runtime.dryRunAdapter(name)
+	if err != nil {
+		return err
+	}
+
+	return dryRunAdapter.DryRunReset()
+}
+
+func (runtime *Runtime) dryRunAdapter(name string) (adapter.DryRunAdapter, error) { //nolint:ireturn
+	runtime.controllersMu.RLock()
+	ctrl, exists := runtime.controllers[name]
+	runtime.controllersMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("controller %q is not registered", name)
+	}
+
+	dryRunAdapter, ok := ctrl.adapter.(adapter.DryRunAdapter)
+	if !ok {
+		return nil, fmt.Errorf("controller %q doesn't support dry-run mode", name)
+	}
+
+	return dryRunAdapter, nil
+}
+
+func (runtime *Runtime) parkingAdapter(name string) (adapter.ParkingAdapter, error) { //nolint:ireturn
+	runtime.controllersMu.RLock()
+	ctrl, exists := runtime.controllers[name]
+	runtime.controllersMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("controller %q is not registered", name)
+	}
+
+	parking, ok := ctrl.adapter.(adapter.ParkingAdapter)
+	if !ok {
+		return nil, fmt.Errorf("controller %q doesn't support parking items", name)
+	}
+
+	return parking, nil
 }
 
 // Run all the controller loops.
+//
+// If the leader election is enabled (see options.WithLeaderElection), controllers are started only
+// once the lease is acquired, and Run returns an error if the lease is lost.
 func (runtime *Runtime) Run(ctx context.Context) error {
+	if runtime.options.LeaderElection != nil {
+		return runtime.runWithLeaderElection(ctx, *runtime.options.LeaderElection)
+	}
+
+	return runtime.run(ctx)
+}
+
+func (runtime *Runtime) runWithLeaderElection(ctx context.Context, election options.LeaderElection) error {
+	ptr := lease.NewLease(election.Namespace, election.ID).Metadata()
+	logger := runtime.logger.With(zap.String("lease", election.ID), zap.String("holder", election.Holder))
+
+	if election.RenewInterval <= 0 || election.RenewInterval >= election.LeaseDuration {
+		return fmt.Errorf("lease renew interval %s should be positive and less than the lease duration %s", election.RenewInterval, election.LeaseDuration)
+	}
+
+	ticker := time.NewTicker(election.RenewInterval)
+	defer ticker.Stop()
+
+	var acquired time.Time
+
+	for {
+		acquired = time.Now()
+
+		_, err := lease.Acquire(ctx, runtime.state, ptr, election.Holder, election.LeaseDuration)
+		if err == nil {
+			break
+		}
+
+		if ctx.Err() != nil {
+			return nil //nolint:nilerr
+		}
+
+		if !errors.Is(err, lease.ErrHeld) {
+			logger.Warn("failed to acquire the lease", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+
+	logger.Info("lease acquired, starting controllers")
+
+	defer func() {
+		releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), election.RenewInterval)
+		defer releaseCancel()
+
+		if err := lease.Release(releaseCtx, runtime.state, ptr, election.Holder); err != nil {
+			logger.Warn("failed to release the lease", zap.Error(err))
+		}
+	}()
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	renewErrCh := make(chan error, 1)
+
+	go func() {
+		renewErrCh <- runtime.renewLease(runCtx, ptr, election, ticker, acquired)
+
+		runCancel()
+	}()
+
+	err := runtime.run(runCtx)
+
+	runCancel()
+
+	if renewErr := <-renewErrCh; renewErr != nil {
+		return renewErr
+	}
+
+	return err
+}
+
+// renewLease renews the lease until the context is canceled.
+//
+// The lease is considered renewed at the time the renew attempt was started, and if it is not renewed
+// within LeaseDuration - RenewInterval since then, renewLease returns an error (stopping the controllers)
+// before the lease might be acquired by another holder.
+func (runtime *Runtime) renewLease(ctx context.Context, ptr resource.Pointer, election options.LeaderElection, ticker *time.Ticker, renewed time.Time) error {
+	deadline := renewed.Add(election.LeaseDuration - election.RenewInterval)
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			return fmt.Errorf("controller runtime lease error: lease was not renewed by %s", deadline)
+		case <-ticker.C:
+		}
+
+		attempt := time.Now()
+
+		renewCtx, renewCancel := context.WithDeadline(ctx, deadline)
+		_, err := lease.Renew(renewCtx, runtime.state, ptr, election.Holder)
+
+		renewCancel()
+
+		switch {
+		case err == nil:
+			deadline = attempt.Add(election.LeaseDuration - election.RenewInterval)
+			timer.Reset(time.Until(deadline))
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, lease.ErrLost):
+			return fmt.Errorf("controller runtime lease error: %w", err)
+		case !time.Now().Before(deadline):
+			return fmt.Errorf("controller runtime lease error: failed to renew: %w", err)
+		default:
+			runtime.logger.Warn("failed to renew the lease", zap.String("lease", election.ID), zap.Error(err))
+		}
+	}
+}
+
+func (runtime *Runtime) run(ctx context.Context) error {
 	if err := func() error {
 		runtime.controllersMu.Lock()
 		defer runtime.controllersMu.Unlock()
@@ -198,8 +580,24 @@ func (runtime *Runtime) Run(ctx context.Context) error {
 			return nil
 		})
 
-		for _, adapter := range runtime.controllers {
-			goFunc(&runtime.group, func() { adapter.Run(runtime.runCtx) })
+		for _, ctrl := range runtime.controllers {
+			if !ctrl.unregistering {
+				runtime.startController(ctrl)
+			}
+		}
+
+		for _, expiring := range runtime.options.ExpiringResources {
+			collector := expiry.NewCollector(runtime.state, runtime.logger, expiring.Namespace, expiring.Type)
+
+			goFunc(&runtime.group, func() { runtime.runCollector("expiry collector", collector.Run) })
+		}
+
+		for _, collector := range runtime.garbageCollectors {
+			goFunc(&runtime.group, func() { runtime.runCollector("garbage collector", collector.Run) })
+		}
+
+		if runtime.statusPublisher != nil {
+			goFunc(&runtime.group, runtime.publishStatus)
 		}
 
 		return nil
@@ -219,9 +617,80 @@ func (runtime *Runtime) Run(ctx context.Context) error {
 
 	runtime.group.Wait() //nolint:errcheck // no function which this group manages should return an error
 
+	if runtime.statusPublisher != nil {
+		// publish the final status of the stopped controllers
+		publishCtx, publishCancel := context.WithTimeout(context.WithoutCancel(ctx), statusRefreshInterval)
+		defer publishCancel()
+
+		runtime.statusPublisher.Publish(publishCtx, runtime.statusTrackers)
+	}
+
 	return watchErr
 }
 
+func (runtime *Runtime) statusTrackers() map[string]*controllerstatus.Tracker {
+	runtime.controllersMu.RLock()
+	defer runtime.controllersMu.RUnlock()
+
+	trackers := make(map[string]*controllerstatus.Tracker, len(runtime.controllers))
+
+	for name, ctrl := range runtime.controllers {
+		if !ctrl.unregistering {
+			trackers[name] = ctrl.status
+		}
+	}
+
+	return trackers
+}
+
+func (runtime *Runtime) publishStatus() {
+	ticker := time.NewTicker(statusRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		runtime.statusPublisher.Publish(runtime.runCtx, runtime.statusTrackers)
+
+		// limit the rate of status updates
+		select {
+		case <-runtime.runCtx.Done():
+			return
+		case <-time.After(statusPublishInterval):
+		}
+
+		select {
+		case <-runtime.runCtx.Done():
+			return
+		case <-runtime.statusNotify:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (runtime *Runtime) runCollector(name string, run func(context.Context) error) {
+	if err := run(runtime.runCtx); err != nil {
+		select {
+		case runtime.watchErrors <- fmt.Errorf("%s: %w", name, err):
+		case <-runtime.runCtx.Done():
+		}
+	}
+}
+
+// Orphans returns resources which are garbage collected by the runtime, but their parents don't exist.
+//
+// Orphans are recomputed from the state each time the runtime starts, so they are reported
+// once the garbage collected resources and their parents are loaded from the state.
+//
+// See options.WithGarbageCollection.
+func (runtime *Runtime) Orphans() []resource.Pointer {
+	var orphans []resource.Pointer
+
+	for _, collector := range runtime.garbageCollectors {
+		orphans = append(orphans, collector.Orphans()...)
+	}
+
+	return orphans
+}
+
 // GetDependencyGraph returns dependency graph between resources and controllers.
 func (runtime *Runtime) GetDependencyGraph() (*controller.DependencyGraph, error) {
 	return runtime.depDB.Export()
@@ -232,9 +701,7 @@ func (runtime *Runtime) setupWatches() error {
 	defer runtime.watchedMu.Unlock()
 
 	for key, cached := range runtime.watched {
-		kind := resource.NewMetadata(key.Namespace, key.Type, "", resource.Version{})
-
-		if err := runtime.state.WatchKindAggregated(runtime.runCtx, kind, runtime.watchCh, state.WithBootstrapContents(cached), state.WithBootstrapBookmark(!cached)); err != nil {
+		if err := runtime.startWatch(key, cached); err != nil {
 			return err
 		}
 	}
@@ -242,19 +709,28 @@ func (runtime *Runtime) setupWatches() error {
 	return nil
 }
 
-func (runtime *Runtime) watch(resourceNamespace resource.Namespace, resourceType resource.Type) error {
+func (runtime *Runtime) watch(input controller.Input) error {
 	runtime.watchedMu.Lock()
 	defer runtime.watchedMu.Unlock()
 
 	key := watchKey{
-		Namespace: resourceNamespace,
-		Type:      resourceType,
+		Namespace: input.Namespace,
+		Type:      input.Type,
+	}
+
+	if input.NamespacePattern != "" {
+		key.Namespace, key.Pattern = input.NamespacePattern, true
 	}
 
 	if _, exists := runtime.watched[key]; exists {
 		return nil
 	}
 
+	// cached resources are already watched, including the ones cached across namespaces
+	if !key.Pattern && runtime.cache.IsHandled(key.Namespace, key.Type) {
+		return nil
+	}
+
 	runtime.watched[key] = false
 
 	// watch is called with controllersMu locked, so this access is synchronized
@@ -262,9 +738,7 @@ func (runtime *Runtime) watch(resourceNamespace resource.Namespace, resourceType
 		return nil
 	}
 
-	kind := resource.NewMetadata(resourceNamespace, resourceType, "", resource.Version{})
-
-	return runtime.state.WatchKindAggregated(runtime.runCtx, kind, runtime.watchCh, state.WithBootstrapBookmark(true))
+	return runtime.startWatch(key, false)
 }
 
 type dedup map[reduced.Key]reduced.Value
@@ -309,18 +783,11 @@ func (runtime *Runtime) processWatched() {
 }
 
 // processEvents processes a group of watch events producing deduplicated map of reducedMetadata.
-//
-// processEvents returns false if the watch failed and the runtime should abort.
-func (runtime *Runtime) processEvents(events []state.Event, m dedup) bool {
-eventLoop:
-	for _, e := range events {
-		if e.Type == state.Errored {
-			// watch failed, we need to abort
-			runtime.watchErrors <- e.Error
-
-			return false
-		}
+func (runtime *Runtime) processEvents(batch watchEvents, m dedup) {
+	resync := batch.resync
 
+eventLoop:
+	for _, e := range batch.events {
 		// ignore Noop events
 		if e.Type == state.Noop {
 			continue
@@ -331,23 +798,53 @@ eventLoop:
 		// - on Bootstrapped event, we notify the cache that it can start serving reads
 		// - after Bootstrapped event, we process events normally, and notify cache about updated/deleted resources
 		//
+		// if the watch was established again with BootstrapContents (see kindWatch), the cache is resynced:
+		// - bootstrapped contents are put to the cache, and controllers are notified as usual
+		// - on Bootstrapped event, resources which were not resynced are removed from the cache, and controllers are notified
+		//
 		// if the resource is not cached, this section is noop
 		if e.Type == state.Bootstrapped {
+			ns, typ := e.Resource.Metadata().Namespace(), e.Resource.Metadata().Type()
+
+			if !batch.cached {
+				// uncached watch was established again with BootstrapContents
+				resync = false
+
+				continue eventLoop
+			}
+
 			runtime.logger.Debug(
 				"bootstrapped event received",
-				zap.String("namespace", e.Resource.Metadata().Namespace()),
-				zap.String("type", e.Resource.Metadata().Type()),
-				zap.Int("cache_size", runtime.cache.Len(e.Resource.Metadata().Namespace(), e.Resource.Metadata().Type())),
+				zap.String("namespace", ns),
+				zap.String("type", typ),
+				zap.Int("cache_size", runtime.cache.Len(ns, typ)),
 			)
 
-			runtime.cache.MarkBootstrapped(e.Resource.Metadata().Namespace(), e.Resource.Metadata().Type())
+			if resync {
+				for _, r := range runtime.cache.EndResync(ns, typ) {
+					reducedMD := reduced.NewMetadata(r.Metadata())
+					m[reducedMD.Key] = reducedMD.Value
+				}
+
+				resync = false
+			}
+
+			runtime.cache.MarkBootstrapped(ns, typ)
 
 			continue eventLoop
 		}
 
+		// a resource might be delivered by several watches (e.g. if the namespace is cached both exactly and by a pattern),
+		// but the cache handler is populated only by a single watch
 		cacheHandled, cacheBootstrapped := runtime.cache.IsHandledBootstrapped(e.Resource.Metadata().Namespace(), e.Resource.Metadata().Type())
-		if cacheHandled {
+		if cacheHandled && batch.cached && runtime.cache.IsPopulatedBy(batch.key.Namespace, batch.key.Pattern, e.Resource) {
 			switch {
+			case resync:
+				runtime.cache.CacheResync(e.Resource)
+
+				if !cacheBootstrapped {
+					continue eventLoop
+				}
 			case !cacheBootstrapped:
 				runtime.cache.CacheAppend(e.Resource)
 
@@ -357,20 +854,24 @@ eventLoop:
 				runtime.cache.CachePut(e.Resource)
 			case e.Type == state.Destroyed:
 				runtime.cache.CacheRemove(e.Resource)
+
+				// the cache which exceeded the size cap is recovered once enough resources are destroyed
+				if runtime.cache.NeedsResync(e.Resource.Metadata().Namespace(), e.Resource.Metadata().Type()) {
+					batch.requestResync()
+				}
 			}
 		}
 
 		reducedMD := reduced.NewMetadata(e.Resource.Metadata())
+		reducedMD.TraceParent = runtime.options.TraceRegistry.Lookup(e.Resource.Metadata())
 		m[reducedMD.Key] = reducedMD.Value
 	}
-
-	return true
 }
 
 // deduplicateWatchEvents deduplicates events from the watch channel into the map sent to the channel ch.
 func (runtime *Runtime) deduplicateWatchEvents(ch chan dedup, empty chan dedup) {
 	for {
-		var events []state.Event
+		var events watchEvents
 
 		// wait for an event
 		select {
@@ -389,9 +890,7 @@ func (runtime *Runtime) deduplicateWatchEvents(ch chan dedup, empty chan dedup)
 			return
 		}
 
-		if !runtime.processEvents(events, m) {
-			return
-		}
+		runtime.processEvents(events, m)
 
 		// we might have not accumulated any events
 		if len(m) == 0 {
@@ -407,9 +906,7 @@ func (runtime *Runtime) deduplicateWatchEvents(ch chan dedup, empty chan dedup)
 		for {
 			select {
 			case events = <-runtime.watchCh:
-				if !runtime.processEvents(events, m) {
-					return
-				}
+				runtime.processEvents(events, m)
 			case <-runtime.runCtx.Done():
 				return
 			default:
@@ -464,8 +961,11 @@ func (runtime *Runtime) deliverDeduplicatedEvents(ch chan dedup, empty chan<- de
 
 		runtime.controllersMu.RLock()
 
-		for _, ctrl := range controllers {
-			runtime.controllers[ctrl].WatchTrigger(&k)
+		for _, name := range controllers {
+			// controller might have been unregistered concurrently
+			if ctrl, exists := runtime.controllers[name]; exists {
+				ctrl.adapter.WatchTrigger(&k)
+			}
 		}
 
 		runtime.controllersMu.RUnlock()
@@ -6,28 +6,41 @@ package runtime_test
 
 import (
 	"context"
+	"expvar"
 	"net"
 	goruntime "runtime"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/siderolabs/gen/optional"
 	"github.com/siderolabs/gen/xtesting/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	suiterunner "github.com/stretchr/testify/suite"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/goleak"
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/conformance"
 	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/dryrun"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
 	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/status"
 	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/lease"
 	"github.com/cosi-project/runtime/pkg/resource"
 	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/rtestutils"
 	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
 	stateconformance "github.com/cosi-project/runtime/pkg/state/conformance"
@@ -35,6 +48,7 @@ import (
 	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
 	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
 	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
+	"github.com/cosi-project/runtime/pkg/tracing"
 )
 
 func noError(err error) {
@@ -304,3 +318,920 @@ func TestRuntimeCachedState(t *testing.T) {
 
 	require.NoError(t, <-errCh)
 }
+
+func TestRuntimeExpiry(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	logger := zaptest.NewLogger(t)
+	rt, err := runtime.NewRuntime(st, logger, options.WithExpiringResource("expiry", conformance.IntResourceType))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	ctx, errCh := future.GoContext(ctx, rt.Run)
+
+	expiring := conformance.NewIntResource("expiry", "expiring", 1)
+	expiring.Metadata().SetTTL(100 * time.Millisecond)
+
+	finalized := conformance.NewIntResource("expiry", "finalized", 2)
+	finalized.Metadata().SetExpires(time.Now().Add(-time.Minute))
+	finalized.Metadata().Finalizers().Add("foo")
+
+	permanent := conformance.NewIntResource("expiry", "permanent", 3)
+
+	for _, r := range []resource.Resource{expiring, finalized, permanent} {
+		require.NoError(t, st.Create(ctx, r))
+	}
+
+	_, err = st.WatchFor(ctx, expiring.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, finalized.Metadata(), state.WithPhases(resource.PhaseTearingDown))
+	require.NoError(t, err)
+
+	require.NoError(t, st.RemoveFinalizer(ctx, finalized.Metadata(), "foo"))
+
+	_, err = st.WatchFor(ctx, finalized.Metadata(), state.WithEventTypes(state.Destroyed))
+	require.NoError(t, err)
+
+	r, err := st.Get(ctx, permanent.Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, resource.PhaseRunning, r.Metadata().Phase())
+
+	cancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimeGarbageCollection(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		mode options.CascadeMode
+	}{
+		{name: "foreground", mode: options.CascadeForeground},
+		{name: "background", mode: options.CascadeBackground},
+		{name: "orphan", mode: options.CascadeOrphan},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+			st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+			logger := zaptest.NewLogger(t)
+			rt, err := runtime.NewRuntime(st, logger, options.WithGarbageCollection("children", conformance.StrResourceType, tt.mode))
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			ctx, errCh := future.GoContext(ctx, rt.Run)
+
+			parent := conformance.NewIntResource("parents", "parent", 1)
+			require.NoError(t, st.Create(ctx, parent))
+
+			child := conformance.NewStrResource("children", "child", "1")
+			child.Metadata().OwnerReferences().Add(parent.Metadata())
+			require.NoError(t, st.Create(ctx, child))
+
+			unrelated := conformance.NewStrResource("children", "unrelated", "2")
+			require.NoError(t, st.Create(ctx, unrelated))
+
+			if tt.mode == options.CascadeForeground {
+				_, err = st.WatchFor(ctx, parent.Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+					return !r.Metadata().Finalizers().Empty(), nil
+				}))
+				require.NoError(t, err)
+
+				ready, teardownErr := st.Teardown(ctx, parent.Metadata())
+				require.NoError(t, teardownErr)
+				assert.False(t, ready)
+
+				_, err = st.WatchFor(ctx, child.Metadata(), state.WithEventTypes(state.Destroyed))
+				require.NoError(t, err)
+
+				_, err = st.WatchFor(ctx, parent.Metadata(), state.WithFinalizerEmpty())
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, st.TeardownAndDestroy(ctx, parent.Metadata()))
+
+			switch tt.mode {
+			case options.CascadeForeground, options.CascadeBackground:
+				_, err = st.WatchFor(ctx, child.Metadata(), state.WithEventTypes(state.Destroyed))
+				require.NoError(t, err)
+
+				require.Eventually(t, func() bool { return len(rt.Orphans()) == 0 }, 5*time.Second, 10*time.Millisecond)
+			case options.CascadeOrphan:
+				require.Eventually(t, func() bool {
+					orphans := rt.Orphans()
+
+					return len(orphans) == 1 && orphans[0].ID() == child.Metadata().ID()
+				}, 5*time.Second, 10*time.Millisecond)
+
+				_, err = st.Get(ctx, child.Metadata())
+				require.NoError(t, err)
+			}
+
+			_, err = st.Get(ctx, unrelated.Metadata())
+			require.NoError(t, err)
+
+			cancel()
+
+			require.NoError(t, <-errCh)
+		})
+	}
+}
+
+func TestRuntimeLeaderElection(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	newRuntime := func(holder string) *runtime.Runtime {
+		rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t).Named(holder),
+			options.WithLeaderElection("default", "leader", holder, 300*time.Millisecond, 100*time.Millisecond),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+			SourceNamespace: "source-" + holder,
+			TargetNamespace: "target",
+		}))
+
+		return rt
+	}
+
+	ctx1, cancel1 := context.WithCancel(ctx)
+	defer cancel1()
+
+	_, errCh1 := future.GoContext(ctx1, newRuntime("a").Run)
+
+	_, err := st.WatchFor(ctx, lease.NewLease("default", "leader").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+		l, ok := r.(*lease.Lease)
+
+		return ok && l.TypedSpec().Holder == "a", nil
+	}))
+	require.NoError(t, err)
+
+	_, errCh2 := future.GoContext(ctx, newRuntime("b").Run)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source-a", "1", 1)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source-b", "2", 2)))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("target", "1", "1").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	// the second runtime is not holding the lease, so its controllers are not running
+	_, err = st.Get(ctx, conformance.NewStrResource("target", "2", "2").Metadata())
+	require.True(t, state.IsNotFoundError(err))
+
+	// stop the first runtime, the lease is released and picked up by the second runtime
+	cancel1()
+	require.NoError(t, <-errCh1)
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("target", "2", "2").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	l, err := safe.StateGet[*lease.Lease](ctx, st, lease.NewLease("default", "leader").Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, "b", l.TypedSpec().Holder)
+
+	cancel()
+
+	require.NoError(t, <-errCh2)
+}
+
+func TestRuntimeLeaderElectionRenewInterval(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t),
+		options.WithLeaderElection("default", "leader", "a", 300*time.Millisecond, 300*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, rt.Run(t.Context()), "renew interval")
+}
+
+func TestRuntimeUnregisterController(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "source-a",
+		TargetNamespace: "target-a",
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	ctx, errCh := future.GoContext(ctx, rt.Run)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source-a", "1", 1)))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("target-a", "1", "1").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.UnregisterController("IntToStrController"))
+	require.EqualError(t, rt.UnregisterController("IntToStrController"), `controller "IntToStrController" is not registered`)
+
+	graph, err := rt.GetDependencyGraph()
+	require.NoError(t, err)
+	assert.Empty(t, graph.Edges)
+
+	// exclusive output is released, so the controller can be registered again with different inputs
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "source-b",
+		TargetNamespace: "target-b",
+	}))
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source-a", "2", 2)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source-b", "3", 3)))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("target-b", "3", "3").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	_, err = st.Get(ctx, conformance.NewStrResource("target-a", "2", "2").Metadata())
+	require.True(t, state.IsNotFoundError(err))
+
+	cancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimePauseController(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+		SourceNamespace: "source",
+		TargetNamespace: "target",
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	ctx, errCh := future.GoContext(ctx, rt.Run)
+
+	require.NoError(t, rt.PauseController(ctx, "IntToStrController"))
+	require.EqualError(t, rt.PauseController(ctx, "Unknown"), `controller "Unknown" is not registered`)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "1", 1)))
+
+	// controller is paused, so no writes happen
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = st.Get(ctx, conformance.NewStrResource("target", "1", "1").Metadata())
+	require.True(t, state.IsNotFoundError(err))
+
+	require.NoError(t, rt.ResumeController("IntToStrController"))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("target", "1", "1").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	// paused controller can be stopped
+	require.NoError(t, rt.PauseController(ctx, "IntToStrController"))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "2", 2)))
+	require.NoError(t, rt.UnregisterController("IntToStrController"))
+
+	cancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimeControllerStatus(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t), options.WithControllerStatus("status"))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&conformance.FailingController{
+		TargetNamespace: "failing",
+	}))
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "source",
+		TargetNamespace: "target",
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("source", "1", 1)))
+
+	rtestutils.AssertResource(ctx, t, st, "FailingController", func(r *status.ControllerStatus, assert *assert.Assertions) {
+		assert.Positive(r.TypedSpec().Restarts)
+		assert.Equal("failing here", r.TypedSpec().LastError)
+	}, rtestutils.WithNamespace("status"))
+
+	rtestutils.AssertResource(ctx, t, st, "QIntToStrController", func(r *status.ControllerStatus, assert *assert.Assertions) {
+		assert.Equal(status.StateRunning, r.TypedSpec().State)
+		assert.False(r.TypedSpec().LastReconcile.IsZero())
+		assert.Equal(uint64(1), r.TypedSpec().Restarts)
+		assert.Zero(r.TypedSpec().Failures)
+		assert.Equal("oh no", r.TypedSpec().LastError)
+		assert.Zero(r.TypedSpec().QueueLength)
+	}, rtestutils.WithNamespace("status"))
+
+	require.NoError(t, rt.PauseController(ctx, "QIntToStrController"))
+
+	rtestutils.AssertResource(ctx, t, st, "QIntToStrController", func(r *status.ControllerStatus, assert *assert.Assertions) {
+		assert.Equal(status.StatePaused, r.TypedSpec().State)
+	}, rtestutils.WithNamespace("status"))
+
+	require.NoError(t, rt.ResumeController("QIntToStrController"))
+
+	require.NoError(t, rt.UnregisterController("FailingController"))
+
+	rtestutils.AssertNoResource[*status.ControllerStatus](ctx, t, st, "FailingController", rtestutils.WithNamespace("status"))
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+
+	// final status is published on shutdown
+	res, err := safe.StateGet[*status.ControllerStatus](ctx, st, status.NewControllerStatus("status", "QIntToStrController").Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, status.StateStopped, res.TypedSpec().State)
+}
+
+func TestRuntimeTracing(t *testing.T) {
+	ignoreCurrent := goleak.IgnoreCurrent()
+
+	t.Cleanup(func() { goleak.VerifyNone(t, ignoreCurrent) })
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	t.Cleanup(func() { assert.NoError(t, tp.Shutdown(context.Background())) })
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	// the server and the runtime share the trace registry
+	traces := tracing.NewRegistry(tracing.DefaultRegistrySize)
+
+	l, err := (&net.ListenConfig{}).Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(server.UnaryTracingInterceptor(tp)),
+		grpc.StreamInterceptor(server.StreamTracingInterceptor(tp)),
+	)
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(st, server.WithTraceRegistry(traces)))
+
+	go func() { assert.NoError(t, grpcServer.Serve(l)) }()
+
+	t.Cleanup(grpcServer.Stop)
+
+	grpcConn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { assert.NoError(t, grpcConn.Close()) })
+
+	clientState := state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(grpcConn)))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t), options.WithTracerProvider(tp), options.WithTraceRegistry(traces))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "ints",
+		TargetNamespace: "strings",
+	}))
+	require.NoError(t, rt.RegisterController(&conformance.StrToSentenceController{
+		SourceNamespace: "strings",
+		TargetNamespace: "sentences",
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	// the client starts the trace, and passes it over gRPC metadata
+	rootCtx, rootSpan := tp.Tracer("test").Start(ctx, "root")
+	rootCtx = metadata.AppendToOutgoingContext(rootCtx, "traceparent", tracing.TraceParent(rootCtx))
+
+	require.NoError(t, clientState.Create(rootCtx, conformance.NewIntResource("ints", "1", 1)))
+
+	rootSpan.End()
+
+	_, err = st.WatchFor(ctx, conformance.NewSentenceResource("sentences", "1", "").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	findSpan := func(name string, pred func(sdktrace.ReadOnlySpan) bool) sdktrace.ReadOnlySpan {
+		for _, span := range recorder.Ended() {
+			if span.Name() == name && pred(span) {
+				return span
+			}
+		}
+
+		return nil
+	}
+
+	linkedTo := func(parent sdktrace.ReadOnlySpan) func(sdktrace.ReadOnlySpan) bool {
+		return func(span sdktrace.ReadOnlySpan) bool {
+			for _, link := range span.Links() {
+				if link.SpanContext.SpanID() == parent.SpanContext().SpanID() {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	childOf := func(parent sdktrace.ReadOnlySpan) func(sdktrace.ReadOnlySpan) bool {
+		return func(span sdktrace.ReadOnlySpan) bool {
+			return span.Parent().SpanID() == parent.SpanContext().SpanID()
+		}
+	}
+
+	// gRPC Create -> QController reconcile -> StateAdapter write -> Controller iteration
+	create := findSpan("cosi.resource.State/Create", childOf(rootSpan.(sdktrace.ReadOnlySpan)))
+	require.NotNil(t, create)
+	assert.Equal(t, trace.SpanKindServer, create.SpanKind())
+
+	reconcile := findSpan("qcontroller.Reconcile", linkedTo(create))
+	require.NotNil(t, reconcile)
+
+	write := findSpan("controller.Modify", childOf(reconcile))
+	require.NotNil(t, write)
+
+	// the trace context is kept out of band, so it doesn't change the resource
+	str, err := st.Get(ctx, conformance.NewStrResource("strings", "1", "").Metadata())
+	require.NoError(t, err)
+
+	assert.Empty(t, str.Metadata().Annotations().Raw())
+
+	// controller iteration span might be still running
+	require.Eventually(t, func() bool {
+		return findSpan("controller.Run", linkedTo(write)) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimeMetricsCollector(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	collector := metrics.NewCollector()
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t), options.WithMetricsCollector(collector))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "ints",
+		TargetNamespace: "strings",
+	}))
+	require.NoError(t, rt.RegisterController(&conformance.StrToSentenceController{
+		SourceNamespace: "strings",
+		TargetNamespace: "sentences",
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "1", 1)))
+
+	_, err = st.WatchFor(ctx, conformance.NewSentenceResource("sentences", "1", "").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+
+	for _, name := range []string{
+		"cosi_qcontroller_reconcile_duration_seconds",
+		"cosi_qcontroller_queue_wait_seconds",
+		"cosi_controller_state_operation_duration_seconds",
+	} {
+		assert.Positive(t, testutil.CollectAndCount(collector, name), name)
+	}
+
+	assert.Positive(t, testutil.CollectAndCount(collector, "cosi_controller_writes_total"))
+}
+
+func TestRuntimeReconcileTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	timeout := controller.TimeoutSettings{
+		Timeout: 100 * time.Millisecond,
+		Cancel:  true,
+	}
+
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrSleepingController{
+		SourceNamespace:  "ints",
+		TargetNamespace:  "strings",
+		ReconcileTimeout: timeout,
+	}))
+	require.NoError(t, rt.RegisterController(&conformance.StuckController{
+		TargetNamespace: "stuck",
+		Timeout:         timeout,
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	// stuck controller is canceled and restarted
+	_, err = st.WatchFor(ctx, conformance.NewIntResource("stuck", "stuck", 0).Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+		res, ok := r.(*conformance.IntResource)
+
+		return ok && res.Value() == 2, nil
+	}))
+	require.NoError(t, err)
+
+	// reconcile sleeping for a minute is canceled and retried
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "1", 60_000)))
+
+	require.Eventually(t, func() bool {
+		timeouts, ok := metrics.ControllerReconcileTimeouts.Get("QIntToStrSleepingController").(*expvar.Int)
+
+		return ok && timeouts.Value() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = safe.StateUpdateWithConflicts(ctx, st, conformance.NewIntResource("ints", "1", 0).Metadata(), func(r *conformance.IntResource) error {
+		r.SetValue(1)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("strings", "1", "").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+		res, ok := r.(*conformance.StrResource)
+
+		return ok && res.Value() == "1", nil
+	}))
+	require.NoError(t, err)
+
+	timeouts, ok := metrics.ControllerReconcileTimeouts.Get("StuckController").(*expvar.Int)
+	require.True(t, ok)
+	assert.Positive(t, timeouts.Value())
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimePriorityClasses(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t),
+		options.WithPriorityClass("critical", 10),
+		options.WithReconcileSlots(1),
+	)
+	require.NoError(t, err)
+
+	require.EqualError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "ints",
+		TargetNamespace: "strings",
+		PriorityClass:   "unknown",
+	}), `error initializing controller "QIntToStrController" adapter: unknown priority class "unknown" for controller "QIntToStrController"`)
+
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "ints",
+		TargetNamespace: "strings",
+		PriorityClass:   "critical",
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	// controller with 4 workers progresses with a single reconcile slot
+	for i := range 10 {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", strconv.Itoa(i), i)))
+	}
+
+	for i := range 10 {
+		_, err = st.WatchFor(ctx, conformance.NewStrResource("strings", strconv.Itoa(i), "").Metadata(), state.WithEventTypes(state.Created))
+		require.NoError(t, err)
+	}
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimeRateLimits(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "ints",
+		TargetNamespace: "strings",
+		RateLimits: controller.RateLimits{
+			Writes:         controller.RateLimit{Limit: 20},
+			UncachedReads:  controller.RateLimit{Limit: 20},
+			ItemReconciles: controller.RateLimit{Limit: 5},
+		},
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "1", 0)))
+
+	_, err = st.WatchFor(ctx, conformance.NewStrResource("strings", "1", "").Metadata(), state.WithEventTypes(state.Created))
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		_, err = safe.StateUpdateWithConflicts(ctx, st, conformance.NewIntResource("ints", "1", 0).Metadata(), func(r *conformance.IntResource) error {
+			r.SetValue(i)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		// the item is throttled, but it converges to the latest value
+		_, err = st.WatchFor(ctx, conformance.NewStrResource("strings", "1", "").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+			res, ok := r.(*conformance.StrResource)
+
+			return ok && res.Value() == strconv.Itoa(i), nil
+		}))
+		require.NoError(t, err)
+	}
+
+	for _, m := range []*expvar.Map{metrics.ControllerWriteThrottled, metrics.ControllerReadThrottled, metrics.QControllerReconcileThrottled} {
+		throttled, ok := m.Get("QIntToStrController").(*expvar.Float)
+		require.True(t, ok)
+		assert.Positive(t, throttled.Value())
+	}
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimeDryRun(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t),
+		options.WithDryRun("QIntToStrController"),
+		options.WithCachedResource("ints", conformance.IntResourceType),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterQController(&conformance.QIntToStrController{
+		SourceNamespace: "ints",
+		TargetNamespace: "strings",
+	}))
+
+	_, err = rt.DryRunPlan("unknown")
+	require.EqualError(t, err, `controller "unknown" is not registered`)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "1", 1)))
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "2", 2)))
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	var created []string
+
+	require.Eventually(t, func() bool {
+		plan, planErr := rt.DryRunPlan("QIntToStrController")
+		require.NoError(t, planErr)
+
+		created = created[:0]
+
+		for _, change := range plan {
+			if change.Operation == dryrun.OperationCreate && change.New.Metadata().Type() == conformance.StrResourceType {
+				created = append(created, change.New.(*conformance.StrResource).Value()) //nolint:forcetypeassert
+			}
+		}
+
+		return len(created) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"1", "2"}, created)
+
+	// state is not modified
+	strs, err := st.List(ctx, resource.NewMetadata("strings", conformance.StrResourceType, "", resource.VersionUndefined))
+	require.NoError(t, err)
+	assert.Empty(t, strs.Items)
+
+	ints, err := st.List(ctx, resource.NewMetadata("ints", conformance.IntResourceType, "", resource.VersionUndefined))
+	require.NoError(t, err)
+
+	for _, r := range ints.Items {
+		assert.True(t, r.Metadata().Finalizers().Empty())
+		assert.Equal(t, resource.VersionUndefined.Next(), r.Metadata().Version())
+	}
+
+	// reset drops the plan, the following changes are planned from scratch
+	require.NoError(t, rt.ResetDryRunPlan("QIntToStrController"))
+	require.EqualError(t, rt.ResetDryRunPlan("unknown"), `controller "unknown" is not registered`)
+
+	plan, err := rt.DryRunPlan("QIntToStrController")
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "3", 3)))
+
+	require.Eventually(t, func() bool {
+		plan, err = rt.DryRunPlan("QIntToStrController")
+		require.NoError(t, err)
+
+		created = created[:0]
+
+		for _, change := range plan {
+			if change.Operation == dryrun.OperationCreate && change.New.Metadata().Type() == conformance.StrResourceType {
+				created = append(created, change.New.(*conformance.StrResource).Value()) //nolint:forcetypeassert
+			}
+		}
+
+		return len(created) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"3"}, created)
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
+
+func TestRuntimeParkedItems(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t), options.WithControllerStatus("status"))
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterQController(&conformance.QFailingController{
+		SourceNamespace: "source",
+		TargetNamespace: "target",
+		Backoff: controller.BackoffPolicy{
+			InitialInterval: 10 * time.Millisecond,
+			MaxInterval:     50 * time.Millisecond,
+			Multiplier:      2,
+			Jitter:          optional.Some(0.0),
+			MaxAttempts:     3,
+		},
+	}))
+	require.NoError(t, rt.RegisterController(&conformance.StrToSentenceController{
+		SourceNamespace: "strings",
+		TargetNamespace: "sentences",
+	}))
+
+	_, err = rt.ParkedItems("StrToSentenceController")
+	require.Error(t, err)
+
+	_, err = rt.ParkedItems("NonExistent")
+	require.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	t.Cleanup(runCancel)
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	require.NoError(t, st.Create(ctx, conformance.NewStrResource("source", "1", "fail")))
+	require.NoError(t, st.Create(ctx, conformance.NewStrResource("source", "2", "permanent")))
+
+	waitParked := func(ids ...resource.ID) map[resource.ID]controller.ParkedItem {
+		parked := map[resource.ID]controller.ParkedItem{}
+
+		require.EventuallyWithT(t, func(collect *assert.CollectT) {
+			items, err := rt.ParkedItems("QFailingController")
+			require.NoError(collect, err)
+
+			clear(parked)
+
+			for _, item := range items {
+				parked[item.Pointer.ID()] = item
+			}
+
+			require.Len(collect, parked, len(ids))
+
+			for _, id := range ids {
+				require.Contains(collect, parked, id)
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+
+		return parked
+	}
+
+	parked := waitParked("1", "2")
+	assert.Equal(t, conformance.StrResourceType, parked["1"].Pointer.Type())
+	assert.Equal(t, 3, parked["1"].Attempts)
+	assert.Equal(t, "failing as requested", parked["1"].LastError)
+
+	// permanent error parks the item without retries
+	assert.Equal(t, 1, parked["2"].Attempts)
+	assert.Equal(t, "failing permanently as requested", parked["2"].LastError)
+
+	// failed reconciles are not controller restarts
+	rtestutils.AssertResource(ctx, t, st, "QFailingController", func(r *status.ControllerStatus, assert *assert.Assertions) {
+		assert.Equal(uint64(4), r.TypedSpec().Failures)
+		assert.Zero(r.TypedSpec().Restarts)
+	}, rtestutils.WithNamespace("status"))
+
+	deadLetters, ok := metrics.QControllerDeadLetters.Get("QFailingController").(expvar.Func)
+	require.True(t, ok)
+	assert.EqualValues(t, 2, deadLetters())
+
+	// explicit retry parks the item again after exhausting the attempts
+	retried, err := rt.RetryParkedItems("QFailingController", conformance.NewStrResource("source", "3", "").Metadata())
+	require.NoError(t, err)
+	assert.Zero(t, retried)
+
+	retried, err = rt.RetryParkedItems("QFailingController", conformance.NewStrResource("source", "1", "").Metadata())
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried)
+
+	parkedAgain := waitParked("1", "2")
+	assert.True(t, parkedAgain["1"].ParkedAt.After(parked["1"].ParkedAt))
+	assert.Equal(t, parked["2"].ParkedAt, parkedAgain["2"].ParkedAt)
+
+	// input changes re-admit the items
+	for _, id := range []resource.ID{"1", "2"} {
+		_, err = safe.StateUpdateWithConflicts(ctx, st, conformance.NewStrResource("source", id, "").Metadata(), func(r *conformance.StrResource) error {
+			r.SetValue("ok")
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = st.WatchFor(ctx, conformance.NewStrResource("target", id, "").Metadata(), state.WithEventTypes(state.Created))
+		require.NoError(t, err)
+	}
+
+	parkedItems, err := rt.ParkedItems("QFailingController")
+	require.NoError(t, err)
+	assert.Empty(t, parkedItems)
+
+	// unregistered controller is removed from the metrics
+	require.NoError(t, rt.UnregisterController("QFailingController"))
+	assert.Nil(t, metrics.QControllerDeadLetters.Get("QFailingController"))
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
@@ -0,0 +1,152 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// snapshotSumController sums the even and odd IntResources from a single snapshot.
+type snapshotSumController struct{}
+
+func (ctrl *snapshotSumController) Name() string {
+	return "SnapshotSumController"
+}
+
+func (ctrl *snapshotSumController) Inputs() []controller.Input {
+	return []controller.Input{
+		{
+			Namespace: "ints",
+			Type:      conformance.IntResourceType,
+			Kind:      controller.InputWeak,
+		},
+		{
+			Namespace: "uncached",
+			Type:      conformance.IntResourceType,
+			Kind:      controller.InputWeak,
+		},
+	}
+}
+
+func (ctrl *snapshotSumController) Outputs() []controller.Output {
+	return []controller.Output{
+		{
+			Type: conformance.StrResourceType,
+			Kind: controller.OutputExclusive,
+		},
+	}
+}
+
+func (ctrl *snapshotSumController) Run(ctx context.Context, r controller.Runtime, _ *zap.Logger) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.EventCh():
+		}
+
+		snapshot, err := safe.ReaderSnapshot(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		var sum int
+
+		for _, index := range []string{"even", "odd"} {
+			list, err := safe.ReaderListByIndex[*conformance.IntResource](ctx, snapshot, resource.NewMetadata("ints", conformance.IntResourceType, "", resource.VersionUndefined), "parity", index)
+			if err != nil {
+				return err
+			}
+
+			for res := range list.All() {
+				sum += res.Value()
+			}
+		}
+
+		// uncached reads are served by the runtime
+		list, err := safe.ReaderList[*conformance.IntResource](ctx, snapshot, resource.NewMetadata("uncached", conformance.IntResourceType, "", resource.VersionUndefined))
+		if err != nil {
+			return err
+		}
+
+		for res := range list.All() {
+			sum += res.Value()
+		}
+
+		if err = safe.WriterModify(ctx, r, conformance.NewStrResource("strings", "sum", ""), func(res *conformance.StrResource) error {
+			res.SetValue(strconv.Itoa(sum))
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestRuntimeCacheSnapshot(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	for i, id := range []resource.ID{"one", "two", "three"} {
+		require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", id, i+1)))
+	}
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("uncached", "ten", 10)))
+
+	rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t),
+		options.WithCachedResource("ints", conformance.IntResourceType, options.WithIndex("parity", parityIndex)),
+		options.WithCachedResource("strings", conformance.StrResourceType),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, rt.RegisterController(&snapshotSumController{}))
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+
+	_, errCh := future.GoContext(runCtx, rt.Run)
+
+	waitSum := func(expected string) {
+		_, err := st.WatchFor(ctx, conformance.NewStrResource("strings", "sum", "").Metadata(), state.WithCondition(func(r resource.Resource) (bool, error) {
+			str, ok := r.(*conformance.StrResource)
+
+			return ok && str.Value() == expected, nil
+		}))
+		require.NoError(t, err)
+	}
+
+	waitSum("16")
+
+	require.NoError(t, st.Create(ctx, conformance.NewIntResource("ints", "four", 4)))
+
+	waitSum("20")
+
+	runCancel()
+
+	require.NoError(t, <-errCh)
+}
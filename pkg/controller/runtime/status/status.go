@@ -0,0 +1,152 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package status provides the ControllerStatus resource published by the controller runtime.
+//
+// See options.WithControllerStatus.
+package status
+
+import (
+	"time"
+
+	"github.com/siderolabs/gen/ensure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cosi-project/runtime/api/controller_status"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// ControllerStatusType is the type of ControllerStatus.
+const ControllerStatusType = resource.Type("ControllerStatuses.meta.cosi.dev")
+
+// ControllerStatus describes the state of a single controller, resource ID is the controller name.
+type ControllerStatus = typed.Resource[ControllerStatusSpec, ControllerStatusExtension]
+
+// NewControllerStatus initializes a ControllerStatus resource.
+func NewControllerStatus(ns resource.Namespace, id resource.ID) *ControllerStatus {
+	return typed.NewResource[ControllerStatusSpec, ControllerStatusExtension](
+		resource.NewMetadata(ns, ControllerStatusType, id, resource.VersionUndefined),
+		ControllerStatusSpec{},
+	)
+}
+
+// ControllerStatusExtension provides auxiliary methods for ControllerStatus.
+type ControllerStatusExtension struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (ControllerStatusExtension) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             ControllerStatusType,
+		DefaultNamespace: meta.NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "State",
+				JSONPath: "{.state}",
+			},
+			{
+				Name:     "Restarts",
+				JSONPath: "{.restarts}",
+			},
+			{
+				Name:     "Failures",
+				JSONPath: "{.failures}",
+			},
+		},
+	}
+}
+
+// State of the controller.
+type State string
+
+// Controller states.
+const (
+	// StateRunning is the state of a normally running controller.
+	StateRunning State = "Running"
+	// StateBackoff is the state of a controller waiting to be restarted after a failure.
+	StateBackoff State = "Backoff"
+	// StatePaused is the state of a controller with writes paused.
+	StatePaused State = "Paused"
+	// StateStopped is the state of a controller which is not running.
+	StateStopped State = "Stopped"
+)
+
+// ControllerStatusSpec provides ControllerStatus definition.
+//
+// Restarts counts the restarts of the controller (or of the QController run hook) after a failure.
+//
+// For QControllers, LastReconcile is updated on each successful reconcile, Failures counts failed reconciles,
+// and BackoffUntil is the time of the latest requeue after a failure.
+//
+// For Controllers, LastReconcile is updated each time the controller calls ResetRestartBackoff.
+//
+//nolint:recvcheck
+type ControllerStatusSpec struct {
+	LastReconcile time.Time `yaml:"lastReconcile,omitempty"`
+	BackoffUntil  time.Time `yaml:"backoffUntil,omitempty"`
+	State         State     `yaml:"state"`
+	LastError     string    `yaml:"lastError,omitempty"`
+	Restarts      uint64    `yaml:"restarts"`
+	Failures      uint64    `yaml:"failures"`
+	QueueLength   int64     `yaml:"queueLength"`
+}
+
+// DeepCopy generates a deep copy of ControllerStatusSpec.
+func (spec ControllerStatusSpec) DeepCopy() ControllerStatusSpec {
+	return spec
+}
+
+// MarshalProto implements ProtoMarshaler.
+func (spec ControllerStatusSpec) MarshalProto() ([]byte, error) {
+	protoSpec := controller_status.ControllerStatusSpec{
+		State:       string(spec.State),
+		LastError:   spec.LastError,
+		Restarts:    spec.Restarts,
+		Failures:    spec.Failures,
+		QueueLength: spec.QueueLength,
+	}
+
+	if !spec.LastReconcile.IsZero() {
+		protoSpec.LastReconcile = timestamppb.New(spec.LastReconcile)
+	}
+
+	if !spec.BackoffUntil.IsZero() {
+		protoSpec.BackoffUntil = timestamppb.New(spec.BackoffUntil)
+	}
+
+	return protobuf.ProtoMarshal(&protoSpec)
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (spec *ControllerStatusSpec) UnmarshalProto(protoBytes []byte) error {
+	protoSpec := controller_status.ControllerStatusSpec{}
+
+	if err := protobuf.ProtoUnmarshal(protoBytes, &protoSpec); err != nil {
+		return err
+	}
+
+	*spec = ControllerStatusSpec{
+		State:       State(protoSpec.State),
+		LastError:   protoSpec.LastError,
+		Restarts:    protoSpec.Restarts,
+		Failures:    protoSpec.Failures,
+		QueueLength: protoSpec.QueueLength,
+	}
+
+	if protoSpec.LastReconcile != nil {
+		spec.LastReconcile = protoSpec.LastReconcile.AsTime()
+	}
+
+	if protoSpec.BackoffUntil != nil {
+		spec.BackoffUntil = protoSpec.BackoffUntil.AsTime()
+	}
+
+	return nil
+}
+
+func init() {
+	ensure.NoError(protobuf.RegisterResource(ControllerStatusType, &ControllerStatus{}))
+}
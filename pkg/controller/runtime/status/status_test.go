@@ -0,0 +1,52 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package status_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/status"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+)
+
+func TestControllerStatusProtobuf(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []status.ControllerStatusSpec{
+		{
+			State: status.StateRunning,
+		},
+		{
+			State:         status.StateBackoff,
+			LastReconcile: time.Unix(1000, 0).UTC(),
+			BackoffUntil:  time.Unix(2000, 0).UTC(),
+			LastError:     "failed",
+			Restarts:      3,
+			Failures:      7,
+			QueueLength:   5,
+		},
+	} {
+		res := status.NewControllerStatus("status", "controller")
+		*res.TypedSpec() = spec
+
+		protoR, err := protobuf.FromResource(res)
+		require.NoError(t, err)
+
+		marshaled, err := protoR.Marshal()
+		require.NoError(t, err)
+
+		unmarshaled, err := protobuf.Unmarshal(marshaled)
+		require.NoError(t, err)
+
+		var got status.ControllerStatus
+
+		require.NoError(t, unmarshaled.Unmarshal(&got))
+		assert.Equal(t, spec, *got.TypedSpec())
+	}
+}
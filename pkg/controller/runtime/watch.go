@@ -0,0 +1,283 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/siderolabs/gen/channel"
+	"go.uber.org/zap"
+
+	"github.com/cosi-project/runtime/pkg/controller/runtime/internal/adapter"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/metrics"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// watchRecoveryResetInterval is the interval after which the watch is considered healthy, and the failures are forgiven.
+const watchRecoveryResetInterval = time.Minute
+
+// errCacheResync stops the watch to establish it again with the bootstrap contents, so that the cache which exceeded
+// the size cap is recovered.
+var errCacheResync = errors.New("cache resync requested")
+
+// watchEvents is a batch of events of a single watch.
+type watchEvents struct {
+	events []state.Event
+
+	// key of the watch which delivered the events
+	key watchKey
+
+	// cached is set if the watch populates the cache
+	cached bool
+
+	// resync is set for the bootstrapped contents of a watch which was established again (see kindWatch).
+	resync bool
+
+	// resyncCh requests the resync of the cached watch (see kindWatch)
+	resyncCh chan<- struct{}
+}
+
+// requestResync asks the watch which delivered the events to resync the cache.
+func (batch watchEvents) requestResync() {
+	select {
+	case batch.resyncCh <- struct{}{}:
+	default:
+	}
+}
+
+// kindWatch runs the watch of a single resource kind, forwarding the events to the runtime.
+//
+// If the watch fails, it is resumed from the last bookmark, or established again with the bootstrap contents
+// (which resyncs the cache and notifies the controllers about all resources of the kind).
+//
+// The cached watch is also established again with the bootstrap contents on request to recover the cache
+// which exceeded the size cap (see cache.ResourceCache.NeedsResync).
+type kindWatch struct {
+	runtime *Runtime
+	logger  *zap.Logger
+	kind    resource.Kind
+
+	resyncCh chan struct{}
+
+	// bookmark of the last forwarded event, nil if the watch can't be resumed
+	bookmark state.Bookmark
+
+	cached bool
+
+	// set if the kind namespace is a namespace pattern
+	pattern bool
+
+	// set once the bootstrapped contents are received (always set if the watch has no bootstrapped contents)
+	bootstrapped bool
+
+	// set while the bootstrapped contents of the watch established again are received
+	resyncing bool
+}
+
+// startWatch establishes the watch of the resource kind and starts forwarding the events.
+func (runtime *Runtime) startWatch(key watchKey, cached bool) error {
+	w := &kindWatch{
+		runtime: runtime,
+		logger:  runtime.logger.With(zap.String("namespace", key.Namespace), zap.String("type", key.Type)),
+		kind:    resource.NewMetadata(key.Namespace, key.Type, "", resource.Version{}),
+		cached:  cached,
+		pattern: key.Pattern,
+
+		resyncCh: make(chan struct{}, 1),
+	}
+
+	ch, cancel, err := w.establish(state.WithBootstrapContents(cached), state.WithBootstrapBookmark(!cached))
+	if err != nil {
+		return err
+	}
+
+	w.bootstrapped = !cached
+
+	goFunc(&runtime.group, func() { w.run(ch, cancel) })
+
+	return nil
+}
+
+func (w *kindWatch) establish(opts ...state.WatchKindOption) (chan []state.Event, context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(w.runtime.runCtx)
+	ch := make(chan []state.Event, watchBuffer)
+
+	if w.pattern {
+		opts = append(opts, state.WithNamespacePattern(w.kind.Namespace()))
+	}
+
+	if err := w.runtime.state.WatchKindAggregated(ctx, w.kind, ch, opts...); err != nil {
+		cancel()
+
+		return nil, nil, err
+	}
+
+	return ch, cancel, nil
+}
+
+// run the watch until the runtime is stopped, or the watch recovery budget is exhausted.
+func (w *kindWatch) run(ch chan []state.Event, cancel context.CancelFunc) {
+	ctx := w.runtime.runCtx
+	recovery := w.runtime.options.WatchRecovery
+	backoff := adapter.NewBackOff(recovery.Backoff)
+
+	var failures int
+
+	for {
+		startTime := time.Now()
+
+		err := w.forward(ctx, ch)
+
+		cancel()
+
+		if err == nil {
+			return
+		}
+
+		if errors.Is(err, errCacheResync) {
+			if ch, cancel, err = w.resync(); err == nil {
+				continue
+			}
+		}
+
+		// automatically reset the failures if the watch was running long enough
+		if time.Since(startTime) > watchRecoveryResetInterval {
+			failures = 0
+
+			backoff.Reset()
+		}
+
+		for {
+			failures++
+
+			if failures > recovery.Budget {
+				w.logger.Error("watch failed, recovery budget exhausted", zap.Error(err), zap.Int("failures", failures))
+
+				select {
+				case w.runtime.watchErrors <- err:
+				case <-ctx.Done():
+				}
+
+				return
+			}
+
+			interval := backoff.NextBackOff()
+
+			w.logger.Warn("watch failed, establishing it again", zap.Error(err), zap.Int("failures", failures), zap.Duration("interval", interval))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			if ch, cancel, err = w.reestablish(); err == nil {
+				break
+			}
+		}
+	}
+}
+
+// forward the watch events to the runtime.
+//
+// forward returns nil if the context is canceled, or the error if the watch failed.
+func (w *kindWatch) forward(ctx context.Context, ch <-chan []state.Event) error {
+	for {
+		var events []state.Event
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.resyncCh:
+			if w.bootstrapped {
+				return errCacheResync
+			}
+
+			continue
+		case events = <-ch:
+		}
+
+		var watchErr error
+
+		batch := watchEvents{
+			events:   events,
+			key:      watchKey{Namespace: w.kind.Namespace(), Type: w.kind.Type(), Pattern: w.pattern},
+			cached:   w.cached,
+			resync:   w.resyncing,
+			resyncCh: w.resyncCh,
+		}
+
+	eventLoop:
+		for i, e := range events {
+			switch e.Type { //nolint:exhaustive
+			case state.Errored:
+				batch.events, watchErr = events[:i], e.Error
+
+				if watchErr == nil {
+					watchErr = errors.New("watch failed")
+				}
+
+				break eventLoop
+			case state.Bootstrapped:
+				w.bootstrapped, w.resyncing = true, false
+			}
+
+			// bookmarks of the bootstrapped contents can't be used to resume the watch, as the contents would be lost
+			if w.bootstrapped && e.Bookmark != nil {
+				w.bookmark = e.Bookmark
+			}
+		}
+
+		if len(batch.events) > 0 && !channel.SendWithContext(ctx, w.runtime.watchCh, batch) {
+			return nil
+		}
+
+		if watchErr != nil {
+			return watchErr
+		}
+	}
+}
+
+// reestablish the failed watch.
+func (w *kindWatch) reestablish() (chan []state.Event, context.CancelFunc, error) {
+	if w.runtime.options.MetricsEnabled {
+		metrics.WatchRestarts.Add(w.kind.Type(), 1)
+	}
+
+	if w.bookmark != nil {
+		ch, cancel, err := w.establish(state.WithKindStartFromBookmark(w.bookmark))
+		if err == nil {
+			w.logger.Info("watch resumed from the bookmark")
+
+			return ch, cancel, nil
+		}
+
+		w.logger.Warn("failed to resume the watch from the bookmark, bootstrapping it again", zap.Error(err))
+	}
+
+	return w.resync()
+}
+
+// resync establishes the watch again with the bootstrap contents.
+func (w *kindWatch) resync() (chan []state.Event, context.CancelFunc, error) {
+	if w.cached {
+		w.runtime.cache.BeginResync(w.kind.Namespace(), w.kind.Type())
+	}
+
+	ch, cancel, err := w.establish(state.WithBootstrapContents(true))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.logger.Info("watch bootstrapped again, resyncing")
+
+	w.bookmark = nil
+	w.bootstrapped, w.resyncing = false, true
+
+	return ch, cancel, nil
+}
@@ -0,0 +1,200 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package runtime_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/siderolabs/gen/channel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/cosi-project/runtime/pkg/controller"
+	"github.com/cosi-project/runtime/pkg/controller/conformance"
+	"github.com/cosi-project/runtime/pkg/controller/runtime"
+	"github.com/cosi-project/runtime/pkg/controller/runtime/options"
+	"github.com/cosi-project/runtime/pkg/future"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+// failingWatchState fails the aggregated watches of IntResource once the fail channel is closed.
+//
+// The watches are established again only once the resume channel is closed.
+type failingWatchState struct {
+	state.CoreState
+
+	fail   chan struct{}
+	resume chan struct{}
+
+	active atomic.Int64
+
+	rejectBookmark bool
+}
+
+func (st *failingWatchState) WatchKindAggregated(ctx context.Context, kind resource.Kind, ch chan<- []state.Event, opts ...state.WatchKindOption) error {
+	if kind.Type() != conformance.IntResourceType {
+		return st.CoreState.WatchKindAggregated(ctx, kind, ch, opts...)
+	}
+
+	failCh := st.fail
+
+	select {
+	case <-st.fail:
+		select {
+		case <-st.resume:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// the watch is established again, don't fail it
+		failCh = nil
+
+		var options state.WatchKindOptions
+
+		for _, opt := range opts {
+			opt(&options)
+		}
+
+		if st.rejectBookmark && options.StartFromBookmark != nil {
+			return errors.New("bookmark is too old")
+		}
+	default:
+	}
+
+	innerCtx, innerCancel := context.WithCancel(ctx)
+	innerCh := make(chan []state.Event)
+
+	if err := st.CoreState.WatchKindAggregated(innerCtx, kind, innerCh, opts...); err != nil {
+		innerCancel()
+
+		return err
+	}
+
+	st.active.Add(1)
+
+	go func() {
+		defer innerCancel()
+		defer st.active.Add(-1)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-failCh:
+				channel.SendWithContext(ctx, ch, []state.Event{{Type: state.Errored, Error: errors.New("injected failure")}})
+
+				return
+			case events := <-innerCh:
+				if !channel.SendWithContext(ctx, ch, events) {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func TestRuntimeWatchRecovery(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		rejectBookmark bool
+	}{
+		{
+			name: "resume",
+		},
+		{
+			name:           "bootstrap",
+			rejectBookmark: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+			failing := &failingWatchState{
+				CoreState:      namespaced.NewState(inmem.Build),
+				fail:           make(chan struct{}),
+				resume:         make(chan struct{}),
+				rejectBookmark: test.rejectBookmark,
+			}
+			st := state.WrapCore(failing)
+
+			rt, err := runtime.NewRuntime(st, zaptest.NewLogger(t),
+				options.WithCachedResource("cached", conformance.IntResourceType),
+				options.WithWatchRecovery(3, controller.BackoffPolicy{InitialInterval: 10 * time.Millisecond}),
+			)
+			require.NoError(t, err)
+
+			require.NoError(t, rt.RegisterController(&conformance.IntToStrController{
+				SourceNamespace: "default",
+				TargetNamespace: "default",
+			}))
+
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			for _, id := range []resource.ID{"a", "b"} {
+				require.NoError(t, st.Create(ctx, conformance.NewIntResource("cached", id, 0)))
+			}
+
+			runCtx, runCancel := context.WithCancel(ctx)
+			defer runCancel()
+
+			_, errCh := future.GoContext(runCtx, rt.Run)
+
+			require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "1", 1)))
+
+			_, err = st.WatchFor(ctx, conformance.NewStrResource("default", "1", "").Metadata(), state.WithEventTypes(state.Created))
+			require.NoError(t, err)
+
+			assertCachedIDs(ctx, t, rt, "a", "b")
+
+			// fail the watches, and wait for them to be gone
+			close(failing.fail)
+
+			require.Eventually(t, func() bool { return failing.active.Load() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+			// changes while the watches are failed
+			require.NoError(t, st.Create(ctx, conformance.NewIntResource("default", "2", 2)))
+			require.NoError(t, st.Destroy(ctx, conformance.NewIntResource("cached", "a", 0).Metadata()))
+			require.NoError(t, st.Create(ctx, conformance.NewIntResource("cached", "c", 0)))
+
+			close(failing.resume)
+
+			_, err = st.WatchFor(ctx, conformance.NewStrResource("default", "2", "").Metadata(), state.WithEventTypes(state.Created))
+			require.NoError(t, err)
+
+			assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+				assertCachedIDs(ctx, collect, rt, "b", "c")
+			}, 5*time.Second, 10*time.Millisecond)
+
+			runCancel()
+
+			require.NoError(t, <-errCh)
+		})
+	}
+}
+
+func assertCachedIDs(ctx context.Context, t require.TestingT, rt *runtime.Runtime, expected ...resource.ID) {
+	list, err := rt.CachedState().List(ctx, resource.NewMetadata("cached", conformance.IntResourceType, "", resource.VersionUndefined))
+	require.NoError(t, err)
+
+	ids := make([]resource.ID, 0, len(list.Items))
+
+	for _, r := range list.Items {
+		ids = append(ids, r.Metadata().ID())
+	}
+
+	assert.Equal(t, expected, ids)
+}
@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// ErrHeld is returned when the lease is held by another holder.
+var ErrHeld = errors.New("lease is held by another holder")
+
+// ErrLost is returned when the lease is no longer held by the holder.
+var ErrLost = errors.New("lease is lost")
+
+// Acquire the lease for the holder.
+//
+// Lease is created if it doesn't exist yet. If the lease is held by another holder and it is not expired,
+// an error wrapping ErrHeld is returned. If the lease is already held by the holder, it is renewed.
+//
+// Concurrent updates are resolved via version-based optimistic concurrency (see state.UpdateWithConflicts),
+// so only a single holder can acquire the lease.
+func Acquire(ctx context.Context, st state.State, ptr resource.Pointer, holder string, duration time.Duration) (*Lease, error) {
+	now := time.Now()
+
+	lease := NewLease(ptr.Namespace(), ptr.ID())
+	*lease.TypedSpec() = LeaseSpec{
+		Holder:   holder,
+		Acquired: now,
+		Renewed:  now,
+		Duration: duration,
+	}
+
+	err := st.Create(ctx, lease)
+	if err == nil {
+		return lease, nil
+	}
+
+	if !state.IsConflictError(err) {
+		return nil, err
+	}
+
+	lease, err = safe.StateUpdateWithConflicts(ctx, st, ptr, func(lease *Lease) error {
+		spec := lease.TypedSpec()
+		now := time.Now()
+
+		switch {
+		case spec.Holder == holder:
+		case spec.Expired(now):
+			spec.Holder = holder
+			spec.Acquired = now
+			spec.Transitions++
+		default:
+			return fmt.Errorf("%w: %q", ErrHeld, spec.Holder)
+		}
+
+		spec.Renewed = now
+		spec.Duration = duration
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// Renew the lease held by the holder.
+//
+// If the lease is not held by the holder anymore, or it has already expired, an error wrapping ErrLost is returned.
+// Expired lease can't be renewed, as another holder might have observed the expiration; the holder
+// should acquire the lease again instead.
+func Renew(ctx context.Context, st state.State, ptr resource.Pointer, holder string) (*Lease, error) {
+	lease, err := safe.StateUpdateWithConflicts(ctx, st, ptr, func(lease *Lease) error {
+		spec := lease.TypedSpec()
+		now := time.Now()
+
+		if spec.Holder != holder {
+			return fmt.Errorf("%w: held by %q", ErrLost, spec.Holder)
+		}
+
+		if spec.Expired(now) {
+			return fmt.Errorf("%w: expired at %s", ErrLost, spec.Renewed.Add(spec.Duration))
+		}
+
+		spec.Renewed = now
+
+		return nil
+	})
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %w", ErrLost, err)
+		}
+
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// Release the lease held by the holder, so that other holders can acquire it without waiting for the expiration.
+//
+// Releasing a lease which is not held by the holder is a no-op.
+func Release(ctx context.Context, st state.State, ptr resource.Pointer, holder string) error {
+	_, err := safe.StateUpdateWithConflicts(ctx, st, ptr, func(lease *Lease) error {
+		spec := lease.TypedSpec()
+
+		if spec.Holder == holder {
+			spec.Holder = ""
+		}
+
+		return nil
+	})
+	if state.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package lease provides a Lease resource and helpers to implement leader election on top of the State.
+package lease
+
+import (
+	"time"
+
+	"github.com/siderolabs/gen/ensure"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	leasepb "github.com/cosi-project/runtime/api/lease"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// LeaseType is the type of Lease.
+const LeaseType = resource.Type("Leases.meta.cosi.dev")
+
+// Lease is a resource which is held by a single holder at a time.
+type Lease = typed.Resource[LeaseSpec, LeaseExtension]
+
+// NewLease initializes a Lease resource.
+func NewLease(ns resource.Namespace, id resource.ID) *Lease {
+	return typed.NewResource[LeaseSpec, LeaseExtension](
+		resource.NewMetadata(ns, LeaseType, id, resource.VersionUndefined),
+		LeaseSpec{},
+	)
+}
+
+// LeaseExtension provides auxiliary methods for Lease.
+type LeaseExtension struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (LeaseExtension) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             LeaseType,
+		DefaultNamespace: meta.NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Holder",
+				JSONPath: "{.holder}",
+			},
+		},
+	}
+}
+
+// LeaseSpec provides Lease definition.
+//
+//nolint:recvcheck
+type LeaseSpec struct {
+	Acquired    time.Time     `yaml:"acquired"`
+	Renewed     time.Time     `yaml:"renewed"`
+	Holder      string        `yaml:"holder"`
+	Duration    time.Duration `yaml:"duration"`
+	Transitions uint64        `yaml:"transitions"`
+}
+
+// Expired returns true if the lease is not held or wasn't renewed in time.
+func (spec LeaseSpec) Expired(now time.Time) bool {
+	return spec.Holder == "" || !now.Before(spec.Renewed.Add(spec.Duration))
+}
+
+// DeepCopy generates a deep copy of LeaseSpec.
+func (spec LeaseSpec) DeepCopy() LeaseSpec {
+	return spec
+}
+
+// MarshalProto implements ProtoMarshaler.
+func (spec LeaseSpec) MarshalProto() ([]byte, error) {
+	protoSpec := leasepb.LeaseSpec{
+		Holder:      spec.Holder,
+		Acquired:    timestamppb.New(spec.Acquired),
+		Renewed:     timestamppb.New(spec.Renewed),
+		Duration:    durationpb.New(spec.Duration),
+		Transitions: spec.Transitions,
+	}
+
+	return protobuf.ProtoMarshal(&protoSpec)
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (spec *LeaseSpec) UnmarshalProto(protoBytes []byte) error {
+	protoSpec := leasepb.LeaseSpec{}
+
+	if err := protobuf.ProtoUnmarshal(protoBytes, &protoSpec); err != nil {
+		return err
+	}
+
+	*spec = LeaseSpec{
+		Holder:      protoSpec.Holder,
+		Acquired:    protoSpec.Acquired.AsTime(),
+		Renewed:     protoSpec.Renewed.AsTime(),
+		Duration:    protoSpec.Duration.AsDuration(),
+		Transitions: protoSpec.Transitions,
+	}
+
+	return nil
+}
+
+func init() {
+	ensure.NoError(protobuf.RegisterResource(LeaseType, &Lease{}))
+}
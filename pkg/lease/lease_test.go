@@ -0,0 +1,97 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lease_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/lease"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+)
+
+func TestLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+	ptr := lease.NewLease("default", "leader").Metadata()
+
+	l, err := lease.Acquire(ctx, st, ptr, "a", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "a", l.TypedSpec().Holder)
+
+	_, err = lease.Acquire(ctx, st, ptr, "b", time.Hour)
+	require.ErrorIs(t, err, lease.ErrHeld)
+
+	_, err = lease.Renew(ctx, st, ptr, "b")
+	require.ErrorIs(t, err, lease.ErrLost)
+
+	renewed, err := lease.Renew(ctx, st, ptr, "a")
+	require.NoError(t, err)
+	assert.True(t, renewed.TypedSpec().Renewed.After(l.TypedSpec().Renewed))
+
+	// releasing a lease held by another holder is a no-op
+	require.NoError(t, lease.Release(ctx, st, ptr, "b"))
+	require.NoError(t, lease.Release(ctx, st, ptr, "a"))
+
+	l, err = lease.Acquire(ctx, st, ptr, "b", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "b", l.TypedSpec().Holder)
+	assert.EqualValues(t, 1, l.TypedSpec().Transitions)
+
+	// lease is not renewed, so it expires
+	time.Sleep(5 * time.Millisecond)
+
+	l, err = lease.Acquire(ctx, st, ptr, "a", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "a", l.TypedSpec().Holder)
+	assert.EqualValues(t, 2, l.TypedSpec().Transitions)
+
+	_, err = lease.Renew(ctx, st, ptr, "b")
+	require.ErrorIs(t, err, lease.ErrLost)
+
+	_, err = lease.Acquire(ctx, st, ptr, "a", time.Millisecond)
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	// expired lease can't be renewed, even if no other holder acquired it
+	_, err = lease.Renew(ctx, st, ptr, "a")
+	require.ErrorIs(t, err, lease.ErrLost)
+}
+
+func TestLeaseProtobuf(t *testing.T) {
+	t.Parallel()
+
+	l := lease.NewLease("default", "leader")
+	*l.TypedSpec() = lease.LeaseSpec{
+		Holder:      "a",
+		Acquired:    time.Unix(1000, 0).UTC(),
+		Renewed:     time.Unix(2000, 0).UTC(),
+		Duration:    time.Minute,
+		Transitions: 3,
+	}
+
+	protoR, err := protobuf.FromResource(l)
+	require.NoError(t, err)
+
+	marshaled, err := protoR.Marshal()
+	require.NoError(t, err)
+
+	unmarshaled, err := protobuf.Unmarshal(marshaled)
+	require.NoError(t, err)
+
+	var got lease.Lease
+
+	require.NoError(t, unmarshaled.Unmarshal(&got))
+	assert.Equal(t, *l.TypedSpec(), *got.TypedSpec())
+}
@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lock
+
+import (
+	"time"
+
+	"github.com/siderolabs/gen/ensure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	lockpb "github.com/cosi-project/runtime/api/lock"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// SemaphoreCounterType is the type of SemaphoreCounterResource.
+const SemaphoreCounterType = resource.Type("SemaphoreCounters.meta.cosi.dev")
+
+// CounterFinalizer is put on the SemaphoreCounterResource, so that it is never destroyed.
+const CounterFinalizer = FinalizerPrefix + "counter"
+
+// SemaphoreCounterResource issues the fencing tokens of the semaphore with the same ID.
+//
+// The counter is kept separately from the SemaphoreResource, so that the tokens keep increasing
+// if the semaphore is destroyed and created again.
+type SemaphoreCounterResource = typed.Resource[SemaphoreCounterSpec, SemaphoreCounterExtension]
+
+// NewSemaphoreCounterResource initializes a SemaphoreCounterResource.
+func NewSemaphoreCounterResource(ns resource.Namespace, id resource.ID) *SemaphoreCounterResource {
+	return typed.NewResource[SemaphoreCounterSpec, SemaphoreCounterExtension](
+		resource.NewMetadata(ns, SemaphoreCounterType, id, resource.VersionUndefined),
+		SemaphoreCounterSpec{},
+	)
+}
+
+// SemaphoreCounterExtension provides auxiliary methods for SemaphoreCounterResource.
+type SemaphoreCounterExtension struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (SemaphoreCounterExtension) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             SemaphoreCounterType,
+		DefaultNamespace: meta.NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Last Token",
+				JSONPath: "{.lastToken}",
+			},
+			{
+				Name:     "Last Holder",
+				JSONPath: "{.lastHolder}",
+			},
+		},
+	}
+}
+
+// SemaphoreCounterSpec provides SemaphoreCounterResource definition.
+//
+//nolint:recvcheck
+type SemaphoreCounterSpec struct {
+	Issued     time.Time `yaml:"issued"`
+	LastHolder string    `yaml:"lastHolder"`
+	LastToken  Token     `yaml:"lastToken"`
+}
+
+// DeepCopy generates a deep copy of SemaphoreCounterSpec.
+func (spec SemaphoreCounterSpec) DeepCopy() SemaphoreCounterSpec {
+	return spec
+}
+
+// MarshalProto implements ProtoMarshaler.
+func (spec SemaphoreCounterSpec) MarshalProto() ([]byte, error) {
+	protoSpec := lockpb.SemaphoreCounterSpec{
+		LastToken:  uint64(spec.LastToken),
+		LastHolder: spec.LastHolder,
+		Issued:     timestamppb.New(spec.Issued),
+	}
+
+	return protobuf.ProtoMarshal(&protoSpec)
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (spec *SemaphoreCounterSpec) UnmarshalProto(protoBytes []byte) error {
+	protoSpec := lockpb.SemaphoreCounterSpec{}
+
+	if err := protobuf.ProtoUnmarshal(protoBytes, &protoSpec); err != nil {
+		return err
+	}
+
+	*spec = SemaphoreCounterSpec{
+		LastToken:  Token(protoSpec.LastToken),
+		LastHolder: protoSpec.LastHolder,
+		Issued:     protoSpec.Issued.AsTime(),
+	}
+
+	return nil
+}
+
+func init() {
+	ensure.NoError(protobuf.RegisterResource(SemaphoreCounterType, &SemaphoreCounterResource{}))
+}
@@ -0,0 +1,442 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package lock provides distributed locks and semaphores backed by the State.
+//
+// Semaphore state is kept in a SemaphoreResource, updates are resolved via version-based
+// optimistic concurrency. Each holder puts a finalizer on the resource, so that the resource
+// can't be destroyed while the semaphore is held. Semaphore which is being torn down can be released,
+// but it can't be acquired.
+//
+// Each holder gets a lease which should be renewed before it expires. Holders with expired leases
+// are reclaimed by the next acquire, so that a crashed holder doesn't block the semaphore forever.
+// Lease expiration is compared against the local clock of the holders, so the clocks should be roughly in sync.
+//
+// Every successful acquire issues a fencing token which is strictly greater than any token
+// issued before for the same semaphore. Tokens are issued by the SemaphoreCounterResource, which is never
+// destroyed, so tokens keep increasing even if the SemaphoreResource is destroyed and created again.
+// Tokens can be passed to the external systems to reject operations performed by stale holders.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// Token is a fencing token issued on acquire.
+type Token uint64
+
+// ErrNotHeld is returned when the semaphore is not held by the holder.
+var ErrNotHeld = errors.New("semaphore is not held")
+
+var errBusy = errors.New("semaphore is busy")
+
+// FinalizerPrefix is the prefix of the finalizers put by the holders on the SemaphoreResource.
+const FinalizerPrefix = "lock.cosi.dev/"
+
+// Semaphore limits the number of concurrent holders.
+//
+// Semaphore is not safe for concurrent use, each holder should use its own instance.
+type Semaphore struct {
+	st       state.State
+	ns       resource.Namespace
+	id       resource.ID
+	holder   string
+	options  Options
+	capacity uint32
+}
+
+// NewSemaphore creates a semaphore with the specified capacity for the holder.
+//
+// Capacity is set when the SemaphoreResource is created, holders with different capacities
+// use the capacity of the existing resource.
+func NewSemaphore(st state.State, ns resource.Namespace, id resource.ID, capacity uint32, holder string, opts ...Option) (*Semaphore, error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("semaphore capacity should be at least 1, got %d", capacity)
+	}
+
+	options := DefaultOptions()
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.LeaseDuration <= 0 {
+		return nil, fmt.Errorf("lease duration should be positive, got %s", options.LeaseDuration)
+	}
+
+	return &Semaphore{
+		st:       st,
+		ns:       ns,
+		id:       id,
+		holder:   holder,
+		options:  options,
+		capacity: capacity,
+	}, nil
+}
+
+func (sem *Semaphore) pointer() resource.Pointer {
+	return NewSemaphoreResource(sem.ns, sem.id).Metadata()
+}
+
+func (sem *Semaphore) finalizer() resource.Finalizer {
+	return FinalizerPrefix + sem.holder
+}
+
+// Acquire the semaphore, waiting until a slot is available or the context is canceled.
+//
+// Acquiring the semaphore which is already held by the holder returns the current token.
+func (sem *Semaphore) Acquire(ctx context.Context) (Token, error) {
+	for {
+		token, busy, err := sem.tryAcquire(ctx)
+		if err == nil {
+			return token, nil
+		}
+
+		if !errors.Is(err, errBusy) {
+			return 0, err
+		}
+
+		if err = sem.wait(ctx, busy); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// wait for any change of the semaphore, or for the lease of some holder to expire.
+func (sem *Semaphore) wait(ctx context.Context, busy busyState) error {
+	waitCtx, waitCancel := ctx, context.CancelFunc(func() {})
+
+	if !busy.expires.IsZero() {
+		waitCtx, waitCancel = context.WithDeadline(ctx, busy.expires)
+	}
+
+	defer waitCancel()
+
+	_, err := sem.st.WatchFor(waitCtx, sem.pointer(), state.WithCondition(func(r resource.Resource) (bool, error) {
+		return !r.Metadata().Version().Equal(busy.version), nil
+	}))
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		// lease of the holder expired, it can be reclaimed
+		return nil
+	}
+
+	return err
+}
+
+// TryAcquire acquires the semaphore without waiting.
+//
+// If there are no available slots, false is returned.
+func (sem *Semaphore) TryAcquire(ctx context.Context) (Token, bool, error) {
+	token, _, err := sem.tryAcquire(ctx)
+	if err != nil {
+		if errors.Is(err, errBusy) {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return token, true, nil
+}
+
+// busyState describes the semaphore which has no available slots.
+type busyState struct {
+	// expires is the earliest expiration of the holder leases
+	expires time.Time
+	version resource.Version
+}
+
+// busy returns errBusy if the semaphore has no available slots (not counting expired holders).
+func (sem *Semaphore) busy(res *SemaphoreResource, now time.Time) (busyState, error) {
+	spec := res.TypedSpec()
+
+	var (
+		active  int
+		expires time.Time
+	)
+
+	for _, h := range spec.Holders {
+		if h.Expired(now) {
+			continue
+		}
+
+		active++
+
+		if expires.IsZero() || h.Expires.Before(expires) {
+			expires = h.Expires
+		}
+	}
+
+	if active < int(spec.Capacity) {
+		return busyState{}, nil
+	}
+
+	return busyState{expires: expires, version: res.Metadata().Version()}, errBusy
+}
+
+//nolint:gocyclo,cyclop
+func (sem *Semaphore) tryAcquire(ctx context.Context) (Token, busyState, error) {
+	for {
+		now := time.Now()
+
+		existing, err := safe.StateGet[*SemaphoreResource](ctx, sem.st, sem.pointer())
+		if err != nil && !state.IsNotFoundError(err) {
+			return 0, busyState{}, err
+		}
+
+		// check the state first, so that the tokens are not issued when the semaphore is busy;
+		// the semaphore being torn down is left to the update, which returns the phase conflict
+		if existing != nil && existing.Metadata().Phase() == resource.PhaseRunning {
+			if holder, held := existing.TypedSpec().Holder(sem.holder); held && !holder.Expired(now) {
+				return holder.Token, busyState{}, nil
+			}
+
+			if busy, busyErr := sem.busy(existing, now); busyErr != nil {
+				return 0, busy, busyErr
+			}
+		}
+
+		token, err := sem.issueToken(ctx)
+		if err != nil {
+			return 0, busyState{}, err
+		}
+
+		now = time.Now()
+		holder := SemaphoreHolder{
+			ID:       sem.holder,
+			Token:    token,
+			Acquired: now,
+			Expires:  now.Add(sem.options.LeaseDuration),
+		}
+
+		if existing == nil {
+			res := NewSemaphoreResource(sem.ns, sem.id)
+			res.Metadata().Finalizers().Add(sem.finalizer())
+			*res.TypedSpec() = SemaphoreSpec{
+				Capacity: sem.capacity,
+				Holders:  []SemaphoreHolder{holder},
+			}
+
+			err = sem.st.Create(ctx, res)
+			if err == nil {
+				return token, busyState{}, nil
+			}
+
+			if !state.IsConflictError(err) {
+				return 0, busyState{}, err
+			}
+
+			// created concurrently, start over
+			continue
+		}
+
+		var busy busyState
+
+		_, err = safe.StateUpdateWithConflicts(ctx, sem.st, sem.pointer(), func(res *SemaphoreResource) error {
+			sem.reclaim(res, now)
+
+			var busyErr error
+
+			if busy, busyErr = sem.busy(res, now); busyErr != nil {
+				return busyErr
+			}
+
+			res.TypedSpec().Holders = append(res.TypedSpec().Holders, holder)
+			res.Metadata().Finalizers().Add(sem.finalizer())
+
+			return nil
+		})
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				// destroyed concurrently, start over
+				continue
+			}
+
+			return 0, busy, err
+		}
+
+		return token, busyState{}, nil
+	}
+}
+
+// reclaim removes the holders with expired leases (and the holder itself, as it is about to be re-added).
+func (sem *Semaphore) reclaim(res *SemaphoreResource, now time.Time) {
+	spec := res.TypedSpec()
+
+	spec.Holders = slices.DeleteFunc(spec.Holders, func(h SemaphoreHolder) bool {
+		if h.ID != sem.holder && !h.Expired(now) {
+			return false
+		}
+
+		res.Metadata().Finalizers().Remove(FinalizerPrefix + h.ID)
+
+		return true
+	})
+}
+
+// issueToken increments the counter of the semaphore and returns the new token.
+func (sem *Semaphore) issueToken(ctx context.Context) (Token, error) {
+	for {
+		counter := NewSemaphoreCounterResource(sem.ns, sem.id)
+		counter.Metadata().Finalizers().Add(CounterFinalizer)
+		*counter.TypedSpec() = SemaphoreCounterSpec{
+			LastToken:  1,
+			LastHolder: sem.holder,
+			Issued:     time.Now(),
+		}
+
+		err := sem.st.Create(ctx, counter)
+		if err == nil {
+			return 1, nil
+		}
+
+		if !state.IsConflictError(err) {
+			return 0, err
+		}
+
+		counter, err = safe.StateUpdateWithConflicts(ctx, sem.st, counter.Metadata(), func(res *SemaphoreCounterResource) error {
+			spec := res.TypedSpec()
+
+			spec.LastToken++
+			spec.LastHolder = sem.holder
+			spec.Issued = time.Now()
+
+			// restore the finalizer, so that the counter can't be destroyed
+			res.Metadata().Finalizers().Add(CounterFinalizer)
+
+			return nil
+		}, state.WithExpectedPhaseAny())
+		if err != nil {
+			if state.IsNotFoundError(err) {
+				continue
+			}
+
+			return 0, err
+		}
+
+		return counter.TypedSpec().LastToken, nil
+	}
+}
+
+// Renew extends the lease of the holder.
+//
+// If the semaphore is not held by the holder, or the lease has already expired, an error wrapping ErrNotHeld is returned.
+// Holder with the expired lease should acquire the semaphore again (getting a new token).
+func (sem *Semaphore) Renew(ctx context.Context) error {
+	_, err := safe.StateUpdateWithConflicts(ctx, sem.st, sem.pointer(), func(res *SemaphoreResource) error {
+		spec := res.TypedSpec()
+		now := time.Now()
+
+		idx := slices.IndexFunc(spec.Holders, func(h SemaphoreHolder) bool { return h.ID == sem.holder })
+		if idx == -1 || spec.Holders[idx].Expired(now) {
+			return fmt.Errorf("%w: holder %q", ErrNotHeld, sem.holder)
+		}
+
+		spec.Holders[idx].Expires = now.Add(sem.options.LeaseDuration)
+
+		return nil
+	}, state.WithExpectedPhaseAny())
+	if state.IsNotFoundError(err) {
+		return fmt.Errorf("%w: %w", ErrNotHeld, err)
+	}
+
+	return err
+}
+
+// Release the semaphore.
+//
+// If the semaphore is not held by the holder, an error wrapping ErrNotHeld is returned.
+// Holder with the expired lease which wasn't reclaimed yet is still released.
+func (sem *Semaphore) Release(ctx context.Context) error {
+	_, err := safe.StateUpdateWithConflicts(ctx, sem.st, sem.pointer(), func(res *SemaphoreResource) error {
+		spec := res.TypedSpec()
+
+		idx := slices.IndexFunc(spec.Holders, func(h SemaphoreHolder) bool { return h.ID == sem.holder })
+		if idx == -1 {
+			return fmt.Errorf("%w: holder %q", ErrNotHeld, sem.holder)
+		}
+
+		spec.Holders = slices.Delete(spec.Holders, idx, idx+1)
+
+		res.Metadata().Finalizers().Remove(sem.finalizer())
+
+		return nil
+	}, state.WithExpectedPhaseAny())
+	if state.IsNotFoundError(err) {
+		return fmt.Errorf("%w: %w", ErrNotHeld, err)
+	}
+
+	return err
+}
+
+// Check verifies that the semaphore is still held by the holder with the specified fencing token.
+//
+// If the semaphore is not held, the lease has expired, or the token doesn't match, an error wrapping ErrNotHeld is returned.
+func (sem *Semaphore) Check(ctx context.Context, token Token) error {
+	res, err := safe.StateGet[*SemaphoreResource](ctx, sem.st, sem.pointer())
+	if err != nil {
+		if state.IsNotFoundError(err) {
+			return fmt.Errorf("%w: %w", ErrNotHeld, err)
+		}
+
+		return err
+	}
+
+	holder, held := res.TypedSpec().Holder(sem.holder)
+	if !held || holder.Token != token || holder.Expired(time.Now()) {
+		return fmt.Errorf("%w: holder %q, token %d", ErrNotHeld, sem.holder, token)
+	}
+
+	return nil
+}
+
+// Mutex is a Semaphore with capacity of one.
+type Mutex struct {
+	sem *Semaphore
+}
+
+// NewMutex creates a mutex for the holder.
+func NewMutex(st state.State, ns resource.Namespace, id resource.ID, holder string, opts ...Option) (*Mutex, error) {
+	sem, err := NewSemaphore(st, ns, id, 1, holder, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mutex{
+		sem: sem,
+	}, nil
+}
+
+// Lock the mutex, waiting until it's available or the context is canceled.
+func (m *Mutex) Lock(ctx context.Context) (Token, error) {
+	return m.sem.Acquire(ctx)
+}
+
+// TryLock locks the mutex without waiting.
+func (m *Mutex) TryLock(ctx context.Context) (Token, bool, error) {
+	return m.sem.TryAcquire(ctx)
+}
+
+// Unlock the mutex.
+func (m *Mutex) Unlock(ctx context.Context) error {
+	return m.sem.Release(ctx)
+}
+
+// Renew extends the lease of the holder.
+func (m *Mutex) Renew(ctx context.Context) error {
+	return m.sem.Renew(ctx)
+}
+
+// Check verifies that the mutex is still locked by the holder with the specified fencing token.
+func (m *Mutex) Check(ctx context.Context, token Token) error {
+	return m.sem.Check(ctx, token)
+}
@@ -0,0 +1,278 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lock_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/lock"
+	"github.com/cosi-project/runtime/pkg/safe"
+	"github.com/cosi-project/runtime/pkg/state"
+	"github.com/cosi-project/runtime/pkg/state/impl/inmem"
+	"github.com/cosi-project/runtime/pkg/state/impl/namespaced"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/client"
+	"github.com/cosi-project/runtime/pkg/state/protobuf/server"
+)
+
+func setupStates(t *testing.T) map[string]state.State {
+	t.Helper()
+
+	inmemState := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	l, err := (&net.ListenConfig{}).Listen(t.Context(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	v1alpha1.RegisterStateServer(grpcServer, server.NewState(state.WrapCore(namespaced.NewState(inmem.Build))))
+
+	go func() { assert.NoError(t, grpcServer.Serve(l)) }()
+
+	t.Cleanup(grpcServer.Stop)
+
+	grpcConn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { assert.NoError(t, grpcConn.Close()) })
+
+	return map[string]state.State{
+		"inmem":    inmemState,
+		"protobuf": state.WrapCore(client.NewAdapter(v1alpha1.NewStateClient(grpcConn))),
+	}
+}
+
+func TestMutex(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+	for name, st := range setupStates(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			a, err := lock.NewMutex(st, "default", "mutex", "a")
+			require.NoError(t, err)
+
+			b, err := lock.NewMutex(st, "default", "mutex", "b")
+			require.NoError(t, err)
+
+			tokenA, err := a.Lock(ctx)
+			require.NoError(t, err)
+
+			_, locked, err := b.TryLock(ctx)
+			require.NoError(t, err)
+			assert.False(t, locked)
+
+			require.NoError(t, a.Check(ctx, tokenA))
+			require.ErrorIs(t, b.Check(ctx, tokenA), lock.ErrNotHeld)
+
+			lockedCh := make(chan lock.Token, 1)
+
+			go func() {
+				tokenB, lockErr := b.Lock(ctx)
+				assert.NoError(t, lockErr)
+
+				lockedCh <- tokenB
+			}()
+
+			select {
+			case <-lockedCh:
+				t.Fatal("mutex should be locked")
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			require.NoError(t, a.Unlock(ctx))
+			require.ErrorIs(t, a.Unlock(ctx), lock.ErrNotHeld)
+
+			var tokenB lock.Token
+
+			select {
+			case tokenB = <-lockedCh:
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for the lock")
+			}
+
+			// fencing tokens are increasing
+			assert.Greater(t, tokenB, tokenA)
+			require.ErrorIs(t, a.Check(ctx, tokenA), lock.ErrNotHeld)
+
+			waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer waitCancel()
+
+			_, err = a.Lock(waitCtx)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			require.NoError(t, b.Unlock(ctx))
+
+			// semaphore resource can be destroyed once released
+			require.NoError(t, st.Destroy(ctx, lock.NewSemaphoreResource("default", "mutex").Metadata()))
+		})
+	}
+}
+
+func TestSemaphore(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+	for name, st := range setupStates(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			a, err := lock.NewSemaphore(st, "default", "sem", 2, "a")
+			require.NoError(t, err)
+
+			b, err := lock.NewSemaphore(st, "default", "sem", 2, "b")
+			require.NoError(t, err)
+
+			c, err := lock.NewSemaphore(st, "default", "sem", 2, "c")
+			require.NoError(t, err)
+
+			tokenA, err := a.Acquire(ctx)
+			require.NoError(t, err)
+
+			// re-acquire returns the same token
+			tokenA2, err := a.Acquire(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tokenA, tokenA2)
+
+			_, acquired, err := b.TryAcquire(ctx)
+			require.NoError(t, err)
+			assert.True(t, acquired)
+
+			_, acquired, err = c.TryAcquire(ctx)
+			require.NoError(t, err)
+			assert.False(t, acquired)
+
+			require.NoError(t, a.Release(ctx))
+
+			_, acquired, err = c.TryAcquire(ctx)
+			require.NoError(t, err)
+			assert.True(t, acquired)
+
+			// semaphore can't be destroyed while held
+			ptr := lock.NewSemaphoreResource("default", "sem").Metadata()
+
+			ready, err := st.Teardown(ctx, ptr)
+			require.NoError(t, err)
+			assert.False(t, ready)
+
+			// semaphore being torn down can't be acquired
+			_, _, err = a.TryAcquire(ctx)
+			require.Error(t, err)
+			assert.True(t, state.IsPhaseConflictError(err))
+
+			require.NoError(t, b.Release(ctx))
+			require.NoError(t, c.Release(ctx))
+
+			require.NoError(t, st.Destroy(ctx, ptr))
+		})
+	}
+}
+
+func TestLease(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+	for name, st := range setupStates(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			a, err := lock.NewMutex(st, "default", "lease", "a", lock.WithLeaseDuration(500*time.Millisecond))
+			require.NoError(t, err)
+
+			b, err := lock.NewMutex(st, "default", "lease", "b", lock.WithLeaseDuration(time.Minute))
+			require.NoError(t, err)
+
+			tokenA, err := a.Lock(ctx)
+			require.NoError(t, err)
+
+			// renewed lease keeps the mutex locked
+			time.Sleep(300 * time.Millisecond)
+			require.NoError(t, a.Renew(ctx))
+			time.Sleep(300 * time.Millisecond)
+
+			require.NoError(t, a.Check(ctx, tokenA))
+
+			_, locked, err := b.TryLock(ctx)
+			require.NoError(t, err)
+			assert.False(t, locked)
+
+			// a stops renewing the lease, b reclaims the mutex once the lease expires
+			tokenB, err := b.Lock(ctx)
+			require.NoError(t, err)
+			assert.Greater(t, tokenB, tokenA)
+
+			require.ErrorIs(t, a.Check(ctx, tokenA), lock.ErrNotHeld)
+			require.ErrorIs(t, a.Renew(ctx), lock.ErrNotHeld)
+
+			res, err := safe.StateGet[*lock.SemaphoreResource](ctx, st, lock.NewSemaphoreResource("default", "lease").Metadata())
+			require.NoError(t, err)
+
+			assert.False(t, res.Metadata().Finalizers().Has(lock.FinalizerPrefix+"a"))
+			assert.True(t, res.Metadata().Finalizers().Has(lock.FinalizerPrefix+"b"))
+
+			holder, held := res.TypedSpec().Holder("b")
+			require.True(t, held)
+			assert.Equal(t, tokenB, holder.Token)
+
+			require.NoError(t, b.Unlock(ctx))
+		})
+	}
+}
+
+func TestTokensSurviveDestroy(t *testing.T) {
+	t.Cleanup(func() { goleak.VerifyNone(t, goleak.IgnoreCurrent()) })
+
+	for name, st := range setupStates(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			t.Cleanup(cancel)
+
+			a, err := lock.NewMutex(st, "default", "counter", "a")
+			require.NoError(t, err)
+
+			tokenA, err := a.Lock(ctx)
+			require.NoError(t, err)
+			require.NoError(t, a.Unlock(ctx))
+
+			require.NoError(t, st.Destroy(ctx, lock.NewSemaphoreResource("default", "counter").Metadata()))
+
+			tokenA2, err := a.Lock(ctx)
+			require.NoError(t, err)
+			assert.Greater(t, tokenA2, tokenA)
+
+			counter, err := safe.StateGet[*lock.SemaphoreCounterResource](ctx, st, lock.NewSemaphoreCounterResource("default", "counter").Metadata())
+			require.NoError(t, err)
+
+			assert.Equal(t, tokenA2, counter.TypedSpec().LastToken)
+			assert.Equal(t, "a", counter.TypedSpec().LastHolder)
+
+			// counter can't be destroyed
+			err = st.Destroy(ctx, counter.Metadata())
+			require.Error(t, err)
+			assert.True(t, state.IsConflictError(err))
+
+			require.NoError(t, a.Unlock(ctx))
+		})
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	_, err := lock.NewSemaphore(st, "default", "sem", 0, "a")
+	require.EqualError(t, err, "semaphore capacity should be at least 1, got 0")
+
+	_, err = lock.NewMutex(st, "default", "mutex", "a", lock.WithLeaseDuration(0))
+	require.EqualError(t, err, "lease duration should be positive, got 0s")
+}
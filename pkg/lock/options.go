@@ -0,0 +1,35 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lock
+
+import "time"
+
+// DefaultLeaseDuration is the default duration of the holder lease.
+const DefaultLeaseDuration = 30 * time.Second
+
+// Options configure Semaphore and Mutex.
+type Options struct {
+	LeaseDuration time.Duration
+}
+
+// Option is a functional option for Semaphore and Mutex.
+type Option func(*Options)
+
+// WithLeaseDuration sets the duration of the holder lease.
+//
+// The holder should renew the lease before it expires, otherwise the slot is reclaimed
+// by the next holder which acquires the semaphore.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(o *Options) {
+		o.LeaseDuration = d
+	}
+}
+
+// DefaultOptions returns the default options.
+func DefaultOptions() Options {
+	return Options{
+		LeaseDuration: DefaultLeaseDuration,
+	}
+}
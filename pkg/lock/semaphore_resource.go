@@ -0,0 +1,134 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lock
+
+import (
+	"slices"
+	"time"
+
+	"github.com/siderolabs/gen/ensure"
+	"github.com/siderolabs/gen/xslices"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	lockpb "github.com/cosi-project/runtime/api/lock"
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/protobuf"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+// SemaphoreType is the type of SemaphoreResource.
+const SemaphoreType = resource.Type("Semaphores.meta.cosi.dev")
+
+// SemaphoreResource keeps the state of a semaphore.
+type SemaphoreResource = typed.Resource[SemaphoreSpec, SemaphoreExtension]
+
+// NewSemaphoreResource initializes a SemaphoreResource.
+func NewSemaphoreResource(ns resource.Namespace, id resource.ID) *SemaphoreResource {
+	return typed.NewResource[SemaphoreSpec, SemaphoreExtension](
+		resource.NewMetadata(ns, SemaphoreType, id, resource.VersionUndefined),
+		SemaphoreSpec{},
+	)
+}
+
+// SemaphoreExtension provides auxiliary methods for SemaphoreResource.
+type SemaphoreExtension struct{}
+
+// ResourceDefinition implements core.ResourceDefinitionProvider interface.
+func (SemaphoreExtension) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             SemaphoreType,
+		DefaultNamespace: meta.NamespaceName,
+		PrintColumns: []meta.PrintColumn{
+			{
+				Name:     "Capacity",
+				JSONPath: "{.capacity}",
+			},
+		},
+	}
+}
+
+// SemaphoreHolder is a single holder of the semaphore.
+type SemaphoreHolder struct {
+	Acquired time.Time `yaml:"acquired"`
+	Expires  time.Time `yaml:"expires"`
+	ID       string    `yaml:"id"`
+	Token    Token     `yaml:"token"`
+}
+
+// Expired returns true if the lease of the holder wasn't renewed in time.
+func (h SemaphoreHolder) Expired(now time.Time) bool {
+	return !now.Before(h.Expires)
+}
+
+// SemaphoreSpec provides SemaphoreResource definition.
+//
+//nolint:recvcheck
+type SemaphoreSpec struct {
+	Holders  []SemaphoreHolder `yaml:"holders"`
+	Capacity uint32            `yaml:"capacity"`
+}
+
+// Holder returns the holder by ID.
+func (spec SemaphoreSpec) Holder(id string) (SemaphoreHolder, bool) {
+	idx := slices.IndexFunc(spec.Holders, func(h SemaphoreHolder) bool { return h.ID == id })
+	if idx == -1 {
+		return SemaphoreHolder{}, false
+	}
+
+	return spec.Holders[idx], true
+}
+
+// DeepCopy generates a deep copy of SemaphoreSpec.
+func (spec SemaphoreSpec) DeepCopy() SemaphoreSpec {
+	spec.Holders = slices.Clone(spec.Holders)
+
+	return spec
+}
+
+// MarshalProto implements ProtoMarshaler.
+func (spec SemaphoreSpec) MarshalProto() ([]byte, error) {
+	protoSpec := lockpb.SemaphoreSpec{
+		Capacity: spec.Capacity,
+		Holders: xslices.Map(spec.Holders, func(h SemaphoreHolder) *lockpb.SemaphoreSpec_Holder {
+			return &lockpb.SemaphoreSpec_Holder{
+				Id:       h.ID,
+				Token:    uint64(h.Token),
+				Acquired: timestamppb.New(h.Acquired),
+				Expires:  timestamppb.New(h.Expires),
+			}
+		}),
+	}
+
+	return protobuf.ProtoMarshal(&protoSpec)
+}
+
+// UnmarshalProto implements protobuf.ResourceUnmarshaler.
+func (spec *SemaphoreSpec) UnmarshalProto(protoBytes []byte) error {
+	protoSpec := lockpb.SemaphoreSpec{}
+
+	if err := protobuf.ProtoUnmarshal(protoBytes, &protoSpec); err != nil {
+		return err
+	}
+
+	*spec = SemaphoreSpec{
+		Capacity: protoSpec.Capacity,
+	}
+
+	for _, h := range protoSpec.Holders {
+		spec.Holders = append(spec.Holders, SemaphoreHolder{
+			ID:       h.Id,
+			Token:    Token(h.Token),
+			Acquired: h.Acquired.AsTime(),
+			Expires:  h.Expires.AsTime(),
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	ensure.NoError(protobuf.RegisterResource(SemaphoreType, &SemaphoreResource{}))
+}
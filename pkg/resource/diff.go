@@ -0,0 +1,164 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// DiffOp is a kind of change described by DiffEntry.
+type DiffOp int
+
+// DiffOp constants.
+const (
+	DiffAdded DiffOp = iota
+	DiffRemoved
+	DiffChanged
+)
+
+func (op DiffOp) String() string {
+	switch op {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return fmt.Sprintf("DiffOp(%d)", op)
+	}
+}
+
+// DiffEntry describes a single changed field between two resources.
+//
+// Path is rooted at either `metadata` or `spec`, e.g. `metadata.labels.app` or `spec.items[1]`.
+// Old and New hold values as decoded from the YAML representation of the resource,
+// Old is nil for DiffAdded, New is nil for DiffRemoved.
+type DiffEntry struct {
+	Old  any
+	New  any
+	Path string
+	Op   DiffOp
+}
+
+// String implements fmt.Stringer.
+func (entry DiffEntry) String() string {
+	switch entry.Op {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %v", entry.Path, entry.New)
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %v", entry.Path, entry.Old)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", entry.Path, entry.Old, entry.New)
+	}
+}
+
+// Diff computes a structured difference between two resources.
+//
+// Resources are compared via their YAML representation (see MarshalYAML), so the diff
+// covers both metadata and spec. Metadata changes come first, followed by spec changes.
+func Diff(oldRes, newRes Resource) ([]DiffEntry, error) {
+	oldTree, err := yamlTree(oldRes)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling old resource: %w", err)
+	}
+
+	newTree, err := yamlTree(newRes)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling new resource: %w", err)
+	}
+
+	var entries []DiffEntry
+
+	for _, key := range []string{"metadata", "spec"} {
+		diffValues(&entries, key, oldTree[key], newTree[key])
+	}
+
+	return entries, nil
+}
+
+func yamlTree(r Resource) (map[string]any, error) {
+	v, err := MarshalYAML(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]any
+
+	if err = yaml.Unmarshal(out, &tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+func diffValues(entries *[]DiffEntry, path string, oldValue, newValue any) {
+	switch oldTyped := oldValue.(type) {
+	case map[string]any:
+		if newTyped, ok := newValue.(map[string]any); ok {
+			keys := slices.Collect(maps.Keys(oldTyped))
+
+			for key := range newTyped {
+				if _, exists := oldTyped[key]; !exists {
+					keys = append(keys, key)
+				}
+			}
+
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				diffChild(entries, path+"."+key, oldTyped, newTyped, key)
+			}
+
+			return
+		}
+	case []any:
+		if newTyped, ok := newValue.([]any); ok {
+			for i := range max(len(oldTyped), len(newTyped)) {
+				childPath := path + "[" + strconv.Itoa(i) + "]"
+
+				switch {
+				case i >= len(newTyped):
+					*entries = append(*entries, DiffEntry{Path: childPath, Op: DiffRemoved, Old: oldTyped[i]})
+				case i >= len(oldTyped):
+					*entries = append(*entries, DiffEntry{Path: childPath, Op: DiffAdded, New: newTyped[i]})
+				default:
+					diffValues(entries, childPath, oldTyped[i], newTyped[i])
+				}
+			}
+
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*entries = append(*entries, DiffEntry{Path: path, Op: DiffChanged, Old: oldValue, New: newValue})
+	}
+}
+
+func diffChild(entries *[]DiffEntry, path string, oldMap, newMap map[string]any, key string) {
+	oldValue, oldExists := oldMap[key]
+	newValue, newExists := newMap[key]
+
+	switch {
+	case !newExists:
+		*entries = append(*entries, DiffEntry{Path: path, Op: DiffRemoved, Old: oldValue})
+	case !oldExists:
+		*entries = append(*entries, DiffEntry{Path: path, Op: DiffAdded, New: newValue})
+	default:
+		diffValues(entries, path, oldValue, newValue)
+	}
+}
@@ -0,0 +1,65 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+type yamlSpec string
+
+func (s yamlSpec) GetYaml() []byte {
+	return []byte(s)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	oldRes, err := resource.NewAnyFromProto(&protoMd{}, &protoSpec{})
+	require.NoError(t, err)
+
+	newRes, err := resource.NewAnyFromProto(&protoMd{}, yamlSpec(`value: abc
+something: [a, b]
+extra: 1
+`))
+	require.NoError(t, err)
+
+	newRes.Metadata().SetVersion(newRes.Metadata().Version().Next())
+	newRes.Metadata().Labels().Set("stage", "final")
+	newRes.Metadata().Labels().Delete("app")
+
+	entries, err := resource.Diff(oldRes, newRes)
+	require.NoError(t, err)
+
+	assert.Equal(t, []resource.DiffEntry{
+		{Path: "metadata.labels.app", Op: resource.DiffRemoved, Old: "foo"},
+		{Path: "metadata.labels.stage", Op: resource.DiffChanged, Old: "initial", New: "final"},
+		{Path: "metadata.version", Op: resource.DiffChanged, Old: 1, New: 2},
+		{Path: "spec.extra", Op: resource.DiffAdded, New: 1},
+		{Path: "spec.something[2]", Op: resource.DiffRemoved, Old: "c"},
+		{Path: "spec.value", Op: resource.DiffChanged, Old: "xyz", New: "abc"},
+	}, entries)
+
+	assert.Equal(t, "~ spec.value: xyz -> abc", entries[5].String())
+
+	entries, err = resource.Diff(oldRes, oldRes.DeepCopy())
+	require.NoError(t, err)
+
+	assert.Empty(t, entries)
+}
+
+func TestDiffOpString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "added", resource.DiffAdded.String())
+	assert.Equal(t, "removed", resource.DiffRemoved.String())
+	assert.Equal(t, "changed", resource.DiffChanged.String())
+	assert.Equal(t, "DiffOp(42)", resource.DiffOp(42).String())
+}
@@ -0,0 +1,195 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package filter implements filter expressions over the resource metadata and spec.
+package filter
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/cel-go/cel"
+	"go.yaml.in/yaml/v4"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+const (
+	varMetadata = "metadata"
+	varSpec     = "spec"
+)
+
+const (
+	// MaxExpressionLength is the maximum length of the filter expression (in code points).
+	MaxExpressionLength = 4096
+
+	// MaxEvaluationCost is the maximum cost of the filter expression evaluation for a single resource.
+	//
+	// The cost is tracked while the expression is evaluated (roughly, each operation and each element
+	// of the traversed lists and maps add to the cost), the evaluation is aborted once the limit is exceeded.
+	MaxEvaluationCost = 100_000
+)
+
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(varMetadata, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(varSpec, cel.DynType),
+		cel.ParserExpressionSizeLimit(MaxExpressionLength),
+	)
+})
+
+var _ resource.FilterExpression = (*Expression)(nil)
+
+// Expression is a compiled filter expression.
+//
+// Expressions are written in CEL (https://cel.dev), and they should evaluate to a boolean.
+// The resource is available to the expression as two variables:
+//   - metadata is a map with the keys namespace, type, id, version, owner, phase (strings), created, updated (timestamps),
+//     labels, annotations (maps of strings) and finalizers (list of strings);
+//   - spec is the YAML representation of the resource spec (null if the spec is not available).
+//
+// Example:
+//
+//	metadata.labels["role"] == "worker" || (metadata.id.startsWith("cp-") && spec.replicas > 3)
+//
+// Expression doesn't match the resource if the evaluation fails (e.g. a missing map key is accessed),
+// so use `has(spec.field)` or `"key" in metadata.labels` to check for the optional fields.
+// The evaluation also fails if it exceeds MaxEvaluationCost.
+type Expression struct {
+	program  cel.Program
+	source   string
+	usesSpec bool
+}
+
+// Compile parses and checks the filter expression.
+//
+// Expressions longer than MaxExpressionLength are rejected.
+func Compile(source string) (*Expression, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing filter environment: %w", err)
+	}
+
+	if length := utf8.RuneCountInString(source); length > MaxExpressionLength {
+		return nil, fmt.Errorf("filter expression is too long: %d > %d", length, MaxExpressionLength)
+	}
+
+	ast, issues := env.Compile(source)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("error compiling filter expression %q: %w", source, issues.Err())
+	}
+
+	if outputType := ast.OutputType(); !outputType.IsExactType(cel.BoolType) && !outputType.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("filter expression %q should evaluate to bool, got %s", source, outputType)
+	}
+
+	program, err := env.Program(ast, cel.CostLimit(MaxEvaluationCost))
+	if err != nil {
+		return nil, fmt.Errorf("error building filter expression %q: %w", source, err)
+	}
+
+	expr := &Expression{
+		program: program,
+		source:  source,
+	}
+
+	for _, ref := range ast.NativeRep().ReferenceMap() {
+		if ref.Name == varSpec {
+			expr.usesSpec = true
+		}
+	}
+
+	return expr, nil
+}
+
+// MustCompile is like Compile, but panics on error.
+func MustCompile(source string) *Expression {
+	expr, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+
+	return expr
+}
+
+// String returns the source of the expression.
+func (expr *Expression) String() string {
+	if expr == nil {
+		return ""
+	}
+
+	return expr.source
+}
+
+// UsesSpec returns true if the expression refers to the resource spec.
+func (expr *Expression) UsesSpec() bool {
+	return expr != nil && expr.usesSpec
+}
+
+// Matches if the resource matches the expression.
+//
+// Nil expression matches any resource.
+func (expr *Expression) Matches(r resource.Resource) bool {
+	if expr == nil {
+		return true
+	}
+
+	vars := map[string]any{
+		varMetadata: metadataVars(r.Metadata()),
+	}
+
+	if expr.usesSpec {
+		// spec is decoded lazily, as it might not be evaluated at all
+		vars[varSpec] = func() any {
+			return specVars(r)
+		}
+	}
+
+	out, _, err := expr.program.Eval(vars)
+	if err != nil {
+		return false
+	}
+
+	matches, ok := out.Value().(bool)
+
+	return ok && matches
+}
+
+func metadataVars(md *resource.Metadata) map[string]any {
+	finalizers := md.Finalizers()
+
+	return map[string]any{
+		"namespace":   md.Namespace(),
+		"type":        md.Type(),
+		"id":          md.ID(),
+		"version":     md.Version().String(),
+		"owner":       md.Owner(),
+		"phase":       md.Phase().String(),
+		"created":     md.Created(),
+		"updated":     md.Updated(),
+		"labels":      md.Labels().Raw(),
+		"annotations": md.Annotations().Raw(),
+		"finalizers":  []string(*finalizers),
+	}
+}
+
+func specVars(r resource.Resource) any {
+	if r.Spec() == nil {
+		return nil
+	}
+
+	out, err := yaml.Marshal(r.Spec())
+	if err != nil {
+		return nil
+	}
+
+	var spec any
+
+	if err = yaml.Unmarshal(out, &spec); err != nil {
+		return nil
+	}
+
+	return spec
+}
@@ -0,0 +1,123 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package filter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/filter"
+	"github.com/cosi-project/runtime/pkg/resource/meta"
+	"github.com/cosi-project/runtime/pkg/resource/typed"
+)
+
+type testSpec struct {
+	Nodes struct {
+		Role string `yaml:"role"`
+	} `yaml:"nodes"`
+	Replicas int `yaml:"replicas"`
+}
+
+func (spec testSpec) DeepCopy() testSpec { return spec }
+
+type testExtension struct{}
+
+func (testExtension) ResourceDefinition() meta.ResourceDefinitionSpec {
+	return meta.ResourceDefinitionSpec{
+		Type:             "Test.test.cosi.dev",
+		DefaultNamespace: "default",
+	}
+}
+
+type testResource = typed.Resource[testSpec, testExtension]
+
+func newTestResource(id resource.ID, replicas int) *testResource {
+	spec := testSpec{Replicas: replicas}
+	spec.Nodes.Role = "worker"
+
+	r := typed.NewResource[testSpec, testExtension](resource.NewMetadata("default", "Test.test.cosi.dev", id, resource.VersionUndefined), spec)
+	r.Metadata().Labels().Set("app", "web")
+	r.Metadata().Finalizers().Add("cleanup")
+
+	return r
+}
+
+func TestExpression(t *testing.T) {
+	t.Parallel()
+
+	r := newTestResource("web-1", 3)
+
+	for _, test := range []struct {
+		expr     string
+		usesSpec bool
+		matches  bool
+	}{
+		{expr: `metadata.id == "web-1"`, matches: true},
+		{expr: `metadata.id.startsWith("db-") || metadata.labels["app"] == "web"`, matches: true},
+		{expr: `metadata.namespace == "default" && metadata.type == "Test.test.cosi.dev"`, matches: true},
+		{expr: `metadata.phase == "running" && "cleanup" in metadata.finalizers`, matches: true},
+		{expr: `metadata.labels["app"] in ["db", "cache"]`, matches: false},
+		{expr: `metadata.labels["missing"] == "value"`, matches: false},
+		{expr: `"missing" in metadata.labels || metadata.version == "undefined"`, matches: true},
+		{expr: `spec.replicas > 2 && spec.nodes.role == "worker"`, usesSpec: true, matches: true},
+		{expr: `spec.replicas > 3`, usesSpec: true, matches: false},
+		{expr: `has(spec.nodes.zone) && spec.nodes.zone == "a"`, usesSpec: true, matches: false},
+		{expr: `spec.replicas`, usesSpec: true, matches: false},
+	} {
+		t.Run(test.expr, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := filter.Compile(test.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expr, expr.String())
+			assert.Equal(t, test.usesSpec, expr.UsesSpec())
+			assert.Equal(t, test.matches, expr.Matches(r))
+		})
+	}
+}
+
+func TestExpressionNil(t *testing.T) {
+	t.Parallel()
+
+	var expr *filter.Expression
+
+	assert.True(t, expr.Matches(newTestResource("web-1", 3)))
+	assert.False(t, expr.UsesSpec())
+	assert.Empty(t, expr.String())
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{
+		`metadata.id ==`,
+		`status.ready`,
+		`"string"`,
+		`1 + 2`,
+	} {
+		_, err := filter.Compile(source)
+		assert.Error(t, err, source)
+	}
+}
+
+func TestExpressionLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := filter.Compile(`metadata.id == "` + strings.Repeat("a", filter.MaxExpressionLength) + `"`)
+	assert.ErrorContains(t, err, "too long")
+
+	list := "[" + strings.Repeat("0, ", 99) + "0]"
+
+	// the expression matches, but the evaluation exceeds the cost limit
+	expr, err := filter.Compile(list + ".all(a, " + list + ".all(b, " + list + ".all(c, a + b + c == 0)))")
+	require.NoError(t, err)
+
+	assert.False(t, expr.Matches(newTestResource("web-1", 3)))
+}
@@ -0,0 +1,30 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+// FilterExpression is a filter expression over the resource metadata and spec.
+//
+// The expression language is implemented by the package filter, so that the packages which only
+// apply the expressions (e.g. state implementations) don't depend on it.
+type FilterExpression interface {
+	// Matches returns true if the resource matches the expression.
+	Matches(r Resource) bool
+	// UsesSpec returns true if the expression refers to the resource spec.
+	UsesSpec() bool
+	// String returns the source of the expression.
+	String() string
+}
+
+// MatchesFilter returns true if the resource matches the filter expression.
+//
+// Nil expression matches any resource.
+func MatchesFilter(expr FilterExpression, r Resource) bool {
+	return expr == nil || expr.Matches(r)
+}
+
+// FilterUsesSpec returns true if the filter expression refers to the resource spec.
+func FilterUsesSpec(expr FilterExpression) bool {
+	return expr != nil && expr.UsesSpec()
+}
@@ -0,0 +1,157 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compare
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version.
+type semver struct {
+	prerelease []string
+	core       [3]uint64
+}
+
+// parseSemver parses semantic version, the leading "v" is optional, missing minor and patch versions are zero.
+//
+// Build metadata (after "+") is ignored.
+func parseSemver(value string) (semver, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	value, _, _ = strings.Cut(value, "+")
+
+	var (
+		version    semver
+		prerelease string
+		hasPre     bool
+	)
+
+	value, prerelease, hasPre = strings.Cut(value, "-")
+
+	parts := strings.Split(value, ".")
+	if len(parts) > len(version.core) {
+		return semver{}, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return semver{}, false
+		}
+
+		version.core[i] = n
+	}
+
+	if hasPre {
+		version.prerelease = strings.Split(prerelease, ".")
+
+		for _, ident := range version.prerelease {
+			if ident == "" {
+				return semver{}, false
+			}
+		}
+	}
+
+	return version, true
+}
+
+func (v semver) compare(other semver) int {
+	for i := range v.core {
+		if c := cmp.Compare(v.core[i], other.core[i]); c != 0 {
+			return c
+		}
+	}
+
+	// version without the pre-release has higher precedence
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := range min(len(v.prerelease), len(other.prerelease)) {
+		if c := comparePrerelease(v.prerelease[i], other.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(v.prerelease), len(other.prerelease))
+}
+
+// comparePrerelease compares pre-release identifiers: numeric identifiers are compared numerically
+// and have lower precedence than the alphanumeric ones.
+func comparePrerelease(left, right string) int {
+	numLeft, errLeft := strconv.ParseUint(left, 10, 64)
+	numRight, errRight := strconv.ParseUint(right, 10, 64)
+
+	switch {
+	case errLeft == nil && errRight == nil:
+		return cmp.Compare(numLeft, numRight)
+	case errLeft == nil:
+		return -1
+	case errRight == nil:
+		return 1
+	default:
+		return strings.Compare(left, right)
+	}
+}
+
+// semverOperators are the operators of the constraint, longest first.
+var semverOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// MatchSemver checks if the version matches the constraint, e.g. ">=1.2.0" (the operator defaults to "=").
+//
+// If the version or the constraint can't be parsed, returns false as the 2nd arg.
+func MatchSemver(version, constraint string) (bool, bool) {
+	op, constraintVersion, ok := parseSemverConstraint(constraint)
+	if !ok {
+		return false, false
+	}
+
+	parsed, ok := parseSemver(version)
+	if !ok {
+		return false, false
+	}
+
+	c := parsed.compare(constraintVersion)
+
+	switch op {
+	case ">=":
+		return c >= 0, true
+	case "<=":
+		return c <= 0, true
+	case "!=":
+		return c != 0, true
+	case ">":
+		return c > 0, true
+	case "<":
+		return c < 0, true
+	default:
+		return c == 0, true
+	}
+}
+
+func parseSemverConstraint(constraint string) (string, semver, bool) {
+	constraint = strings.TrimSpace(constraint)
+
+	op := "="
+
+	for _, candidate := range semverOperators {
+		if strings.HasPrefix(constraint, candidate) {
+			op = candidate
+			constraint = constraint[len(candidate):]
+
+			break
+		}
+	}
+
+	version, ok := parseSemver(constraint)
+
+	return op, version, ok
+}
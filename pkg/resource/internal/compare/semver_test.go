@@ -0,0 +1,49 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cosi-project/runtime/pkg/resource/internal/compare"
+)
+
+func TestMatchSemver(t *testing.T) {
+	for _, tt := range []struct {
+		version    string
+		constraint string
+		matches    bool
+		ok         bool
+	}{
+		{version: "1.2.3", constraint: "1.2.3", matches: true, ok: true},
+		{version: "v1.2.3", constraint: "=1.2.3", matches: true, ok: true},
+		{version: "1.2", constraint: "1.2.0", matches: true, ok: true},
+		{version: "1.2.3+build.5", constraint: "1.2.3", matches: true, ok: true},
+		{version: "1.2.3", constraint: ">=1.2.3", matches: true, ok: true},
+		{version: "1.2.3", constraint: ">1.2.3", ok: true},
+		{version: "1.10.0", constraint: ">1.9.0", matches: true, ok: true},
+		{version: "1.2.3", constraint: "<=1.2.3", matches: true, ok: true},
+		{version: "1.2.3", constraint: "<v2", matches: true, ok: true},
+		{version: "1.2.3", constraint: "!=1.2.3", ok: true},
+		{version: "1.0.0-alpha", constraint: "<1.0.0", matches: true, ok: true},
+		{version: "1.0.0-alpha.1", constraint: ">1.0.0-alpha", matches: true, ok: true},
+		{version: "1.0.0-alpha.beta", constraint: ">1.0.0-alpha.1", matches: true, ok: true},
+		{version: "1.0.0-rc.11", constraint: ">1.0.0-rc.2", matches: true, ok: true},
+		{version: "latest", constraint: ">=1.0.0"},
+		{version: "1.2.3.4", constraint: ">=1.0.0"},
+		{version: "1.0.0-", constraint: ">=1.0.0"},
+		{version: "1.2.3", constraint: ">=x"},
+		{version: "1.2.3", constraint: ""},
+	} {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			matches, ok := compare.MatchSemver(tt.version, tt.constraint)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
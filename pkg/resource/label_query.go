@@ -5,6 +5,7 @@
 package resource
 
 import (
+	"regexp"
 	"slices"
 )
 
@@ -27,6 +28,20 @@ const (
 	LabelOpLTNumeric
 	// LabelOpLTENumeric is the operation for checking if a label value is less than or equal to the specified numeric value.
 	LabelOpLTENumeric
+	// LabelOpGT is the operation for checking if a label value is greater than the specified value.
+	LabelOpGT
+	// LabelOpGTE is the operation for checking if a label value is greater than or equal to the specified value.
+	LabelOpGTE
+	// LabelOpGTNumeric is the operation for checking if a label value is greater than the specified numeric value.
+	LabelOpGTNumeric
+	// LabelOpGTENumeric is the operation for checking if a label value is greater than or equal to the specified numeric value.
+	LabelOpGTENumeric
+	// LabelOpSemver is the operation for checking if a label value is a semantic version matching all the constraints.
+	LabelOpSemver
+	// LabelOpPrefix is the operation for checking if a label value starts with the specified prefix.
+	LabelOpPrefix
+	// LabelOpRegexp is the operation for checking if a label value matches the specified regular expression.
+	LabelOpRegexp
 )
 
 func (l LabelOp) isComparison() bool {
@@ -36,6 +51,11 @@ func (l LabelOp) isComparison() bool {
 	case LabelOpLTE:
 	case LabelOpLTNumeric:
 	case LabelOpLTENumeric:
+	case LabelOpGT:
+	case LabelOpGTE:
+	case LabelOpGTNumeric:
+	case LabelOpGTENumeric:
+	case LabelOpSemver:
 	default:
 		return false
 	}
@@ -45,6 +65,8 @@ func (l LabelOp) isComparison() bool {
 
 // LabelTerm describes a filter on metadata labels.
 type LabelTerm struct {
+	// Regexp is the compiled regular expression of the LabelOpRegexp term (Value holds its source).
+	Regexp *regexp.Regexp
 	Key    string
 	Value  []string
 	Op     LabelOp
@@ -187,8 +209,107 @@ func LabelLTENumeric(label, value string, opts ...TermOption) LabelQueryOption {
 	}
 }
 
+// LabelGT checks that the label value is greater than value, peforms string comparison.
+func LabelGT(label, value string, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  []string{value},
+			Op:     LabelOpGT,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
+// LabelGTE checks that the label value is greater or equal to value, peforms string comparison.
+func LabelGTE(label, value string, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  []string{value},
+			Op:     LabelOpGTE,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
+// LabelGTNumeric checks that the label value is greater than value, peforms numeric comparison, if possible.
+func LabelGTNumeric(label, value string, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  []string{value},
+			Op:     LabelOpGTNumeric,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
+// LabelGTENumeric checks that the label value is greater or equal to value, peforms numeric comparison, if possible.
+func LabelGTENumeric(label, value string, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  []string{value},
+			Op:     LabelOpGTENumeric,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
+// LabelSemver checks that the label value is a semantic version matching all constraints.
+//
+// Each constraint is a version prefixed with an operator (=, !=, <, <=, >, >=; defaults to =),
+// so a range is expressed as two constraints, e.g. ">=1.2.0", "<2.0.0".
+// The leading "v" of the versions is optional, missing minor and patch versions are zero.
+func LabelSemver(label string, constraints []string, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  constraints,
+			Op:     LabelOpSemver,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
+// LabelPrefix checks that the label value starts with the prefix.
+func LabelPrefix(label, prefix string, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  []string{prefix},
+			Op:     LabelOpPrefix,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
+// LabelRegexp checks that the label value matches the regular expression.
+func LabelRegexp(label string, re *regexp.Regexp, opts ...TermOption) LabelQueryOption {
+	return func(q *LabelQuery) {
+		q.Terms = append(q.Terms, LabelTerm{
+			Key:    label,
+			Value:  []string{re.String()},
+			Regexp: re,
+			Op:     LabelOpRegexp,
+			Invert: getInvert(opts),
+		})
+	}
+}
+
 // RawLabelQuery sets the label query to the verbatim value.
+//
+// LabelOpRegexp terms without Regexp are compiled from their Value once, a term with an invalid expression never matches.
 func RawLabelQuery(query LabelQuery) LabelQueryOption {
+	query.Terms = slices.Clone(query.Terms)
+
+	for i, term := range query.Terms {
+		if term.Op == LabelOpRegexp && term.Regexp == nil && len(term.Value) > 0 {
+			query.Terms[i].Regexp, _ = regexp.Compile(term.Value[0])
+		}
+	}
+
 	return func(q *LabelQuery) {
 		*q = query
 	}
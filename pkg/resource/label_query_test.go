@@ -5,6 +5,7 @@
 package resource_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -123,6 +124,86 @@ func TestLabelTerm(t *testing.T) {
 	))
 }
 
+func TestLabelTermOperators(t *testing.T) {
+	var labels resource.Labels
+
+	labels.Set("app", "app2")
+	labels.Set("mem", "5GiB")
+	labels.Set("version", "v1.5.2")
+	labels.Set("pre", "1.6.0-alpha.1")
+	labels.Set("bad", "latest")
+
+	for _, tt := range []struct {
+		name    string
+		term    resource.LabelTerm
+		matches bool
+	}{
+		{name: "gt", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpGT, Value: []string{"app1"}}, matches: true},
+		{name: "gt equal", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpGT, Value: []string{"app2"}}},
+		{name: "gte equal", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpGTE, Value: []string{"app2"}}, matches: true},
+		{name: "gt missing inverted", term: resource.LabelTerm{Key: "missing", Op: resource.LabelOpGT, Value: []string{"a"}, Invert: true}},
+		{name: "gt numeric", term: resource.LabelTerm{Key: "mem", Op: resource.LabelOpGTNumeric, Value: []string{"5368709119"}}, matches: true},
+		{name: "gt numeric equal", term: resource.LabelTerm{Key: "mem", Op: resource.LabelOpGTNumeric, Value: []string{"5368709120"}}},
+		{name: "gte numeric equal", term: resource.LabelTerm{Key: "mem", Op: resource.LabelOpGTENumeric, Value: []string{"5Gi"}}, matches: true},
+		{name: "gt numeric not a number", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpGTNumeric, Value: []string{"1"}, Invert: true}},
+		{name: "semver range", term: resource.LabelTerm{Key: "version", Op: resource.LabelOpSemver, Value: []string{">=1.5.0", "<1.6"}}, matches: true},
+		{name: "semver out of range", term: resource.LabelTerm{Key: "version", Op: resource.LabelOpSemver, Value: []string{">=1.5.0", "<1.5.2"}}},
+		{name: "semver exact", term: resource.LabelTerm{Key: "version", Op: resource.LabelOpSemver, Value: []string{"1.5.2"}}, matches: true},
+		{name: "semver prerelease", term: resource.LabelTerm{Key: "pre", Op: resource.LabelOpSemver, Value: []string{"<1.6.0"}}, matches: true},
+		{name: "semver invalid value", term: resource.LabelTerm{Key: "bad", Op: resource.LabelOpSemver, Value: []string{">=1.0.0"}, Invert: true}},
+		{name: "semver invalid constraint", term: resource.LabelTerm{Key: "version", Op: resource.LabelOpSemver, Value: []string{">=x"}, Invert: true}},
+		{name: "semver missing", term: resource.LabelTerm{Key: "missing", Op: resource.LabelOpSemver, Value: []string{">=1.0.0"}, Invert: true}},
+		{name: "prefix", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpPrefix, Value: []string{"app"}}, matches: true},
+		{name: "prefix mismatch", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpPrefix, Value: []string{"web"}}},
+		{name: "prefix missing inverted", term: resource.LabelTerm{Key: "missing", Op: resource.LabelOpPrefix, Value: []string{"app"}, Invert: true}, matches: true},
+		{name: "regexp", term: regexpTerm("app", "^app[0-9]$"), matches: true},
+		{name: "regexp mismatch", term: regexpTerm("app", "^web")},
+		{name: "regexp missing inverted", term: regexpTerm("missing", "^app", resource.NotMatches), matches: true},
+		{name: "regexp not compiled", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpRegexp, Value: []string{"^app"}}},
+		{name: "regexp not compiled inverted", term: resource.LabelTerm{Key: "app", Op: resource.LabelOpRegexp, Value: []string{"^app"}, Invert: true}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, labels.Matches(tt.term))
+		})
+	}
+}
+
+func TestRawLabelQueryRegexp(t *testing.T) {
+	labels := resource.Labels{}
+	labels.Set("app", "app1")
+
+	for _, tt := range []struct {
+		name    string
+		expr    string
+		matches bool
+	}{
+		{name: "valid", expr: "^app[0-9]$", matches: true},
+		{name: "mismatch", expr: "^web"},
+		{name: "invalid", expr: "app("},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := resource.LabelQuery{
+				Terms: []resource.LabelTerm{{Key: "app", Op: resource.LabelOpRegexp, Value: []string{tt.expr}}},
+			}
+
+			var query resource.LabelQuery
+
+			resource.RawLabelQuery(raw)(&query)
+
+			assert.Equal(t, tt.matches, query.Matches(labels))
+			assert.Nil(t, raw.Terms[0].Regexp)
+		})
+	}
+}
+
+func regexpTerm(label, expr string, opts ...resource.TermOption) resource.LabelTerm {
+	var query resource.LabelQuery
+
+	resource.LabelRegexp(label, regexp.MustCompile(expr), opts...)(&query)
+
+	return query.Terms[0]
+}
+
 func TestLabelQuery(t *testing.T) {
 	var labels resource.Labels
 
@@ -0,0 +1,277 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseLabelSelector parses a Kubernetes-style label selector string into a LabelQuery.
+//
+// Selector is a comma-separated list of requirements, which are combined with AND:
+//
+//	key              label exists
+//	!key             label doesn't exist
+//	key=value        label value is equal (key==value is also accepted)
+//	key!=value       label value is not equal (or the label doesn't exist)
+//	key in (a,b)     label value is in the set
+//	key notin (a,b)  label value is not in the set (or the label doesn't exist)
+//	key<value        label value is less than the number (LabelOpLTNumeric)
+//	key<=value       label value is less than or equal to the number (LabelOpLTENumeric)
+//	key>value        label value is greater than the number (LabelOpGTNumeric)
+//	key>=value       label value is greater than or equal to the number (LabelOpGTENumeric)
+//
+// Empty selector matches all resources.
+func ParseLabelSelector(selector string) (LabelQuery, error) {
+	p := selectorParser{
+		tokens: tokenizeSelector(selector),
+	}
+
+	var query LabelQuery
+
+	if len(p.tokens) == 0 {
+		return query, nil
+	}
+
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return LabelQuery{}, fmt.Errorf("error parsing label selector %q: %w", selector, err)
+		}
+
+		query.Terms = append(query.Terms, term)
+
+		switch tok := p.next(); tok {
+		case "":
+			return query, nil
+		case ",":
+		default:
+			return LabelQuery{}, fmt.Errorf("error parsing label selector %q: expected \",\", got %q", selector, tok)
+		}
+	}
+}
+
+// FormatLabelSelector formats a LabelQuery as a Kubernetes-style label selector string.
+//
+// FormatLabelSelector is the reverse of ParseLabelSelector, it returns an error
+// if the query contains terms which can't be represented in the selector syntax.
+func FormatLabelSelector(query LabelQuery) (string, error) {
+	requirements := make([]string, 0, len(query.Terms))
+
+	for _, term := range query.Terms {
+		requirement, err := formatSelectorTerm(term)
+		if err != nil {
+			return "", err
+		}
+
+		requirements = append(requirements, requirement)
+	}
+
+	return strings.Join(requirements, ","), nil
+}
+
+//nolint:gocyclo,cyclop
+func formatSelectorTerm(term LabelTerm) (string, error) {
+	if !isSelectorIdent(term.Key) {
+		return "", fmt.Errorf("label key %q can't be represented in the selector", term.Key)
+	}
+
+	for _, value := range term.Value {
+		if value != "" && !isSelectorIdent(value) {
+			return "", fmt.Errorf("label value %q can't be represented in the selector", value)
+		}
+	}
+
+	switch {
+	case term.Op == LabelOpExists && term.Invert:
+		return "!" + term.Key, nil
+	case term.Op == LabelOpExists:
+		return term.Key, nil
+	case term.Op == LabelOpEqual && len(term.Value) == 1 && term.Invert:
+		return term.Key + "!=" + term.Value[0], nil
+	case term.Op == LabelOpEqual && len(term.Value) == 1:
+		return term.Key + "=" + term.Value[0], nil
+	case term.Op == LabelOpIn && term.Invert:
+		return term.Key + " notin (" + strings.Join(term.Value, ",") + ")", nil
+	case term.Op == LabelOpIn:
+		return term.Key + " in (" + strings.Join(term.Value, ",") + ")", nil
+	case term.Op == LabelOpLTNumeric && len(term.Value) == 1 && !term.Invert:
+		return term.Key + "<" + term.Value[0], nil
+	case term.Op == LabelOpLTENumeric && len(term.Value) == 1 && !term.Invert:
+		return term.Key + "<=" + term.Value[0], nil
+	case term.Op == LabelOpGTNumeric && len(term.Value) == 1 && !term.Invert:
+		return term.Key + ">" + term.Value[0], nil
+	case term.Op == LabelOpGTENumeric && len(term.Value) == 1 && !term.Invert:
+		return term.Key + ">=" + term.Value[0], nil
+	default:
+		return "", fmt.Errorf("label term on %q can't be represented in the selector", term.Key)
+	}
+}
+
+// selectorOperators are the tokens of the selector which are not identifiers, longest first.
+var selectorOperators = []string{"==", "!=", "<=", ">=", "=", "!", "<", ">", ",", "(", ")"}
+
+func isSelectorIdent(s string) bool {
+	return s != "" && !strings.ContainsAny(s, " \t\n=!<>,()")
+}
+
+func tokenizeSelector(s string) []string {
+	var tokens []string
+
+	for {
+		s = strings.TrimLeft(s, " \t\n")
+		if s == "" {
+			return tokens
+		}
+
+		token := ""
+
+		for _, op := range selectorOperators {
+			if strings.HasPrefix(s, op) {
+				token = op
+
+				break
+			}
+		}
+
+		if token == "" {
+			end := strings.IndexAny(s, " \t\n=!<>,()")
+			if end == -1 {
+				end = len(s)
+			}
+
+			token = s[:end]
+		}
+
+		tokens = append(tokens, token)
+		s = s[len(token):]
+	}
+}
+
+// selectorComparisons maps the comparison operators of the selector to the numeric label operations.
+var selectorComparisons = map[string]LabelOp{
+	"<":  LabelOpLTNumeric,
+	"<=": LabelOpLTENumeric,
+	">":  LabelOpGTNumeric,
+	">=": LabelOpGTENumeric,
+}
+
+type selectorParser struct {
+	tokens []string
+}
+
+func (p *selectorParser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+
+	return p.tokens[0]
+}
+
+func (p *selectorParser) next() string {
+	tok := p.peek()
+
+	if len(p.tokens) > 0 {
+		p.tokens = p.tokens[1:]
+	}
+
+	return tok
+}
+
+func (p *selectorParser) ident(what string) (string, error) {
+	tok := p.next()
+	if !isSelectorIdent(tok) {
+		if tok == "" {
+			return "", fmt.Errorf("expected %s, got end of selector", what)
+		}
+
+		return "", fmt.Errorf("expected %s, got %q", what, tok)
+	}
+
+	return tok, nil
+}
+
+// value parses the value of the equality requirement, which might be empty.
+func (p *selectorParser) value() string {
+	if tok := p.peek(); isSelectorIdent(tok) {
+		return p.next()
+	}
+
+	return ""
+}
+
+//nolint:gocyclo,cyclop
+func (p *selectorParser) parseTerm() (LabelTerm, error) {
+	if p.peek() == "!" {
+		p.next()
+
+		key, err := p.ident("label key")
+		if err != nil {
+			return LabelTerm{}, err
+		}
+
+		return LabelTerm{Key: key, Op: LabelOpExists, Invert: true}, nil
+	}
+
+	key, err := p.ident("label key")
+	if err != nil {
+		return LabelTerm{}, err
+	}
+
+	switch op := p.peek(); op {
+	case "", ",":
+		return LabelTerm{Key: key, Op: LabelOpExists}, nil
+	case "=", "==", "!=":
+		p.next()
+
+		return LabelTerm{Key: key, Op: LabelOpEqual, Value: []string{p.value()}, Invert: op == "!="}, nil
+	case "<", "<=", ">", ">=":
+		p.next()
+
+		value, err := p.ident("label value")
+		if err != nil {
+			return LabelTerm{}, err
+		}
+
+		return LabelTerm{Key: key, Op: selectorComparisons[op], Value: []string{value}}, nil
+	case "in", "notin":
+		p.next()
+
+		values, err := p.parseSet()
+		if err != nil {
+			return LabelTerm{}, err
+		}
+
+		return LabelTerm{Key: key, Op: LabelOpIn, Value: values, Invert: op == "notin"}, nil
+	default:
+		return LabelTerm{}, fmt.Errorf("unexpected %q after label key %q", op, key)
+	}
+}
+
+func (p *selectorParser) parseSet() ([]string, error) {
+	if tok := p.next(); tok != "(" {
+		return nil, fmt.Errorf("expected \"(\", got %q", tok)
+	}
+
+	var values []string
+
+	for {
+		value, err := p.ident("label value")
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+
+		switch tok := p.next(); tok {
+		case ")":
+			return values, nil
+		case ",":
+		default:
+			return nil, fmt.Errorf("expected \",\" or \")\", got %q", tok)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct { //nolint:govet
+		selector  string
+		expected  resource.LabelQuery
+		formatted string
+	}{
+		{
+			selector: "",
+		},
+		{
+			selector: "a=b,c!=d,e in (x,y),!f,g<10",
+			expected: resource.LabelQuery{
+				Terms: []resource.LabelTerm{
+					{Key: "a", Op: resource.LabelOpEqual, Value: []string{"b"}},
+					{Key: "c", Op: resource.LabelOpEqual, Value: []string{"d"}, Invert: true},
+					{Key: "e", Op: resource.LabelOpIn, Value: []string{"x", "y"}},
+					{Key: "f", Op: resource.LabelOpExists, Invert: true},
+					{Key: "g", Op: resource.LabelOpLTNumeric, Value: []string{"10"}},
+				},
+			},
+		},
+		{
+			selector: " role == worker , zone notin ( a , b ), ready, mem<=16, cpu>2, disk >= 100 ",
+			expected: resource.LabelQuery{
+				Terms: []resource.LabelTerm{
+					{Key: "role", Op: resource.LabelOpEqual, Value: []string{"worker"}},
+					{Key: "zone", Op: resource.LabelOpIn, Value: []string{"a", "b"}, Invert: true},
+					{Key: "ready", Op: resource.LabelOpExists},
+					{Key: "mem", Op: resource.LabelOpLTENumeric, Value: []string{"16"}},
+					{Key: "cpu", Op: resource.LabelOpGTNumeric, Value: []string{"2"}},
+					{Key: "disk", Op: resource.LabelOpGTENumeric, Value: []string{"100"}},
+				},
+			},
+			formatted: "role=worker,zone notin (a,b),ready,mem<=16,cpu>2,disk>=100",
+		},
+		{
+			selector: "example.com/role=,in",
+			expected: resource.LabelQuery{
+				Terms: []resource.LabelTerm{
+					{Key: "example.com/role", Op: resource.LabelOpEqual, Value: []string{""}},
+					{Key: "in", Op: resource.LabelOpExists},
+				},
+			},
+		},
+	} {
+		t.Run(test.selector, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := resource.ParseLabelSelector(test.selector)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, query)
+
+			formatted, err := resource.FormatLabelSelector(query)
+			require.NoError(t, err)
+
+			if test.formatted == "" {
+				test.formatted = test.selector
+			}
+
+			assert.Equal(t, test.formatted, formatted)
+		})
+	}
+}
+
+func TestParseLabelSelectorErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, selector := range []string{
+		"a=b,",
+		",a",
+		"!",
+		"!a=b",
+		"a b",
+		"a in x",
+		"a in (x",
+		"a in ()",
+		"a in (x y)",
+		"a<",
+		"a>",
+		"a>=>1",
+		"a==b=c",
+	} {
+		_, err := resource.ParseLabelSelector(selector)
+		assert.Error(t, err, selector)
+	}
+}
+
+func TestFormatLabelSelectorErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, term := range []resource.LabelTerm{
+		{Key: "a", Op: resource.LabelOpLT, Value: []string{"b"}},
+		{Key: "a", Op: resource.LabelOpRegexp, Value: []string{"^b"}},
+		{Key: "a", Op: resource.LabelOpLTNumeric, Value: []string{"1"}, Invert: true},
+		{Key: "a b", Op: resource.LabelOpExists},
+		{Key: "a", Op: resource.LabelOpEqual, Value: []string{"b,c"}},
+	} {
+		_, err := resource.FormatLabelSelector(resource.LabelQuery{Terms: []resource.LabelTerm{term}})
+		assert.Error(t, err, term.Key)
+	}
+}
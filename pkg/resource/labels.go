@@ -7,6 +7,7 @@ package resource
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/cosi-project/runtime/pkg/resource/internal/compare"
 	"github.com/cosi-project/runtime/pkg/resource/internal/kv"
@@ -88,6 +89,46 @@ func (labels Labels) matches(term LabelTerm) *bool {
 		}
 
 		return new(left <= right)
+	case LabelOpGTE:
+		return new(value >= term.Value[0])
+	case LabelOpGT:
+		return new(value > term.Value[0])
+	case LabelOpGTNumeric:
+		left, right, ok := compare.GetNumbers(value, term.Value[0])
+		if !ok {
+			return nil
+		}
+
+		return new(left > right)
+	case LabelOpGTENumeric:
+		left, right, ok := compare.GetNumbers(value, term.Value[0])
+		if !ok {
+			return nil
+		}
+
+		return new(left >= right)
+	case LabelOpSemver:
+		for _, constraint := range term.Value {
+			matches, ok := compare.MatchSemver(value, constraint)
+			if !ok {
+				return nil
+			}
+
+			if !matches {
+				return new(false)
+			}
+		}
+
+		return new(true)
+	case LabelOpPrefix:
+		return new(strings.HasPrefix(value, term.Value[0]))
+	case LabelOpRegexp:
+		if term.Regexp == nil {
+			// expression is not compiled or is not valid, never matches
+			return nil
+		}
+
+		return new(term.Regexp.MatchString(value))
 	default:
 		panic(fmt.Sprintf("unsupported label term operator: %v", term.Op))
 	}
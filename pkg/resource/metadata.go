@@ -22,6 +22,7 @@ var _ Reference = Metadata{}
 type Metadata struct {
 	created     time.Time
 	updated     time.Time
+	expires     time.Time
 	ns          Namespace
 	typ         Type
 	id          ID
@@ -30,6 +31,7 @@ type Metadata struct {
 	labels      Labels
 	annotations Annotations
 	fins        Finalizers
+	ownerRefs   OwnerReferences
 	phase       Phase
 }
 
@@ -83,6 +85,18 @@ func (md Metadata) Updated() time.Time {
 	return md.updated
 }
 
+// Expires returns resource expiration timestamp.
+//
+// Zero value means that the resource never expires.
+func (md Metadata) Expires() time.Time {
+	return md.expires
+}
+
+// Expired returns true if the resource has an expiration timestamp which is not after now.
+func (md Metadata) Expired(now time.Time) bool {
+	return !md.expires.IsZero() && !md.expires.After(now)
+}
+
 // SetVersion updates resource version.
 func (md *Metadata) SetVersion(newVersion Version) {
 	md.ver = newVersion
@@ -98,11 +112,29 @@ func (md *Metadata) SetUpdated(t time.Time) {
 	md.updated = t
 }
 
+// SetExpires sets the resource expiration timestamp.
+//
+// Once the timestamp passes, the resource is torn down and destroyed by the controller runtime
+// (if configured to handle the resource kind). Zero value clears the expiration.
+func (md *Metadata) SetExpires(t time.Time) {
+	md.expires = t
+}
+
+// SetTTL sets the resource expiration timestamp to the specified duration from now.
+func (md *Metadata) SetTTL(ttl time.Duration) {
+	md.expires = time.Now().Add(ttl)
+}
+
 // Finalizers returns a reference to the finalizers.
 func (md *Metadata) Finalizers() *Finalizers {
 	return &md.fins
 }
 
+// OwnerReferences returns a reference to the owner references.
+func (md *Metadata) OwnerReferences() *OwnerReferences {
+	return &md.ownerRefs
+}
+
 // Labels returns a reference to the labels.
 func (md *Metadata) Labels() *Labels {
 	return &md.labels
@@ -153,6 +185,7 @@ func (md Metadata) Equal(other Metadata) bool {
 		md.id == other.id &&
 		md.phase == other.phase &&
 		md.owner == other.owner &&
+		md.expires.Equal(other.expires) &&
 		md.ver.Equal(other.ver)
 	if !equal {
 		return false
@@ -166,6 +199,10 @@ func (md Metadata) Equal(other Metadata) bool {
 		return false
 	}
 
+	if !md.ownerRefs.Equal(other.ownerRefs) {
+		return false
+	}
+
 	if len(md.fins) != len(other.fins) {
 		return false
 	}
@@ -217,6 +254,50 @@ func (md *Metadata) MarshalYAML() (any, error) {
 		}
 	}
 
+	var ownerRefs []*yaml.Node
+
+	if !md.ownerRefs.Empty() {
+		ownerRefs = []*yaml.Node{
+			{
+				Kind:  yaml.ScalarNode,
+				Value: "ownerReferences",
+			},
+			{
+				Kind:    yaml.SequenceNode,
+				Content: make([]*yaml.Node, 0, len(md.ownerRefs)),
+			},
+		}
+
+		for _, ref := range md.ownerRefs {
+			ownerRefs[1].Content = append(ownerRefs[1].Content, &yaml.Node{
+				Kind: yaml.MappingNode,
+				Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: "namespace"},
+					{Kind: yaml.ScalarNode, Value: ref.ns},
+					{Kind: yaml.ScalarNode, Value: "type"},
+					{Kind: yaml.ScalarNode, Value: ref.typ},
+					{Kind: yaml.ScalarNode, Value: "id"},
+					{Kind: yaml.ScalarNode, Value: ref.id},
+				},
+			})
+		}
+	}
+
+	var expires []*yaml.Node
+
+	if !md.expires.IsZero() {
+		expires = []*yaml.Node{
+			{
+				Kind:  yaml.ScalarNode,
+				Value: "expires",
+			},
+			{
+				Kind:  yaml.ScalarNode,
+				Value: md.expires.Format(time.RFC3339),
+			},
+		}
+	}
+
 	labels := md.labels.ToYAML("labels")
 	annotations := md.annotations.ToYAML("annotations")
 
@@ -289,9 +370,11 @@ func (md *Metadata) MarshalYAML() (any, error) {
 					Value: md.updated.Format(time.RFC3339),
 				},
 			},
+			expires,
 			labels,
 			annotations,
 			finalizers,
+			ownerRefs,
 		),
 	}, nil
 }
@@ -348,8 +431,12 @@ func (md *Metadata) UnmarshalYAML(value *yaml.Node) (err error) {
 			md.created = scalarParser(val, func(s string) (time.Time, error) { return time.Parse(time.RFC3339, s) })
 		case "updated":
 			md.updated = scalarParser(val, func(s string) (time.Time, error) { return time.Parse(time.RFC3339, s) })
+		case "expires":
+			md.expires = scalarParser(val, func(s string) (time.Time, error) { return time.Parse(time.RFC3339, s) })
 		case "finalizers":
 			md.fins = getFinalizers(val)
+		case "ownerReferences":
+			md.ownerRefs = getOwnerReferences(val)
 		case "labels":
 			md.labels = mappingParser[Labels](val)
 		case "annotations":
@@ -413,6 +500,33 @@ func getFinalizers(val *yaml.Node) Finalizers {
 	return xslices.Map(val.Content, getScalarValue)
 }
 
+func getOwnerReferences(val *yaml.Node) OwnerReferences {
+	if val.Kind != yaml.SequenceNode {
+		panicFormatf("%d:%d expected sequence node, got %d", val.Line, val.Column, val.Kind)
+	}
+
+	return xslices.Map(val.Content, func(node *yaml.Node) OwnerReference {
+		if node.Kind != yaml.MappingNode {
+			panicFormatf("%d:%d expected mapping node, got %d", node.Line, node.Column, node.Kind)
+		}
+
+		var ref OwnerReference
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			switch getScalarValue(node.Content[i]) {
+			case "namespace":
+				ref.ns = getScalarValue(node.Content[i+1])
+			case "type":
+				ref.typ = getScalarValue(node.Content[i+1])
+			case "id":
+				ref.id = getScalarValue(node.Content[i+1])
+			}
+		}
+
+		return ref
+	})
+}
+
 func panicFormatf(format string, a ...any) {
 	panic(&tryError{err: fmt.Errorf(format, a...)})
 }
@@ -434,6 +548,23 @@ type MetadataProto interface { //nolint:interfacebloat
 	GetLabels() map[string]string
 }
 
+// MetadataExpiresProto is an optional interface for MetadataProto which carries the expiration timestamp.
+type MetadataExpiresProto interface {
+	GetExpires() *timestamppb.Timestamp
+}
+
+// OwnerReferenceProto is an interface for protobuf serialization of OwnerReference.
+type OwnerReferenceProto = interface {
+	GetNamespace() string
+	GetType() string
+	GetId() string //nolint:revive,stylecheck
+}
+
+// MetadataOwnerReferencesProto is an optional interface for MetadataProto which carries the owner references.
+type MetadataOwnerReferencesProto interface {
+	GetOwnerReferencesProto() []OwnerReferenceProto
+}
+
 // NewMetadataFromProto builds Metadata object from ProtoMetadata interface data.
 func NewMetadataFromProto(proto MetadataProto) (Metadata, error) {
 	ver, err := ParseVersion(proto.GetVersion())
@@ -451,6 +582,10 @@ func NewMetadataFromProto(proto MetadataProto) (Metadata, error) {
 	md.created = proto.GetCreated().AsTime()
 	md.updated = proto.GetUpdated().AsTime()
 
+	if expiring, ok := proto.(MetadataExpiresProto); ok && expiring.GetExpires() != nil {
+		md.expires = expiring.GetExpires().AsTime()
+	}
+
 	if err := md.SetOwner(proto.GetOwner()); err != nil {
 		return md, err
 	}
@@ -467,5 +602,11 @@ func NewMetadataFromProto(proto MetadataProto) (Metadata, error) {
 		md.Labels().Set(k, v)
 	}
 
+	if owned, ok := proto.(MetadataOwnerReferencesProto); ok {
+		for _, ref := range owned.GetOwnerReferencesProto() {
+			md.OwnerReferences().Add(NewMetadata(ref.GetNamespace(), ref.GetType(), ref.GetId(), VersionUndefined))
+		}
+	}
+
 	return md, nil
 }
@@ -0,0 +1,60 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import "fmt"
+
+var _ Resource = (*MetadataOnly)(nil)
+
+// MetadataOnly is a resource which carries only the metadata, the spec is not loaded.
+//
+// MetadataOnly resources are returned e.g. by the metadata-only cache of the controller runtime.
+// They can't be converted to the typed resource, and they should not be written back to the state.
+type MetadataOnly struct {
+	md Metadata
+}
+
+// NewMetadataOnly builds a metadata-only resource from a copy of the resource metadata.
+func NewMetadataOnly(md *Metadata) *MetadataOnly {
+	return &MetadataOnly{
+		md: md.Copy(),
+	}
+}
+
+// String method for debugging/logging.
+func (r *MetadataOnly) String() string {
+	return fmt.Sprintf("MetadataOnly(%s)", r.md.String())
+}
+
+// Metadata for the resource.
+func (r *MetadataOnly) Metadata() *Metadata {
+	return &r.md
+}
+
+// Spec is always nil, as the spec is not loaded.
+func (r *MetadataOnly) Spec() any {
+	return nil
+}
+
+// DeepCopy returns a copy of the resource.
+func (r *MetadataOnly) DeepCopy() Resource { //nolint:ireturn
+	return NewMetadataOnly(&r.md)
+}
+
+// SpecNotLoaded implements SpecNotLoaded interface.
+func (r *MetadataOnly) SpecNotLoaded() {
+}
+
+// SpecNotLoaded is a marker interface for the resources without the spec loaded.
+type SpecNotLoaded interface {
+	SpecNotLoaded()
+}
+
+// IsMetadataOnly checks if the spec of the resource is not loaded.
+func IsMetadataOnly(res Resource) bool {
+	_, ok := res.(SpecNotLoaded)
+
+	return ok
+}
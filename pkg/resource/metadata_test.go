@@ -96,6 +96,25 @@ func TestMetadata(t *testing.T) {
 
 	md.Annotations().Set("a", "b")
 	assert.True(t, md.Equal(mdCopy))
+
+	now := time.Now()
+
+	assert.True(t, md.Expires().IsZero())
+	assert.False(t, md.Expired(now))
+
+	mdCopy.SetExpires(now)
+	assert.False(t, md.Equal(mdCopy))
+	assert.True(t, mdCopy.Expired(now))
+	assert.False(t, mdCopy.Expired(now.Add(-time.Second)))
+
+	md.SetExpires(now)
+	assert.True(t, md.Equal(mdCopy))
+
+	mdCopy.OwnerReferences().Add(resource.NewMetadata("default", "parent", "a", resource.VersionUndefined))
+	assert.False(t, md.Equal(mdCopy))
+
+	md.OwnerReferences().Add(resource.NewMetadata("default", "parent", "a", resource.VersionUndefined))
+	assert.True(t, md.Equal(mdCopy))
 }
 
 func TestMetadataMarshalYAML(t *testing.T) {
@@ -187,6 +206,36 @@ finalizers:
 
 	assert.NoError(t, yaml.Unmarshal(out, &in))
 	assert.True(t, md.Equal(in))
+
+	md.SetExpires(ts)
+	md.OwnerReferences().Add(resource.NewMetadata("default", "parent", "a", resource.VersionUndefined))
+
+	out, err = yaml.Marshal(&md)
+	assert.NoError(t, err)
+	assert.Equal(t, `namespace: default
+type: type
+id: aaa
+version: 1
+owner: FooController
+phase: running
+`+timestamps+`expires: 2021-06-23T19:22:29Z
+labels:
+    app: foo
+    stage: initial
+annotations:
+    dependencies: abcdef
+    ttl: 1h
+finalizers:
+    - '"resource1'
+    - resource2
+ownerReferences:
+    - namespace: default
+      type: parent
+      id: a
+`, string(out))
+
+	assert.NoError(t, yaml.Unmarshal(out, &in))
+	assert.True(t, md.Equal(in))
 }
 
 var ts = ensure.Value(time.Parse(time.RFC3339, "2021-06-23T19:22:29Z"))
@@ -257,6 +306,42 @@ func TestNewMedataFromProto(t *testing.T) {
 	assert.True(t, md.Equal(other))
 }
 
+type protoOwnerReference struct {
+	ns, typ, id string
+}
+
+func (ref protoOwnerReference) GetNamespace() string { return ref.ns }
+func (ref protoOwnerReference) GetType() string      { return ref.typ }
+
+//nolint:golint,revive,stylecheck
+func (ref protoOwnerReference) GetId() string { return ref.id }
+
+type protoMdWithOwners struct {
+	protoMd
+}
+
+func (p *protoMdWithOwners) GetOwnerReferencesProto() []resource.OwnerReferenceProto {
+	return []resource.OwnerReferenceProto{
+		protoOwnerReference{ns: "default", typ: "parent", id: "a"},
+		protoOwnerReference{ns: "other", typ: "parent", id: "b"},
+	}
+}
+
+func TestNewMedataFromProtoOwnerReferences(t *testing.T) {
+	md, err := resource.NewMetadataFromProto(&protoMdWithOwners{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, resource.OwnerReferences{
+		resource.NewOwnerReference(resource.NewMetadata("default", "parent", "a", resource.VersionUndefined)),
+		resource.NewOwnerReference(resource.NewMetadata("other", "parent", "b", resource.VersionUndefined)),
+	}, *md.OwnerReferences())
+
+	r, err := resource.NewAnyFromProto(&protoMdWithOwners{}, &protoSpec{})
+	assert.NoError(t, err)
+
+	assert.True(t, r.Metadata().OwnerReferences().Equal(*md.OwnerReferences()))
+}
+
 func BenchmarkMetadataEqual(b *testing.B) {
 	for _, l := range []int{0, 1, 2, 3} {
 		b.Run(strconv.Itoa(l), func(b *testing.B) {
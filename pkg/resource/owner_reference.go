@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource
+
+import (
+	"fmt"
+	"slices"
+)
+
+var _ Pointer = OwnerReference{}
+
+// OwnerReference points to the parent resource of a resource.
+//
+// Resources with owner references are torn down and destroyed by the controller runtime
+// garbage collector once the parent resource is torn down or destroyed.
+type OwnerReference struct {
+	ns  Namespace
+	typ Type
+	id  ID
+}
+
+// NewOwnerReference builds an owner reference to the resource pointed by ptr.
+func NewOwnerReference(ptr Pointer) OwnerReference {
+	return OwnerReference{
+		ns:  ptr.Namespace(),
+		typ: ptr.Type(),
+		id:  ptr.ID(),
+	}
+}
+
+// Namespace implements Pointer.
+func (ref OwnerReference) Namespace() Namespace {
+	return ref.ns
+}
+
+// Type implements Pointer.
+func (ref OwnerReference) Type() Type {
+	return ref.typ
+}
+
+// ID implements Pointer.
+func (ref OwnerReference) ID() ID {
+	return ref.id
+}
+
+// String implements fmt.Stringer.
+func (ref OwnerReference) String() string {
+	return fmt.Sprintf("%s(%s/%s)", ref.typ, ref.ns, ref.id)
+}
+
+// OwnerReferences is a set of OwnerReference's with methods to add/remove items.
+//
+//nolint:recvcheck
+type OwnerReferences []OwnerReference
+
+// Add a (unique) OwnerReference to the set.
+func (refs *OwnerReferences) Add(ptr Pointer) bool {
+	*refs = slices.Clone(*refs)
+
+	if refs.Has(ptr) {
+		return false
+	}
+
+	*refs = append(*refs, NewOwnerReference(ptr))
+
+	return true
+}
+
+// Remove a (unique) OwnerReference from the set.
+func (refs *OwnerReferences) Remove(ptr Pointer) bool {
+	*refs = slices.Clone(*refs)
+
+	for i, ref := range *refs {
+		if ref == NewOwnerReference(ptr) {
+			*refs = append((*refs)[:i], (*refs)[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Empty returns true if list of owner references is empty.
+func (refs OwnerReferences) Empty() bool {
+	return len(refs) == 0
+}
+
+// Has returns true if the reference to ptr is present in the list of owner references.
+func (refs OwnerReferences) Has(ptr Pointer) bool {
+	return slices.Contains(refs, NewOwnerReference(ptr))
+}
+
+// Set copies the owner references from the other.
+func (refs *OwnerReferences) Set(other OwnerReferences) {
+	*refs = slices.Clone(other)
+}
+
+// Equal tests two sets of owner references for equality (order is ignored).
+func (refs OwnerReferences) Equal(other OwnerReferences) bool {
+	if len(refs) != len(other) {
+		return false
+	}
+
+	for _, ref := range refs {
+		if !slices.Contains(other, ref) {
+			return false
+		}
+	}
+
+	return true
+}
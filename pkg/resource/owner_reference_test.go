@@ -0,0 +1,47 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+func TestOwnerReferences(t *testing.T) {
+	t.Parallel()
+
+	a := resource.NewMetadata("default", "parent", "a", resource.VersionUndefined)
+	b := resource.NewMetadata("default", "parent", "b", resource.VersionUndefined)
+	c := resource.NewMetadata("other", "parent", "a", resource.VersionUndefined)
+
+	var refs resource.OwnerReferences
+
+	assert.True(t, refs.Empty())
+
+	assert.True(t, refs.Add(a))
+
+	refsCopy := refs
+
+	assert.True(t, refs.Add(b))
+	assert.False(t, refs.Add(b))
+	assert.True(t, refs.Has(b))
+	assert.False(t, refs.Has(c))
+	assert.False(t, refsCopy.Has(b))
+
+	assert.False(t, refs.Equal(refsCopy))
+	assert.True(t, refsCopy.Add(b))
+	assert.True(t, refs.Equal(refsCopy))
+
+	assert.False(t, refs.Remove(c))
+	assert.True(t, refs.Remove(a))
+	assert.False(t, refs.Remove(a))
+	assert.Equal(t, "parent(default/b)", refs[0].String())
+
+	refsCopy.Set(refs)
+	assert.True(t, refs.Equal(refsCopy))
+}
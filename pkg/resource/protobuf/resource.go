@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
+	"github.com/siderolabs/gen/xslices"
 	"go.yaml.in/yaml/v4"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 
@@ -18,6 +20,11 @@ import (
 
 var _ resource.Resource = (*Resource)(nil)
 
+var (
+	_ resource.MetadataExpiresProto         = (*v1alpha1.Metadata)(nil)
+	_ resource.MetadataOwnerReferencesProto = (*v1alpha1.Metadata)(nil)
+)
+
 // Resource which can be marshaled and unmarshaled from protobuf.
 type Resource struct {
 	spec protoSpec
@@ -83,17 +90,19 @@ func (r *Resource) DeepCopy() resource.Resource { //nolint:ireturn
 func (r *Resource) Marshal() (*v1alpha1.Resource, error) {
 	return &v1alpha1.Resource{
 		Metadata: &v1alpha1.Metadata{
-			Namespace:   r.md.Namespace(),
-			Type:        r.md.Type(),
-			Id:          r.md.ID(),
-			Version:     r.md.Version().String(),
-			Owner:       r.md.Owner(),
-			Phase:       r.md.Phase().String(),
-			Created:     timestamppb.New(r.md.Created()),
-			Updated:     timestamppb.New(r.md.Updated()),
-			Finalizers:  *r.md.Finalizers(),
-			Annotations: r.md.Annotations().Raw(),
-			Labels:      r.md.Labels().Raw(),
+			Namespace:       r.md.Namespace(),
+			Type:            r.md.Type(),
+			Id:              r.md.ID(),
+			Version:         r.md.Version().String(),
+			Owner:           r.md.Owner(),
+			Phase:           r.md.Phase().String(),
+			Created:         timestamppb.New(r.md.Created()),
+			Updated:         timestamppb.New(r.md.Updated()),
+			Finalizers:      *r.md.Finalizers(),
+			Annotations:     r.md.Annotations().Raw(),
+			Labels:          r.md.Labels().Raw(),
+			Expires:         marshalExpires(r.md.Expires()),
+			OwnerReferences: marshalOwnerReferences(*r.md.OwnerReferences()),
 		},
 		Spec: &v1alpha1.Spec{
 			ProtoSpec: r.spec.protobuf,
@@ -218,3 +227,25 @@ func Unmarshal(protoResource *v1alpha1.Resource) (*Resource, error) {
 		},
 	}, nil
 }
+
+func marshalExpires(expires time.Time) *timestamppb.Timestamp {
+	if expires.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(expires)
+}
+
+func marshalOwnerReferences(refs resource.OwnerReferences) []*v1alpha1.OwnerReference {
+	if refs.Empty() {
+		return nil
+	}
+
+	return xslices.Map(refs, func(ref resource.OwnerReference) *v1alpha1.OwnerReference {
+		return &v1alpha1.OwnerReference{
+			Namespace: ref.Namespace(),
+			Type:      ref.Type(),
+			Id:        ref.ID(),
+		}
+	})
+}
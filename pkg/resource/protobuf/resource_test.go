@@ -23,6 +23,7 @@ func TestMarshalUnmarshal(t *testing.T) {
 
 	created, _ := time.Parse(time.RFC3339, "2021-06-23T19:22:29Z") //nolint:errcheck
 	updated, _ := time.Parse(time.RFC3339, "2021-06-23T20:22:29Z") //nolint:errcheck
+	expires, _ := time.Parse(time.RFC3339, "2021-06-24T20:22:29Z") //nolint:errcheck
 
 	protoR := &v1alpha1.Resource{
 		Metadata: &v1alpha1.Metadata{
@@ -34,7 +35,11 @@ func TestMarshalUnmarshal(t *testing.T) {
 			Phase:      "running",
 			Created:    timestamppb.New(created),
 			Updated:    timestamppb.New(updated),
+			Expires:    timestamppb.New(expires),
 			Finalizers: []string{"a1", "a2"},
+			OwnerReferences: []*v1alpha1.OwnerReference{
+				{Namespace: "ns", Type: "parent", Id: "p1"},
+			},
 			Annotations: map[string]string{
 				"ttl": "1h",
 			},
@@ -79,6 +84,7 @@ func TestMarshalUnmarshal(t *testing.T) {
     phase: running
     created: 2021-06-23T19:22:29Z
     updated: 2021-06-23T20:22:29Z
+    expires: 2021-06-24T20:22:29Z
     labels:
         app: foo
         stage: initial
@@ -87,6 +93,10 @@ func TestMarshalUnmarshal(t *testing.T) {
     finalizers:
         - a1
         - a2
+    ownerReferences:
+        - namespace: ns
+          type: parent
+          id: p1
 spec: true
 `,
 		string(yy))
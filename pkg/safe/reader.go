@@ -6,6 +6,7 @@ package safe
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cosi-project/runtime/pkg/controller"
 	"github.com/cosi-project/runtime/pkg/controller/generic"
@@ -32,6 +33,33 @@ func ReaderGet[T resource.Resource](ctx context.Context, rdr controller.Reader,
 	return result, nil
 }
 
+// ReaderGetReadOnly is a type safe wrapper around ReadOnlyReader.GetReadOnly.
+//
+// The returned resource might be shared with the cache, so it must not be modified.
+// If the reader doesn't implement controller.ReadOnlyReader, it falls back to Reader.Get.
+func ReaderGetReadOnly[T resource.Resource](ctx context.Context, rdr controller.Reader, ptr resource.Pointer) (T, error) { //nolint:ireturn
+	readOnlyRdr, ok := rdr.(controller.ReadOnlyReader)
+	if !ok {
+		return ReaderGet[T](ctx, rdr, ptr)
+	}
+
+	got, err := readOnlyRdr.GetReadOnly(ctx, ptr)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	result, ok := got.(T)
+	if !ok {
+		var zero T
+
+		return zero, typeMismatchErr(result, got)
+	}
+
+	return result, nil
+}
+
 // ReaderGetByID is a type safe wrapper around reader.Get.
 func ReaderGetByID[T generic.ResourceWithRD](ctx context.Context, rdr controller.Reader, id resource.ID) (T, error) { //nolint:ireturn
 	var r T
@@ -63,6 +91,42 @@ func ReaderGetByID[T generic.ResourceWithRD](ctx context.Context, rdr controller
 // ReaderList is a type safe wrapper around Reader.List.
 func ReaderList[T resource.Resource](ctx context.Context, rdr controller.Reader, kind resource.Kind, opts ...state.ListOption) (List[T], error) {
 	got, err := rdr.List(ctx, kind, opts...)
+
+	return readerListResult[T](got, err)
+}
+
+// ReaderListReadOnly is a type safe wrapper around ReadOnlyReader.ListReadOnly.
+//
+// The returned resources might be shared with the cache, so they must not be modified.
+// If the reader doesn't implement controller.ReadOnlyReader, it falls back to Reader.List.
+func ReaderListReadOnly[T resource.Resource](ctx context.Context, rdr controller.Reader, kind resource.Kind, opts ...state.ListOption) (List[T], error) {
+	readOnlyRdr, ok := rdr.(controller.ReadOnlyReader)
+	if !ok {
+		return ReaderList[T](ctx, rdr, kind, opts...)
+	}
+
+	got, err := readOnlyRdr.ListReadOnly(ctx, kind, opts...)
+
+	return readerListResult[T](got, err)
+}
+
+// ReaderListByIndex is a type safe wrapper around IndexReader.ListByIndex.
+//
+// The reader should implement controller.IndexReader, otherwise an error is returned.
+func ReaderListByIndex[T resource.Resource](ctx context.Context, rdr controller.Reader, kind resource.Kind, index, key string) (List[T], error) {
+	indexRdr, ok := rdr.(controller.IndexReader)
+	if !ok {
+		var zero List[T]
+
+		return zero, fmt.Errorf("reader %T doesn't support listing by index", rdr)
+	}
+
+	got, err := indexRdr.ListByIndex(ctx, kind, index, key)
+
+	return readerListResult[T](got, err)
+}
+
+func readerListResult[T resource.Resource](got resource.List, err error) (List[T], error) {
 	if err != nil {
 		var zero List[T]
 
@@ -98,3 +162,15 @@ func ReaderListAll[T generic.ResourceWithRD](ctx context.Context, rdr controller
 
 	return ReaderList[T](ctx, rdr, md, opts...)
 }
+
+// ReaderSnapshot returns a reader pinned to a consistent view of the cached resources (see controller.SnapshotReader).
+//
+// If the reader doesn't support snapshots, the reader itself is returned.
+func ReaderSnapshot(ctx context.Context, rdr controller.Reader) (controller.Reader, error) { //nolint:ireturn
+	snapshotRdr, ok := rdr.(controller.SnapshotReader)
+	if !ok {
+		return rdr, nil
+	}
+
+	return snapshotRdr.Snapshot(ctx)
+}
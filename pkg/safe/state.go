@@ -18,14 +18,28 @@ import (
 	"github.com/cosi-project/runtime/pkg/state"
 )
 
-func typeMismatchErr(expected, got any) error {
+func typeMismatchErr(expected any, got resource.Resource) error {
+	if resource.IsMetadataOnly(got) {
+		return metadataOnlyErr(expected, got)
+	}
+
 	return fmt.Errorf("type mismatch: expected %T, got %T", expected, got)
 }
 
-func typeMismatchFirstElErr(expected, got any) error {
+func typeMismatchFirstElErr(expected any, got resource.Resource) error {
+	if resource.IsMetadataOnly(got) {
+		return metadataOnlyErr(expected, got)
+	}
+
 	return fmt.Errorf("type mismatch on the first element: expected %T, got %T", expected, got)
 }
 
+// metadataOnlyErr is returned for the metadata-only resources (e.g. served by the metadata-only cache),
+// as the typed resource can't be built without the spec.
+func metadataOnlyErr(expected any, got resource.Resource) error {
+	return fmt.Errorf("type mismatch: expected %T, got metadata-only %s, read it as resource.Resource or *resource.MetadataOnly", expected, got.Metadata().Type())
+}
+
 // StateGet is a type safe wrapper around state.Get.
 func StateGet[T resource.Resource](ctx context.Context, st state.CoreState, ptr resource.Pointer, options ...state.GetOption) (T, error) { //nolint:ireturn
 	got, err := st.Get(ctx, ptr, options...)
@@ -107,6 +121,34 @@ func StateListAll[T generic.ResourceWithRD](ctx context.Context, st state.CoreSt
 	return StateList[T](ctx, st, md, opts...)
 }
 
+// StateHistory is a type safe wrapper around state.HistoryReader.History.
+//
+// If the state doesn't implement state.HistoryReader, unsupported error is returned.
+func StateHistory[T resource.Resource](ctx context.Context, st state.CoreState, ptr resource.Pointer, n int, options ...state.HistoryOption) ([]T, error) {
+	historyReader, ok := st.(state.HistoryReader)
+	if !ok {
+		return nil, state.ErrUnsupportedOperation("history")
+	}
+
+	got, err := historyReader.History(ctx, ptr, n, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, 0, len(got))
+
+	for _, r := range got {
+		typed, ok := r.(T)
+		if !ok {
+			return nil, typeMismatchErr(typed, r)
+		}
+
+		result = append(result, typed)
+	}
+
+	return result, nil
+}
+
 // WrappedStateEvent holds a state.Event that can be cast to its original Resource type when accessed with Event().
 type WrappedStateEvent[T resource.Resource] struct {
 	event state.Event
@@ -281,6 +323,13 @@ func (l *List[T]) FilterLabelQuery(opts ...resource.LabelQueryOption) List[T] {
 	return NewList[T](filteredList)
 }
 
+// FilterExpression returns a new list applying the filter expression.
+func (l *List[T]) FilterExpression(expr resource.FilterExpression) List[T] {
+	return NewList[T](resource.List{
+		Items: xslices.Filter(l.list.Items, func(r resource.Resource) bool { return resource.MatchesFilter(expr, r) }),
+	})
+}
+
 // ForEachErr iterates over the given list and calls the given function for each element.
 // If the function returns an error, the iteration stops and the error is returned.
 func (l *List[T]) ForEachErr(fn func(T) error) error {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/cosi-project/runtime/pkg/controller/conformance"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/filter"
 	"github.com/cosi-project/runtime/pkg/resource/meta"
 	"github.com/cosi-project/runtime/pkg/resource/typed"
 	"github.com/cosi-project/runtime/pkg/safe"
@@ -146,6 +147,11 @@ func TestListFilter(t *testing.T) {
 	filtered = all.FilterLabelQuery(resource.LabelEqual("value", "4"))
 	assert.Equal(t, 0, filtered.Len())
 
+	filtered = all.FilterExpression(filter.MustCompile(`metadata.labels["value"] == "3" || metadata.id == "1"`))
+	assert.Equal(t, 2, filtered.Len())
+	assert.Equal(t, "1", filtered.Get(0).Metadata().ID())
+	assert.Equal(t, "3", filtered.Get(1).Metadata().ID())
+
 	assert.Equal(t, 3, all.Len())
 }
 
@@ -254,3 +260,35 @@ func TestTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+// metadataOnlyState returns the metadata-only resources (like the metadata-only cache of the controller runtime).
+type metadataOnlyState struct {
+	inmem.State
+}
+
+func (st *metadataOnlyState) List(context.Context, resource.Kind, ...state.ListOption) (resource.List, error) {
+	return resource.List{Items: []resource.Resource{resource.NewMetadataOnly(conformance.NewIntResource(ns, "test", 1).Metadata())}}, nil
+}
+
+func (st *metadataOnlyState) Get(context.Context, resource.Pointer, ...state.GetOption) (resource.Resource, error) {
+	return resource.NewMetadataOnly(conformance.NewIntResource(ns, "test", 1).Metadata()), nil
+}
+
+func TestMetadataOnly(t *testing.T) {
+	s := state.WrapCore(&metadataOnlyState{})
+	kind := resource.NewMetadata(ns, conformance.IntResourceType, "", resource.VersionUndefined)
+
+	_, err := safe.ReaderList[*conformance.IntResource](t.Context(), s, kind)
+	assert.ErrorContains(t, err, "got metadata-only "+conformance.IntResourceType)
+
+	_, err = safe.ReaderGet[*conformance.IntResource](t.Context(), s, kind)
+	assert.ErrorContains(t, err, "got metadata-only "+conformance.IntResourceType)
+
+	_, err = safe.StateList[*conformance.IntResource](t.Context(), s, kind)
+	assert.ErrorContains(t, err, "got metadata-only "+conformance.IntResourceType)
+
+	list, err := safe.ReaderList[*resource.MetadataOnly](t.Context(), s, kind)
+	require.NoError(t, err)
+	require.Equal(t, 1, list.Len())
+	assert.Equal(t, "test", list.Get(0).Metadata().ID())
+}
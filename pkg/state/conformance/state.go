@@ -11,6 +11,7 @@ import (
 	"math/rand"
 	"regexp"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/filter"
 	"github.com/cosi-project/runtime/pkg/safe"
 	"github.com/cosi-project/runtime/pkg/state"
 )
@@ -1135,6 +1137,58 @@ func (suite *StateSuite) TestTeardownDestroy() {
 	suite.Assert().NoError(suite.State.Destroy(ctx, path1.Metadata()))
 }
 
+// TestHistory verifies access to the previous versions of a resource.
+func (suite *StateSuite) TestHistory() {
+	ns := suite.getNamespace()
+	path := NewPathResource(ns, "var/history")
+
+	ctx := context.Background()
+
+	_, err := safe.StateHistory[*PathResource](ctx, suite.State, path.Metadata(), 0)
+	if state.IsUnsupportedError(err) {
+		suite.T().Skip("history is not supported by this backend")
+	}
+
+	suite.Require().Error(err)
+	suite.Assert().True(state.IsNotFoundError(err))
+
+	suite.Require().NoError(suite.State.Create(ctx, path))
+
+	for i := range 3 {
+		_, err = suite.State.UpdateWithConflicts(ctx, path.Metadata(), func(r resource.Resource) error {
+			r.Metadata().Labels().Set("step", strconv.Itoa(i))
+
+			return nil
+		})
+		suite.Require().NoError(err)
+	}
+
+	history, err := safe.StateHistory[*PathResource](ctx, suite.State, path.Metadata(), 2)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 2)
+
+	suite.Assert().Equal("4", history[0].Metadata().Version().String())
+	suite.Assert().Equal("3", history[1].Metadata().Version().String())
+
+	step, _ := history[1].Metadata().Labels().Get("step")
+	suite.Assert().Equal("1", step)
+
+	history, err = safe.StateHistory[*PathResource](ctx, suite.State, path.Metadata(), 0)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 4)
+
+	suite.Assert().Equal("1", history[3].Metadata().Version().String())
+	suite.Assert().True(history[3].Metadata().Labels().Empty())
+
+	suite.Require().NoError(suite.State.Destroy(ctx, path.Metadata()))
+
+	history, err = safe.StateHistory[*PathResource](ctx, suite.State, path.Metadata(), 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(history, 1)
+
+	suite.Assert().Equal("4", history[0].Metadata().Version().String())
+}
+
 // TestUpdate verifies update flow.
 func (suite *StateSuite) TestUpdate() {
 	ns := suite.getNamespace()
@@ -1284,6 +1338,55 @@ func (suite *StateSuite) TestLabels() {
 	suite.Require().Equal(1, list.Len())
 	suite.Assert().True(resourceEqualIgnoreVersion(path1, list.Get(0)))
 
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(resource.LabelGTNumeric("weight", "12000")))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(1, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path2, list.Get(0)))
+
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(resource.LabelGTENumeric("weight", "10000")))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(2, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path1, list.Get(0)))
+	suite.Assert().True(resourceEqualIgnoreVersion(path2, list.Get(1)))
+
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(resource.LabelGTE("app", "app2")))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(2, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path2, list.Get(0)))
+	suite.Assert().True(resourceEqualIgnoreVersion(path3, list.Get(1)))
+
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(resource.LabelGT("app", "app2")))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(1, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path3, list.Get(0)))
+
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(resource.LabelPrefix("weight", "1")))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(1, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path1, list.Get(0)))
+
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(resource.LabelRegexp("app", regexp.MustCompile("^app[13]$"))))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(2, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path1, list.Get(0)))
+	suite.Assert().True(resourceEqualIgnoreVersion(path3, list.Get(1)))
+
+	list, err = safe.StateList[*PathResource](ctx, suite.State, path1.Metadata(), state.WithLabelQuery(
+		resource.LabelIn("app", []string{"app1", "app2", "app3"}),
+		resource.LabelRegexp("weight", regexp.MustCompile("^1"), resource.NotMatches),
+	))
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(2, list.Len())
+	suite.Assert().True(resourceEqualIgnoreVersion(path2, list.Get(0)))
+	suite.Assert().True(resourceEqualIgnoreVersion(path3, list.Get(1)))
+
 	list, err = safe.StateList[*PathResource](
 		ctx, suite.State, path1.Metadata(),
 		state.WithLabelQuery(resource.LabelEqual("app", "app2")),
@@ -1363,6 +1466,77 @@ func (suite *StateSuite) TestIDQuery() {
 	}
 }
 
+// TestFilter verifies filter expressions for List and WatchKind operations.
+func (suite *StateSuite) TestFilter() {
+	ns := suite.getNamespace()
+
+	for i := range 10 {
+		path := NewPathResource(ns, fmt.Sprintf("filter/path%d", i))
+
+		if i == 1 || i == 2 {
+			path.Metadata().Labels().Set("tier", "gold")
+		}
+
+		suite.Require().NoError(suite.State.Create(context.Background(), path))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expr := filter.MustCompile(`metadata.id.startsWith("filter/") && (metadata.labels["tier"] == "gold" || metadata.id.endsWith("7"))`)
+
+	list, err := safe.StateList[*PathResource](
+		ctx, suite.State, NewPathResource(ns, "").Metadata(),
+		state.WithFilter(expr),
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(3, list.Len())
+
+	suite.Assert().Equal("filter/path1", list.Get(0).Metadata().ID())
+	suite.Assert().Equal("filter/path2", list.Get(1).Metadata().ID())
+	suite.Assert().Equal("filter/path7", list.Get(2).Metadata().ID())
+
+	watchCh := make(chan state.Event)
+
+	suite.Require().NoError(suite.State.WatchKind(
+		ctx, NewPathResource(ns, "").Metadata(), watchCh,
+		state.WithBootstrapContents(true),
+		state.WatchWithFilter(expr),
+	))
+
+	for _, i := range []int{1, 2, 7} {
+		select {
+		case event := <-watchCh:
+			suite.Assert().Equal(state.Created, event.Type)
+			suite.Assert().Equal(fmt.Sprintf("filter/path%d", i), event.Resource.Metadata().ID())
+		case <-time.After(1 * time.Second):
+			suite.Require().FailNow("timeout waiting for event")
+		}
+	}
+
+	select {
+	case event := <-watchCh:
+		suite.Assert().Equal(state.Bootstrapped, event.Type)
+	case <-time.After(1 * time.Second):
+		suite.Require().FailNow("timeout waiting for event")
+	}
+
+	for i := range 10 {
+		suite.Require().NoError(suite.State.Destroy(ctx, NewPathResource(ns, fmt.Sprintf("filter/path%d", i)).Metadata()))
+	}
+
+	for _, i := range []int{1, 2, 7} {
+		select {
+		case event := <-watchCh:
+			suite.Assert().Equal(state.Destroyed, event.Type)
+			suite.Assert().Equal(fmt.Sprintf("filter/path%d", i), event.Resource.Metadata().ID())
+		case <-time.After(1 * time.Second):
+			suite.Require().FailNow("timeout waiting for event")
+		}
+	}
+}
+
 // TestContextWithTeardown verifies ContextWithTeardown.
 func (suite *StateSuite) TestContextWithTeardown() {
 	path1 := NewPathResource(suite.getNamespace(), "ctx/r1")
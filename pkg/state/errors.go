@@ -46,6 +46,20 @@ func IsUnsupportedError(err error) bool {
 	return errors.As(err, &i)
 }
 
+//nolint:errname
+type eUnsupported struct {
+	error
+}
+
+func (eUnsupported) UnsupportedError() {}
+
+// ErrUnsupportedOperation generates error compatible with ErrUnsupported.
+func ErrUnsupportedOperation(operation string) error {
+	return eUnsupported{
+		fmt.Errorf("%s is not supported", operation),
+	}
+}
+
 // ErrNotFound should be implemented by "not found" errors.
 type ErrNotFound interface {
 	NotFoundError()
@@ -75,8 +75,41 @@ func (filter *stateFilter) Get(ctx context.Context, resourcePointer resource.Poi
 	return filter.state.Get(ctx, resourcePointer, opts...)
 }
 
+// History returns up to n most recent versions of a resource.
+//
+// History access is checked as Get.
+func (filter *stateFilter) History(ctx context.Context, resourcePointer resource.Pointer, n int, opts ...HistoryOption) ([]resource.Resource, error) {
+	if err := filter.rule(ctx, Access{
+		ResourceNamespace: resourcePointer.Namespace(),
+		ResourceType:      resourcePointer.Type(),
+		ResourceID:        resourcePointer.ID(),
+
+		Verb: Get,
+	}); err != nil {
+		return nil, err
+	}
+
+	h, ok := filter.state.(HistoryReader)
+	if !ok {
+		return nil, ErrUnsupportedOperation("history")
+	}
+
+	return h.History(ctx, resourcePointer, n, opts...)
+}
+
 // List resources by type.
 func (filter *stateFilter) List(ctx context.Context, resourceKind resource.Kind, opts ...ListOption) (resource.List, error) {
+	var options ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// access rules are checked per namespace
+	if options.NamespacePattern != "" {
+		return resource.List{}, ErrUnsupportedOperation("namespace pattern list")
+	}
+
 	if err := filter.rule(ctx, Access{
 		ResourceNamespace: resourceKind.Namespace(),
 		ResourceType:      resourceKind.Type(),
@@ -165,6 +198,17 @@ func (filter *stateFilter) Watch(ctx context.Context, resourcePointer resource.P
 
 // WatchKind watches resources of specific kind (namespace and type).
 func (filter *stateFilter) WatchKind(ctx context.Context, resourceKind resource.Kind, ch chan<- Event, opts ...WatchKindOption) error {
+	var options WatchKindOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// access rules are checked per namespace
+	if options.NamespacePattern != "" {
+		return ErrUnsupportedOperation("namespace pattern watch")
+	}
+
 	if err := filter.rule(ctx, Access{
 		ResourceNamespace: resourceKind.Namespace(),
 		ResourceType:      resourceKind.Type(),
@@ -179,6 +223,17 @@ func (filter *stateFilter) WatchKind(ctx context.Context, resourceKind resource.
 
 // WatchKindAggregated watches resources of specific kind (namespace and type).
 func (filter *stateFilter) WatchKindAggregated(ctx context.Context, resourceKind resource.Kind, ch chan<- []Event, opts ...WatchKindOption) error {
+	var options WatchKindOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// access rules are checked per namespace
+	if options.NamespacePattern != "" {
+		return ErrUnsupportedOperation("namespace pattern watch")
+	}
+
 	if err := filter.rule(ctx, Access{
 		ResourceNamespace: resourceKind.Namespace(),
 		ResourceType:      resourceKind.Type(),
@@ -101,9 +101,7 @@ func (collection *ResourceCollection) Get(resourceID resource.ID) (resource.Reso
 func (collection *ResourceCollection) List(options *state.ListOptions) (resource.List, error) {
 	collection.mu.Lock()
 
-	result := resource.List{
-		Items: make([]resource.Resource, 0, len(collection.storage)),
-	}
+	candidates := make([]resource.Resource, 0, len(collection.storage))
 
 	for _, res := range collection.storage {
 		if !options.IDQuery.Matches(*res.Metadata()) {
@@ -114,11 +112,25 @@ func (collection *ResourceCollection) List(options *state.ListOptions) (resource
 			continue
 		}
 
-		result.Items = append(result.Items, res.DeepCopy())
+		candidates = append(candidates, res)
 	}
 
 	collection.mu.Unlock()
 
+	// stored resources are never modified (updates replace them), so the filter expression
+	// is evaluated without the lock held
+	result := resource.List{
+		Items: make([]resource.Resource, 0, len(candidates)),
+	}
+
+	for _, res := range candidates {
+		if !resource.MatchesFilter(options.Filter, res) {
+			continue
+		}
+
+		result.Items = append(result.Items, res.DeepCopy())
+	}
+
 	sort.Slice(result.Items, func(i, j int) bool {
 		return result.Items[i].Metadata().ID() < result.Items[j].Metadata().ID()
 	})
@@ -126,6 +138,36 @@ func (collection *ResourceCollection) List(options *state.ListOptions) (resource
 	return result, nil
 }
 
+// History returns up to n most recent versions of a resource, most recent first.
+//
+// Versions are recovered from the event history, so they are available only while the
+// corresponding events are retained in the history buffer.
+func (collection *ResourceCollection) History(resourceID resource.ID, n int) ([]resource.Resource, error) {
+	collection.mu.Lock()
+	defer collection.mu.Unlock()
+
+	var result []resource.Resource
+
+	minPos := max(collection.writePos-int64(collection.capacity), 0)
+
+	for pos := collection.writePos - 1; pos >= minPos && (n <= 0 || len(result) < n); pos-- {
+		event := collection.stream[pos%int64(collection.capacity)]
+
+		// destroyed event carries the same version as the last update
+		if event.Type == state.Destroyed || event.Resource.Metadata().ID() != resourceID {
+			continue
+		}
+
+		result = append(result, event.Resource.DeepCopy())
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNotFound(resource.NewMetadata(collection.ns, collection.typ, resourceID, resource.VersionUndefined))
+	}
+
+	return result, nil
+}
+
 func (collection *ResourceCollection) inject(resource resource.Resource) {
 	collection.storage[resource.Metadata().ID()] = resource
 	collection.publish(state.Event{
@@ -450,10 +492,18 @@ func (collection *ResourceCollection) WatchAll(ctx context.Context, singleCh cha
 		opt(&options)
 	}
 
-	matches := func(res resource.Resource) bool {
+	if options.NamespacePattern != "" {
+		return state.ErrUnsupportedOperation("namespace pattern watch")
+	}
+
+	matchesQueries := func(res resource.Resource) bool {
 		return options.IDQuery.Matches(*res.Metadata()) && options.LabelQueries.Matches(*res.Metadata().Labels())
 	}
 
+	matches := func(res resource.Resource) bool {
+		return matchesQueries(res) && resource.MatchesFilter(options.Filter, res)
+	}
+
 	collection.mu.Lock()
 	defer collection.mu.Unlock()
 
@@ -468,15 +518,12 @@ func (collection *ResourceCollection) WatchAll(ctx context.Context, singleCh cha
 
 		bootstrapList = make([]resource.Resource, 0, len(collection.storage))
 
+		// the filter expression is evaluated once the lock is released (see List)
 		for _, res := range collection.storage {
-			if matches(res) {
-				bootstrapList = append(bootstrapList, res.DeepCopy())
+			if matchesQueries(res) {
+				bootstrapList = append(bootstrapList, res)
 			}
 		}
-
-		sort.Slice(bootstrapList, func(i, j int) bool {
-			return bootstrapList[i].Metadata().ID() < bootstrapList[j].Metadata().ID()
-		})
 	}
 
 	switch {
@@ -519,6 +566,15 @@ func (collection *ResourceCollection) WatchAll(ctx context.Context, singleCh cha
 	go func() {
 		// send initial contents if they were captured
 		if options.BootstrapContents {
+			bootstrapList = xslices.Map(
+				xslices.Filter(bootstrapList, func(res resource.Resource) bool { return resource.MatchesFilter(options.Filter, res) }),
+				resource.Resource.DeepCopy,
+			)
+
+			sort.Slice(bootstrapList, func(i, j int) bool {
+				return bootstrapList[i].Metadata().ID() < bootstrapList[j].Metadata().ID()
+			})
+
 			switch {
 			case singleCh != nil:
 				for _, res := range bootstrapList {
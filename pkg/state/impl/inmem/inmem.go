@@ -16,7 +16,10 @@ import (
 	"github.com/cosi-project/runtime/pkg/state"
 )
 
-var _ state.CoreState = &State{}
+var (
+	_ state.CoreState     = &State{}
+	_ state.HistoryReader = &State{}
+)
 
 // State implements state.CoreState.
 type State struct {
@@ -108,6 +111,17 @@ func (st *State) Get(ctx context.Context, resourcePointer resource.Pointer, _ ..
 	return st.getCollection(resourcePointer.Type()).Get(resourcePointer.ID())
 }
 
+// History returns up to n most recent versions of a resource.
+//
+// History depth is limited by the history capacity of the resource collection (see WithHistoryMaxCapacity).
+func (st *State) History(ctx context.Context, resourcePointer resource.Pointer, n int, _ ...state.HistoryOption) ([]resource.Resource, error) {
+	if err := st.loadStore(ctx); err != nil {
+		return nil, err
+	}
+
+	return st.getCollection(resourcePointer.Type()).History(resourcePointer.ID(), n)
+}
+
 // List resources.
 func (st *State) List(ctx context.Context, resourceKind resource.Kind, opts ...state.ListOption) (resource.List, error) {
 	if err := st.loadStore(ctx); err != nil {
@@ -120,6 +134,10 @@ func (st *State) List(ctx context.Context, resourceKind resource.Kind, opts ...s
 		opt(&options)
 	}
 
+	if options.NamespacePattern != "" {
+		return resource.List{}, state.ErrUnsupportedOperation("namespace pattern list")
+	}
+
 	return st.getCollection(resourceKind.Type()).List(&options)
 }
 
@@ -179,6 +197,10 @@ func (st *State) Watch(ctx context.Context, resourcePointer resource.Pointer, ch
 
 // WatchKind all resources by type.
 func (st *State) WatchKind(ctx context.Context, resourceKind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	if err := checkWatchKindOptions(opts); err != nil {
+		return err
+	}
+
 	if err := st.loadStore(ctx); err != nil {
 		return err
 	}
@@ -188,9 +210,28 @@ func (st *State) WatchKind(ctx context.Context, resourceKind resource.Kind, ch c
 
 // WatchKindAggregated all resources by type.
 func (st *State) WatchKindAggregated(ctx context.Context, resourceKind resource.Kind, ch chan<- []state.Event, opts ...state.WatchKindOption) error {
+	if err := checkWatchKindOptions(opts); err != nil {
+		return err
+	}
+
 	if err := st.loadStore(ctx); err != nil {
 		return err
 	}
 
 	return st.getCollection(resourceKind.Type()).WatchAll(ctx, nil, ch, opts...)
 }
+
+// checkWatchKindOptions rejects the watch options which are not supported by the inmem state.
+func checkWatchKindOptions(opts []state.WatchKindOption) error {
+	var options state.WatchKindOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.NamespacePattern != "" {
+		return state.ErrUnsupportedOperation("namespace pattern watch")
+	}
+
+	return nil
+}
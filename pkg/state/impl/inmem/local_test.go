@@ -231,3 +231,21 @@ func TestWatchInvalidBookmark(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, state.IsInvalidWatchBookmarkError(err))
 }
+
+func TestNamespacePatternUnsupported(t *testing.T) {
+	t.Parallel()
+
+	const namespace = "default"
+
+	st := state.WrapCore(inmem.NewState(namespace))
+
+	ctx := t.Context()
+
+	kind := resource.NewMetadata(namespace, conformance.PathResourceType, "", resource.VersionUndefined)
+
+	_, err := st.List(ctx, kind, state.WithListNamespacePattern("*"))
+	require.Error(t, err)
+
+	require.Error(t, st.WatchKind(ctx, kind, make(chan state.Event), state.WithNamespacePattern("*")))
+	require.Error(t, st.WatchKindAggregated(ctx, kind, make(chan []state.Event), state.WithNamespacePattern("*")))
+}
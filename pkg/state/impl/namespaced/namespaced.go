@@ -7,7 +7,9 @@ package namespaced
 
 import (
 	"context"
+	"sync"
 
+	"github.com/siderolabs/gen/channel"
 	"github.com/siderolabs/gen/concurrent"
 
 	"github.com/cosi-project/runtime/pkg/resource"
@@ -17,20 +19,31 @@ import (
 // StateBuilder builds state by namespace.
 type StateBuilder func(resource.Namespace) state.CoreState
 
-var _ state.CoreState = (*State)(nil)
+var (
+	_ state.CoreState     = (*State)(nil)
+	_ state.HistoryReader = (*State)(nil)
+)
 
 // State implements delegating State for each namespace.
+//
+// WatchKind and WatchKindAggregated support watching across namespaces (see state.WithNamespacePattern),
+// and List supports listing across namespaces (see state.WithListNamespacePattern).
 type State struct {
 	builder StateBuilder
 
 	namespaces *concurrent.HashTrieMap[resource.Namespace, state.CoreState]
+
+	// mu serializes creation of the namespaces and registration of the pattern watches
+	mu             sync.Mutex
+	patternWatches map[*patternWatch]struct{}
 }
 
 // NewState initializes new namespaced State.
 func NewState(builder StateBuilder) *State {
 	return &State{
-		builder:    builder,
-		namespaces: concurrent.NewHashTrieMap[resource.Namespace, state.CoreState](),
+		builder:        builder,
+		namespaces:     concurrent.NewHashTrieMap[resource.Namespace, state.CoreState](),
+		patternWatches: map[*patternWatch]struct{}{},
 	}
 }
 
@@ -39,7 +52,27 @@ func (st *State) getNamespace(ns resource.Namespace) state.CoreState { //nolint:
 		return s
 	}
 
-	s, _ := st.namespaces.LoadOrStore(ns, st.builder(ns))
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if s, ok := st.namespaces.Load(ns); ok {
+		return s
+	}
+
+	s := st.builder(ns)
+
+	// watch the new namespace before it is visible, so that no events are missed
+	for w := range st.patternWatches {
+		if !w.matches(ns) {
+			continue
+		}
+
+		if err := w.watchNamespace(ns, s, w.newNamespaceOpts...); err != nil {
+			w.fail(err)
+		}
+	}
+
+	st.namespaces.Store(ns, s)
 
 	return s
 }
@@ -51,8 +84,30 @@ func (st *State) Get(ctx context.Context, ptr resource.Pointer, opts ...state.Ge
 	return st.getNamespace(ptr.Namespace()).Get(ctx, ptr, opts...)
 }
 
+// History returns up to n most recent versions of a resource, most recent first.
+//
+// If the namespace state doesn't support history, unsupported error is returned.
+func (st *State) History(ctx context.Context, ptr resource.Pointer, n int, opts ...state.HistoryOption) ([]resource.Resource, error) {
+	h, ok := st.getNamespace(ptr.Namespace()).(state.HistoryReader)
+	if !ok {
+		return nil, state.ErrUnsupportedOperation("history")
+	}
+
+	return h.History(ctx, ptr, n, opts...)
+}
+
 // List resources by kind.
 func (st *State) List(ctx context.Context, kind resource.Kind, opts ...state.ListOption) (resource.List, error) {
+	var options state.ListOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.NamespacePattern != "" {
+		return st.listPattern(ctx, kind, options.NamespacePattern, opts)
+	}
+
 	return st.getNamespace(kind.Namespace()).List(ctx, kind, opts...)
 }
 
@@ -91,10 +146,28 @@ func (st *State) Watch(ctx context.Context, ptr resource.Pointer, ch chan<- stat
 
 // WatchKind watches resources of specific kind (namespace and type).
 func (st *State) WatchKind(ctx context.Context, kind resource.Kind, ch chan<- state.Event, opts ...state.WatchKindOption) error {
+	if options := watchKindOptions(opts); options.NamespacePattern != "" {
+		return st.watchPattern(ctx, kind, options, opts, func(ctx context.Context, events []state.Event) bool {
+			for _, e := range events {
+				if !channel.SendWithContext(ctx, ch, e) {
+					return false
+				}
+			}
+
+			return true
+		})
+	}
+
 	return st.getNamespace(kind.Namespace()).WatchKind(ctx, kind, ch, opts...)
 }
 
 // WatchKindAggregated watches resources of specific kind (namespace and type).
 func (st *State) WatchKindAggregated(ctx context.Context, kind resource.Kind, ch chan<- []state.Event, opts ...state.WatchKindOption) error {
+	if options := watchKindOptions(opts); options.NamespacePattern != "" {
+		return st.watchPattern(ctx, kind, options, opts, func(ctx context.Context, events []state.Event) bool {
+			return channel.SendWithContext(ctx, ch, events)
+		})
+	}
+
 	return st.getNamespace(kind.Namespace()).WatchKindAggregated(ctx, kind, ch, opts...)
 }
@@ -5,8 +5,11 @@
 package namespaced_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/goleak"
 
@@ -27,3 +30,107 @@ func TestNamespacedConformance(t *testing.T) {
 		Namespaces: []resource.Namespace{"default", "controller", "system", "runtime"},
 	})
 }
+
+func TestNamespacePatternWatch(t *testing.T) {
+	t.Parallel()
+
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("tenant-a", "existing")))
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("other", "ignored")))
+
+	kind := resource.NewMetadata("", conformance.PathResourceType, "", resource.VersionUndefined)
+
+	ch := make(chan []state.Event)
+
+	require.NoError(t, st.WatchKindAggregated(ctx, kind, ch, state.WithNamespacePattern("tenant-*"), state.WithBootstrapContents(true)))
+
+	var bootstrap []state.Event
+
+	for {
+		events := <-ch
+		bootstrap = append(bootstrap, events...)
+
+		if bootstrap[len(bootstrap)-1].Type == state.Bootstrapped {
+			break
+		}
+	}
+
+	require.Len(t, bootstrap, 2)
+	assert.Equal(t, state.Created, bootstrap[0].Type)
+	assert.Equal(t, "tenant-a", bootstrap[0].Resource.Metadata().Namespace())
+	assert.Equal(t, "tenant-*", bootstrap[1].Resource.Metadata().Namespace())
+
+	// namespace created after the watch is established
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("other", "ignored-too")))
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("tenant-b", "new")))
+
+	events := <-ch
+	require.Len(t, events, 1)
+	assert.Equal(t, state.Created, events[0].Type)
+	assert.Equal(t, "tenant-b", events[0].Resource.Metadata().Namespace())
+	assert.Nil(t, events[0].Bookmark)
+
+	require.NoError(t, st.Destroy(ctx, conformance.NewPathResource("tenant-a", "existing").Metadata()))
+
+	events = <-ch
+	require.Len(t, events, 1)
+	assert.Equal(t, state.Destroyed, events[0].Type)
+	assert.Equal(t, "tenant-a", events[0].Resource.Metadata().Namespace())
+
+	// single event watch
+	singleCh := make(chan state.Event)
+
+	require.NoError(t, st.WatchKind(ctx, kind, singleCh, state.WithNamespacePattern("*")))
+	require.NoError(t, st.Create(ctx, conformance.NewPathResource("tenant-c", "new")))
+
+	event := <-singleCh
+	assert.Equal(t, state.Created, event.Type)
+	assert.Equal(t, "tenant-c", event.Resource.Metadata().Namespace())
+
+	require.Error(t, st.WatchKind(ctx, kind, singleCh, state.WithNamespacePattern("[")))
+
+	cancel()
+}
+
+func TestNamespacePatternList(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	st := state.WrapCore(namespaced.NewState(inmem.Build))
+
+	for _, r := range []*conformance.PathResource{
+		conformance.NewPathResource("tenant-b", "1"),
+		conformance.NewPathResource("tenant-a", "2"),
+		conformance.NewPathResource("tenant-a", "1"),
+		conformance.NewPathResource("other", "1"),
+	} {
+		require.NoError(t, st.Create(ctx, r))
+	}
+
+	kind := resource.NewMetadata("", conformance.PathResourceType, "", resource.VersionUndefined)
+
+	list, err := st.List(ctx, kind, state.WithListNamespacePattern("tenant-*"))
+	require.NoError(t, err)
+
+	var ids []string
+
+	for _, r := range list.Items {
+		ids = append(ids, r.Metadata().Namespace()+"/"+r.Metadata().ID())
+	}
+
+	assert.Equal(t, []string{"tenant-a/1", "tenant-a/2", "tenant-b/1"}, ids)
+
+	list, err = st.List(ctx, kind, state.WithListNamespacePattern("none-*"))
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+
+	_, err = st.List(ctx, kind, state.WithListNamespacePattern("["))
+	require.Error(t, err)
+}
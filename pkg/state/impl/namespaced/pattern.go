@@ -0,0 +1,234 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package namespaced
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/siderolabs/gen/channel"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/state"
+)
+
+// patternWatch is a watch of the resource type across the namespaces matching the pattern.
+//
+// Each matching namespace is watched separately, and the events are merged into a single stream.
+type patternWatch struct {
+	ctx context.Context //nolint:containedctx
+
+	ch chan []state.Event
+
+	typ     resource.Type
+	pattern string
+
+	// options of the watches of the namespaces created after the watch is established
+	newNamespaceOpts []state.WatchKindOption
+}
+
+func (w *patternWatch) matches(ns resource.Namespace) bool {
+	matched, _ := path.Match(w.pattern, ns) //nolint:errcheck // pattern is validated when the watch is established
+
+	return matched
+}
+
+func (w *patternWatch) watchNamespace(ns resource.Namespace, st state.CoreState, opts ...state.WatchKindOption) error {
+	return st.WatchKindAggregated(w.ctx, resource.NewMetadata(ns, w.typ, "", resource.VersionUndefined), w.ch, opts...)
+}
+
+// fail the watch with the error.
+func (w *patternWatch) fail(err error) {
+	go channel.SendWithContext(w.ctx, w.ch, []state.Event{{Type: state.Errored, Error: err}})
+}
+
+func (w *patternWatch) bootstrappedEvent() state.Event {
+	return state.Event{
+		Type:     state.Bootstrapped,
+		Resource: resource.NewTombstone(resource.NewMetadata(w.pattern, w.typ, "", resource.VersionUndefined)),
+	}
+}
+
+// merge the events of the namespace watches, and send them until the watch is canceled or failed.
+//
+// While bootstrapping, the contents of the namespaces in pending are sent as they come, while
+// other events are held until all pending namespaces are bootstrapped, and a single Bootstrapped event is sent.
+func (w *patternWatch) merge(bootstrapping bool, pending map[resource.Namespace]struct{}, send func([]state.Event) bool) {
+	var held []state.Event
+
+	if bootstrapping && len(pending) == 0 {
+		if !send([]state.Event{w.bootstrappedEvent()}) {
+			return
+		}
+
+		bootstrapping = false
+	}
+
+	for {
+		var events []state.Event
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case events = <-w.ch:
+		}
+
+		out := make([]state.Event, 0, len(events))
+
+		for _, e := range events {
+			// bookmarks of the namespace watches can't be used to resume the pattern watch
+			e.Bookmark = nil
+
+			switch {
+			case e.Type == state.Errored:
+				send(append(out, e))
+
+				return
+			case e.Type == state.Noop:
+				continue
+			case e.Type == state.Bootstrapped:
+				delete(pending, e.Resource.Metadata().Namespace())
+
+				if bootstrapping && len(pending) == 0 {
+					bootstrapping = false
+
+					out = append(out, w.bootstrappedEvent())
+					out = append(out, held...)
+					held = nil
+				}
+			case bootstrapping:
+				if _, ok := pending[e.Resource.Metadata().Namespace()]; ok {
+					out = append(out, e)
+				} else {
+					held = append(held, e)
+				}
+			default:
+				out = append(out, e)
+			}
+		}
+
+		if len(out) > 0 && !send(out) {
+			return
+		}
+	}
+}
+
+func watchKindOptions(opts []state.WatchKindOption) state.WatchKindOptions {
+	var options state.WatchKindOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// watchPattern establishes the watch of the kind type across the namespaces matching the pattern.
+func (st *State) watchPattern(ctx context.Context, kind resource.Kind, options state.WatchKindOptions, opts []state.WatchKindOption, send func(context.Context, []state.Event) bool) error {
+	if options.StartFromBookmark != nil {
+		return fmt.Errorf("cannot use StartFromBookmark with the namespace pattern")
+	}
+
+	if _, err := path.Match(options.NamespacePattern, ""); err != nil {
+		return fmt.Errorf("invalid namespace pattern %q: %w", options.NamespacePattern, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// namespaces are watched without the pattern and the bootstrap bookmark
+	namespaceOpts := append(slices.Clone(opts), state.WithNamespacePattern(""), state.WithBootstrapBookmark(false))
+
+	w := &patternWatch{
+		ctx:     ctx,
+		ch:      make(chan []state.Event),
+		typ:     kind.Type(),
+		pattern: options.NamespacePattern,
+		// new namespaces are empty, so there are no contents to bootstrap
+		newNamespaceOpts: append(slices.Clone(namespaceOpts), state.WithBootstrapContents(false)),
+	}
+
+	pending := map[resource.Namespace]struct{}{}
+
+	var err error
+
+	st.mu.Lock()
+
+	for ns, nsState := range st.namespaces.Range {
+		if !w.matches(ns) {
+			continue
+		}
+
+		if err = w.watchNamespace(ns, nsState, namespaceOpts...); err != nil {
+			break
+		}
+
+		if options.BootstrapContents {
+			pending[ns] = struct{}{}
+		}
+	}
+
+	if err == nil {
+		st.patternWatches[w] = struct{}{}
+	}
+
+	st.mu.Unlock()
+
+	if err != nil {
+		cancel()
+
+		return err
+	}
+
+	go func() {
+		defer cancel()
+
+		defer func() {
+			st.mu.Lock()
+			delete(st.patternWatches, w)
+			st.mu.Unlock()
+		}()
+
+		w.merge(options.BootstrapContents, pending, func(events []state.Event) bool {
+			return send(ctx, events)
+		})
+	}()
+
+	return nil
+}
+
+// listPattern lists the resources of the kind type across the namespaces matching the pattern.
+func (st *State) listPattern(ctx context.Context, kind resource.Kind, pattern string, opts []state.ListOption) (resource.List, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return resource.List{}, fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+	}
+
+	var namespaces []resource.Namespace
+
+	for ns := range st.namespaces.Range {
+		if matched, _ := path.Match(pattern, ns); matched { //nolint:errcheck // pattern is validated above
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	slices.Sort(namespaces)
+
+	// namespaces are listed without the pattern
+	namespaceOpts := append(slices.Clone(opts), state.WithListNamespacePattern(""))
+
+	var list resource.List
+
+	for _, ns := range namespaces {
+		nsList, err := st.getNamespace(ns).List(ctx, resource.NewMetadata(ns, kind.Type(), "", resource.VersionUndefined), namespaceOpts...)
+		if err != nil {
+			return resource.List{}, err
+		}
+
+		list.Items = append(list.Items, nsList.Items...)
+	}
+
+	return list, nil
+}
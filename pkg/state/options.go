@@ -26,8 +26,10 @@ func WithGetUnmarshalOptions(opt ...UnmarshalOption) GetOption {
 // ListOptions for the CoreState.List function.
 type ListOptions struct {
 	IDQuery          resource.IDQuery
+	Filter           resource.FilterExpression
 	LabelQueries     resource.LabelQueries
 	UnmarshalOptions UnmarshalOptions
+	NamespacePattern string
 }
 
 // ListOption builds ListOptions.
@@ -55,6 +57,27 @@ func WithIDQuery(opt ...resource.IDQueryOption) ListOption {
 	}
 }
 
+// WithFilter sets a filter expression for the list options (see package filter).
+//
+// Filter is applied in addition to the ID and label queries.
+func WithFilter(expr resource.FilterExpression) ListOption {
+	return func(opts *ListOptions) {
+		opts.Filter = expr
+	}
+}
+
+// WithListNamespacePattern lists resources of the kind type across all namespaces matching the pattern.
+//
+// Pattern syntax is the one of path.Match, "*" matches all namespaces, the namespace of the kind is ignored.
+// Resources are sorted by namespace, and by ID within the namespace.
+//
+// Namespace patterns are supported by namespaced.State, other implementations return an error.
+func WithListNamespacePattern(pattern string) ListOption {
+	return func(opts *ListOptions) {
+		opts.NamespacePattern = pattern
+	}
+}
+
 // WithListUnmarshalOptions sets unmarshal options for List API.
 func WithListUnmarshalOptions(opt ...UnmarshalOption) ListOption {
 	return func(opts *ListOptions) {
@@ -174,6 +197,23 @@ func WithTeardownAndDestroyOwner(owner string) TeardownAndDestroyOption {
 	}
 }
 
+// HistoryOptions for the HistoryReader.History function.
+type HistoryOptions struct {
+	UnmarshalOptions UnmarshalOptions
+}
+
+// HistoryOption builds HistoryOptions.
+type HistoryOption func(*HistoryOptions)
+
+// WithHistoryUnmarshalOptions sets unmarshal options for History API.
+func WithHistoryUnmarshalOptions(opt ...UnmarshalOption) HistoryOption {
+	return func(opts *HistoryOptions) {
+		for _, o := range opt {
+			o(&opts.UnmarshalOptions)
+		}
+	}
+}
+
 // WatchOptions for the CoreState.Watch function.
 type WatchOptions struct {
 	StartFromBookmark Bookmark
@@ -210,9 +250,11 @@ func WithStartFromBookmark(bookmark Bookmark) WatchOption {
 // WatchKindOptions for the CoreState.WatchKind function.
 type WatchKindOptions struct {
 	IDQuery           resource.IDQuery
+	Filter            resource.FilterExpression
 	LabelQueries      resource.LabelQueries
 	StartFromBookmark Bookmark
 	UnmarshalOptions  UnmarshalOptions
+	NamespacePattern  string
 	BootstrapContents bool
 	BootstrapBookmark bool
 	TailEvents        int
@@ -249,6 +291,21 @@ func WithKindStartFromBookmark(bookmark Bookmark) WatchKindOption {
 	}
 }
 
+// WithNamespacePattern watches resources of the kind type across all namespaces matching the pattern.
+//
+// Pattern syntax is the one of path.Match, "*" matches all namespaces, the namespace of the kind is ignored.
+// The watch covers the namespaces which are created after the watch is established.
+// With BootstrapContents, a single Bootstrapped event is sent once the contents of all namespaces are sent,
+// its resource namespace is set to the pattern.
+//
+// Events of the watch don't carry bookmarks, so StartFromBookmark can't be used.
+// Namespace patterns are supported by namespaced.State, other implementations return an error.
+func WithNamespacePattern(pattern string) WatchKindOption {
+	return func(opts *WatchKindOptions) {
+		opts.NamespacePattern = pattern
+	}
+}
+
 // WithWatchKindUnmarshalOptions sets unmarshal options for WatchKind API.
 func WithWatchKindUnmarshalOptions(opt ...UnmarshalOption) WatchKindOption {
 	return func(opts *WatchKindOptions) {
@@ -280,6 +337,15 @@ func WatchWithIDQuery(opt ...resource.IDQueryOption) WatchKindOption {
 	}
 }
 
+// WatchWithFilter sets a filter expression for the watch options (see package filter).
+//
+// Filter is applied in addition to the ID and label queries.
+func WatchWithFilter(expr resource.FilterExpression) WatchKindOption {
+	return func(opts *WatchKindOptions) {
+		opts.Filter = expr
+	}
+}
+
 // UnmarshalOptions control resources marshaling/unmarshaling.
 type UnmarshalOptions struct {
 	SkipProtobufUnmarshal bool
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sync/atomic"
 	"time"
 
@@ -29,6 +30,7 @@ var (
 	_ state.CoreState            = (*Adapter)(nil)
 	_ state.Teardowner           = (*Adapter)(nil)
 	_ state.TeardownAndDestroyer = (*Adapter)(nil)
+	_ state.HistoryReader        = (*Adapter)(nil)
 )
 
 // Adapter implement state.CoreState from the gRPC State client.
@@ -122,6 +124,10 @@ func (adapter *Adapter) List(ctx context.Context, resourceKind resource.Kind, op
 		o(&opts)
 	}
 
+	if opts.NamespacePattern != "" {
+		return resource.List{}, state.ErrUnsupportedOperation("namespace pattern list")
+	}
+
 	labelQueries := make([]*v1alpha1.LabelQuery, 0, len(opts.LabelQueries))
 
 	for _, query := range opts.LabelQueries {
@@ -137,8 +143,9 @@ func (adapter *Adapter) List(ctx context.Context, resourceKind resource.Kind, op
 		Namespace: resourceKind.Namespace(),
 		Type:      resourceKind.Type(),
 		Options: &v1alpha1.ListOptions{
-			LabelQuery: labelQueries,
-			IdQuery:    transformIDQuery(opts.IDQuery),
+			LabelQuery:  labelQueries,
+			IdQuery:     transformIDQuery(opts.IDQuery),
+			FilterQuery: transformFilterQuery(opts.Filter),
 		},
 	})
 	if err != nil {
@@ -414,6 +421,63 @@ func (adapter *Adapter) TeardownAndDestroy(ctx context.Context, resourcePointer
 	return nil
 }
 
+// History returns up to n most recent versions of a resource, most recent first.
+//
+// Implements [state.HistoryReader]. If the server does not implement the History RPC,
+// unsupported error is returned.
+func (adapter *Adapter) History(ctx context.Context, resourcePointer resource.Pointer, n int, opt ...state.HistoryOption) ([]resource.Resource, error) {
+	opts := state.HistoryOptions{}
+
+	for _, o := range opt {
+		o(&opts)
+	}
+
+	resp, err := adapter.client.History(ctx, &v1alpha1.HistoryRequest{
+		Namespace: resourcePointer.Namespace(),
+		Type:      resourcePointer.Type(),
+		Id:        resourcePointer.ID(),
+
+		Options: &v1alpha1.HistoryOptions{
+			// n <= 0 requests all versions, so the depth is clamped to the int32 range without changing the meaning
+			Depth: int32(min(max(n, 0), math.MaxInt32)),
+		},
+	})
+	if err != nil {
+		switch status.Code(err) { //nolint:exhaustive
+		case codes.NotFound:
+			return nil, eNotFound{err}
+		case codes.Unimplemented:
+			return nil, eUnsupported{err}
+		default:
+			return nil, err
+		}
+	}
+
+	result := make([]resource.Resource, 0, len(resp.GetResources()))
+
+	for _, protoR := range resp.GetResources() {
+		unmarshaled, err := protobuf.Unmarshal(protoR)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.UnmarshalOptions.SkipProtobufUnmarshal {
+			result = append(result, unmarshaled)
+
+			continue
+		}
+
+		r, err := protobuf.UnmarshalResource(unmarshaled)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
 // adapterCoreView wraps an Adapter so the result satisfies state.CoreState
 // but neither state.Teardowner nor state.TeardownAndDestroyer. The teardown
 // fallbacks use this to drive the default paths in coreWrapper without
@@ -488,6 +552,10 @@ func (adapter *Adapter) WatchKind(ctx context.Context, resourceKind resource.Kin
 		o(&opts)
 	}
 
+	if opts.NamespacePattern != "" {
+		return state.ErrUnsupportedOperation("namespace pattern watch")
+	}
+
 	labelQueries := make([]*v1alpha1.LabelQuery, 0, len(opts.LabelQueries))
 
 	for _, query := range opts.LabelQueries {
@@ -509,6 +577,7 @@ func (adapter *Adapter) WatchKind(ctx context.Context, resourceKind resource.Kin
 			TailEvents:        int32(opts.TailEvents),
 			LabelQuery:        labelQueries,
 			IdQuery:           transformIDQuery(opts.IDQuery),
+			FilterQuery:       transformFilterQuery(opts.Filter),
 		},
 		ApiVersion: 1,
 	}
@@ -542,6 +611,10 @@ func (adapter *Adapter) WatchKindAggregated(ctx context.Context, resourceKind re
 		o(&opts)
 	}
 
+	if opts.NamespacePattern != "" {
+		return state.ErrUnsupportedOperation("namespace pattern watch")
+	}
+
 	labelQueries := make([]*v1alpha1.LabelQuery, 0, len(opts.LabelQueries))
 
 	for _, query := range opts.LabelQueries {
@@ -563,6 +636,7 @@ func (adapter *Adapter) WatchKindAggregated(ctx context.Context, resourceKind re
 			TailEvents:        int32(opts.TailEvents),
 			LabelQuery:        labelQueries,
 			IdQuery:           transformIDQuery(opts.IDQuery),
+			FilterQuery:       transformFilterQuery(opts.Filter),
 			Aggregated:        true,
 		},
 		ApiVersion: 1,
@@ -13,6 +13,13 @@ type eNotFound struct {
 
 func (eNotFound) NotFoundError() {}
 
+//nolint:errname
+type eUnsupported struct {
+	error
+}
+
+func (eUnsupported) UnsupportedError() {}
+
 //nolint:errname
 type eConflict struct {
 	error
@@ -0,0 +1,20 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package client
+
+import (
+	"github.com/cosi-project/runtime/api/v1alpha1"
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+func transformFilterQuery(input resource.FilterExpression) *v1alpha1.FilterQuery {
+	if input == nil || input.String() == "" {
+		return nil
+	}
+
+	return &v1alpha1.FilterQuery{
+		Expression: input.String(),
+	}
+}
@@ -66,6 +66,55 @@ func transformLabelQuery(input resource.LabelQuery) (*v1alpha1.LabelQuery, error
 				Op:     v1alpha1.LabelTerm_LTE_NUMERIC,
 				Invert: term.Invert,
 			})
+		case resource.LabelOpGT:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_GT,
+				Invert: term.Invert,
+			})
+		case resource.LabelOpGTE:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_GTE,
+				Invert: term.Invert,
+			})
+		case resource.LabelOpGTNumeric:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_GT_NUMERIC,
+				Invert: term.Invert,
+			})
+		case resource.LabelOpGTENumeric:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_GTE_NUMERIC,
+				Invert: term.Invert,
+			})
+		case resource.LabelOpSemver:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_SEMVER,
+				Invert: term.Invert,
+			})
+		case resource.LabelOpPrefix:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_PREFIX,
+				Invert: term.Invert,
+			})
+		case resource.LabelOpRegexp:
+			labelQuery.Terms = append(labelQuery.Terms, &v1alpha1.LabelTerm{
+				Key:    term.Key,
+				Value:  term.Value,
+				Op:     v1alpha1.LabelTerm_REGEXP,
+				Invert: term.Invert,
+			})
 		default:
 			return nil, fmt.Errorf("unsupported label query operator: %v", term.Op)
 		}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net"
 	"os"
@@ -22,7 +23,9 @@ import (
 	"go.uber.org/zap/zaptest"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/future"
@@ -314,6 +317,83 @@ func TestProtobufTeardownRoundtrip(t *testing.T) {
 	assert.True(t, state.IsNotFoundError(err))
 }
 
+func TestProtobufListLabelSelector(t *testing.T) {
+	grpcConn, _, _, coreState := ProtobufSetup(t) //nolint:dogsled
+
+	stateClient := v1alpha1.NewStateClient(grpcConn)
+
+	for i, tier := range []string{"gold", "silver", "bronze", "gold"} {
+		r := conformance.NewPathResource("default", fmt.Sprintf("/selector/%d", i))
+		r.Metadata().Labels().Set("tier", tier)
+
+		if i == 3 {
+			r.Metadata().Labels().Set("legacy", "")
+		}
+
+		require.NoError(t, coreState.Create(t.Context(), r))
+	}
+
+	list := func(query *v1alpha1.LabelQuery) ([]string, error) {
+		cli, err := stateClient.List(t.Context(), &v1alpha1.ListRequest{
+			Namespace: "default",
+			Type:      conformance.PathResourceType,
+			Options: &v1alpha1.ListOptions{
+				LabelQuery: []*v1alpha1.LabelQuery{query},
+			},
+		})
+		require.NoError(t, err)
+
+		var ids []string
+
+		for {
+			resp, err := cli.Recv()
+			if errors.Is(err, io.EOF) {
+				return ids, nil
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			ids = append(ids, resp.GetResource().GetMetadata().GetId())
+		}
+	}
+
+	ids, err := list(&v1alpha1.LabelQuery{Selector: "tier in (gold,silver),!legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/selector/0", "/selector/1"}, ids)
+
+	// terms and selector are combined with AND
+	ids, err = list(&v1alpha1.LabelQuery{
+		Terms: []*v1alpha1.LabelTerm{
+			{Key: "tier", Op: v1alpha1.LabelTerm_EQUAL, Value: []string{"gold"}},
+		},
+		Selector: "!legacy",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/selector/0"}, ids)
+
+	_, err = list(&v1alpha1.LabelQuery{Selector: "tier in gold"})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = list(&v1alpha1.LabelQuery{
+		Terms: []*v1alpha1.LabelTerm{
+			{Key: "tier", Op: v1alpha1.LabelTerm_REGEXP, Value: []string{"gold("}},
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = list(&v1alpha1.LabelQuery{
+		Terms: []*v1alpha1.LabelTerm{
+			{Key: "tier", Op: v1alpha1.LabelTerm_PREFIX},
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 // teardownUnimplementedServer embeds the standard server but returns
 // Unimplemented for the Teardown RPC, simulating an old server.
 type teardownUnimplementedServer struct {
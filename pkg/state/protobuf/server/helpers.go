@@ -6,12 +6,16 @@ package server
 
 import (
 	"regexp"
+	"strings"
 
+	"github.com/siderolabs/gen/xslices"
+	"go.yaml.in/yaml/v4"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/filter"
 )
 
 // ConvertLabelQuery converts protobuf representation of LabelQuery to state representation.
@@ -25,6 +29,14 @@ func ConvertLabelQuery(terms []*v1alpha1.LabelTerm) ([]resource.LabelQueryOption
 			opts = append(opts, resource.NotMatches)
 		}
 
+		switch term.Op { //nolint:exhaustive
+		case v1alpha1.LabelTerm_GT, v1alpha1.LabelTerm_GTE, v1alpha1.LabelTerm_GT_NUMERIC, v1alpha1.LabelTerm_GTE_NUMERIC,
+			v1alpha1.LabelTerm_SEMVER, v1alpha1.LabelTerm_PREFIX, v1alpha1.LabelTerm_REGEXP:
+			if len(term.Value) == 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "label query operator %v on %q requires a value", term.Op, term.Key)
+			}
+		}
+
 		switch term.Op {
 		case v1alpha1.LabelTerm_EQUAL:
 			labelOpts = append(labelOpts, resource.LabelEqual(term.Key, term.Value[0], opts...))
@@ -42,6 +54,25 @@ func ConvertLabelQuery(terms []*v1alpha1.LabelTerm) ([]resource.LabelQueryOption
 			labelOpts = append(labelOpts, resource.LabelLTNumeric(term.Key, term.Value[0], opts...))
 		case v1alpha1.LabelTerm_LTE_NUMERIC:
 			labelOpts = append(labelOpts, resource.LabelLTENumeric(term.Key, term.Value[0], opts...))
+		case v1alpha1.LabelTerm_GT:
+			labelOpts = append(labelOpts, resource.LabelGT(term.Key, term.Value[0], opts...))
+		case v1alpha1.LabelTerm_GTE:
+			labelOpts = append(labelOpts, resource.LabelGTE(term.Key, term.Value[0], opts...))
+		case v1alpha1.LabelTerm_GT_NUMERIC:
+			labelOpts = append(labelOpts, resource.LabelGTNumeric(term.Key, term.Value[0], opts...))
+		case v1alpha1.LabelTerm_GTE_NUMERIC:
+			labelOpts = append(labelOpts, resource.LabelGTENumeric(term.Key, term.Value[0], opts...))
+		case v1alpha1.LabelTerm_SEMVER:
+			labelOpts = append(labelOpts, resource.LabelSemver(term.Key, term.Value, opts...))
+		case v1alpha1.LabelTerm_PREFIX:
+			labelOpts = append(labelOpts, resource.LabelPrefix(term.Key, term.Value[0], opts...))
+		case v1alpha1.LabelTerm_REGEXP:
+			re, err := regexp.Compile(term.Value[0])
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "failed to compile label regexp: %v", err)
+			}
+
+			labelOpts = append(labelOpts, resource.LabelRegexp(term.Key, re, opts...))
 		default:
 			return nil, status.Errorf(codes.Unimplemented, "unsupported label query operator: %v", term.Op)
 		}
@@ -50,6 +81,77 @@ func ConvertLabelQuery(terms []*v1alpha1.LabelTerm) ([]resource.LabelQueryOption
 	return labelOpts, nil
 }
 
+// ConvertLabelSelector converts the label selector of the protobuf LabelQuery to state representation.
+func ConvertLabelSelector(selector string) ([]resource.LabelQueryOption, error) {
+	if selector == "" {
+		return nil, nil
+	}
+
+	query, err := resource.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse label selector: %v", err)
+	}
+
+	return xslices.Map(query.Terms, func(term resource.LabelTerm) resource.LabelQueryOption {
+		return func(q *resource.LabelQuery) {
+			q.Terms = append(q.Terms, term)
+		}
+	}), nil
+}
+
+func marshalDiff(oldRes, newRes resource.Resource) (*v1alpha1.ResourceDiff, error) {
+	entries, err := resource.Diff(oldRes, newRes)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &v1alpha1.ResourceDiff{
+		OldVersion: oldRes.Metadata().Version().String(),
+		NewVersion: newRes.Metadata().Version().String(),
+		Entries:    make([]*v1alpha1.DiffEntry, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		protoEntry := &v1alpha1.DiffEntry{
+			Path: entry.Path,
+		}
+
+		switch entry.Op {
+		case resource.DiffAdded:
+			protoEntry.Op = v1alpha1.DiffEntry_ADDED
+		case resource.DiffRemoved:
+			protoEntry.Op = v1alpha1.DiffEntry_REMOVED
+		case resource.DiffChanged:
+			protoEntry.Op = v1alpha1.DiffEntry_CHANGED
+		}
+
+		if entry.Op != resource.DiffAdded {
+			if protoEntry.OldValue, err = marshalDiffValue(entry.Old); err != nil {
+				return nil, err
+			}
+		}
+
+		if entry.Op != resource.DiffRemoved {
+			if protoEntry.NewValue, err = marshalDiffValue(entry.New); err != nil {
+				return nil, err
+			}
+		}
+
+		diff.Entries = append(diff.Entries, protoEntry)
+	}
+
+	return diff, nil
+}
+
+func marshalDiffValue(v any) (*string, error) {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(strings.TrimSuffix(string(out), "\n")), nil
+}
+
 // ConvertIDQuery converts protobuf representation of IDQuery to state representation.
 func ConvertIDQuery(input *v1alpha1.IDQuery) ([]resource.IDQueryOption, error) {
 	if input == nil || input.Regexp == "" {
@@ -63,3 +165,17 @@ func ConvertIDQuery(input *v1alpha1.IDQuery) ([]resource.IDQueryOption, error) {
 
 	return []resource.IDQueryOption{resource.IDRegexpMatch(re)}, nil
 }
+
+// ConvertFilterQuery converts protobuf representation of FilterQuery to the filter expression.
+func ConvertFilterQuery(input *v1alpha1.FilterQuery) (*filter.Expression, error) {
+	if input == nil || input.Expression == "" {
+		return nil, nil
+	}
+
+	expr, err := filter.Compile(input.Expression)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to compile filter expression: %v", err)
+	}
+
+	return expr, nil
+}
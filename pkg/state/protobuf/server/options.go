@@ -0,0 +1,25 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import "github.com/cosi-project/runtime/pkg/tracing"
+
+// StateOptions configure the gRPC State service.
+type StateOptions struct {
+	TraceRegistry *tracing.Registry
+}
+
+// StateOption applies settings to StateOptions.
+type StateOption func(options *StateOptions)
+
+// WithTraceRegistry records the trace context of Create and Update calls in the registry.
+//
+// The registry should be shared with the controller runtime (see options.WithTraceRegistry),
+// so that the controllers which react to the change link their spans to the RPC span.
+func WithTraceRegistry(registry *tracing.Registry) StateOption {
+	return func(options *StateOptions) {
+		options.TraceRegistry = registry
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/cosi-project/runtime/api/v1alpha1"
 	"github.com/cosi-project/runtime/pkg/resource"
+	"github.com/cosi-project/runtime/pkg/resource/filter"
 	"github.com/cosi-project/runtime/pkg/resource/protobuf"
 	"github.com/cosi-project/runtime/pkg/state"
 )
@@ -21,14 +22,21 @@ import (
 type State struct {
 	v1alpha1.UnimplementedStateServer
 
-	state state.CoreState
+	state   state.CoreState
+	options StateOptions
 }
 
 // NewState initializes new gRPC State service implementation.
-func NewState(state state.CoreState) *State {
-	return &State{
+func NewState(state state.CoreState, opts ...StateOption) *State {
+	server := &State{
 		state: state,
 	}
+
+	for _, opt := range opts {
+		opt(&server.options)
+	}
+
+	return server
 }
 
 // Get a resource by type and ID.
@@ -70,7 +78,12 @@ func (server *State) List(req *v1alpha1.ListRequest, srv v1alpha1.State_ListServ
 				return err
 			}
 
-			opts = append(opts, state.WithLabelQuery(labelOpts...))
+			selectorOpts, err := ConvertLabelSelector(query.GetSelector())
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, state.WithLabelQuery(append(labelOpts, selectorOpts...)...))
 		}
 
 		if req.GetOptions().GetIdQuery() != nil {
@@ -81,6 +94,15 @@ func (server *State) List(req *v1alpha1.ListRequest, srv v1alpha1.State_ListServ
 
 			opts = append(opts, state.WithIDQuery(idOpts...))
 		}
+
+		if req.GetOptions().GetFilterQuery() != nil {
+			expr, err := ConvertFilterQuery(req.GetOptions().GetFilterQuery())
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, state.WithFilter(expr))
+		}
 	}
 
 	items, err := server.state.List(srv.Context(), resource.NewMetadata(req.GetNamespace(), req.GetType(), "", resource.VersionUndefined), opts...)
@@ -127,7 +149,10 @@ func (server *State) Create(ctx context.Context, req *v1alpha1.CreateRequest) (*
 		return nil, err
 	}
 
+	commit := server.options.TraceRegistry.Record(ctx, r.Metadata())
+
 	err = server.state.Create(ctx, r, state.WithCreateOwner(req.GetOptions().GetOwner()))
+	commit(r.Metadata().Version(), err)
 
 	switch {
 	case state.IsNotFoundError(err):
@@ -195,7 +220,10 @@ func (server *State) Update(ctx context.Context, req *v1alpha1.UpdateRequest) (*
 		opts = append(opts, state.WithExpectedPhase(expectedPhase))
 	}
 
+	commit := server.options.TraceRegistry.Record(ctx, r.Metadata())
+
 	err = server.state.Update(ctx, r, opts...)
+	commit(r.Metadata().Version(), err)
 
 	switch {
 	case state.IsNotFoundError(err):
@@ -303,6 +331,60 @@ func (server *State) TeardownAndDestroy(ctx context.Context, req *v1alpha1.Teard
 	return &v1alpha1.TeardownAndDestroyResponse{}, nil
 }
 
+// History returns previous versions of a resource, most recent first.
+//
+// If the resource has no recorded versions, error is returned.
+// If the wrapped state doesn't implement [state.HistoryReader], Unimplemented error is returned.
+func (server *State) History(ctx context.Context, req *v1alpha1.HistoryRequest) (*v1alpha1.HistoryResponse, error) {
+	historyReader, ok := server.state.(state.HistoryReader)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "history is not supported")
+	}
+
+	items, err := historyReader.History(
+		ctx,
+		resource.NewMetadata(req.GetNamespace(), req.GetType(), req.GetId(), resource.VersionUndefined),
+		int(req.GetOptions().GetDepth()),
+	)
+
+	switch {
+	case state.IsNotFoundError(err):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case state.IsUnsupportedError(err):
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	resp := &v1alpha1.HistoryResponse{
+		Resources: make([]*v1alpha1.Resource, 0, len(items)),
+	}
+
+	for _, r := range items {
+		marshaled, err := marshalResource(r)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Resources = append(resp.Resources, marshaled)
+	}
+
+	if !req.GetOptions().GetDiff() {
+		return resp, nil
+	}
+
+	for i := range len(items) - 1 {
+		diff, err := marshalDiff(items[i+1], items[i])
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Diffs = append(resp.Diffs, diff)
+	}
+
+	return resp, nil
+}
+
 // Watch state of a resource by (namespace, type) or a specific resource by (namespace, type, id).
 //
 // It's fine to watch for a resource which doesn't exist yet.
@@ -347,7 +429,14 @@ func (server *State) Watch(req *v1alpha1.WatchRequest, srv v1alpha1.State_WatchS
 				return err
 			}
 
-			opts = append(opts, state.WatchWithLabelQuery(labelOpts...))
+			var selectorOpts []resource.LabelQueryOption
+
+			selectorOpts, err = ConvertLabelSelector(query.GetSelector())
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, state.WatchWithLabelQuery(append(labelOpts, selectorOpts...)...))
 		}
 
 		if req.GetOptions().GetIdQuery() != nil {
@@ -361,6 +450,17 @@ func (server *State) Watch(req *v1alpha1.WatchRequest, srv v1alpha1.State_WatchS
 			opts = append(opts, state.WatchWithIDQuery(idOpts...))
 		}
 
+		if req.GetOptions().GetFilterQuery() != nil {
+			var expr *filter.Expression
+
+			expr, err = ConvertFilterQuery(req.GetOptions().GetFilterQuery())
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, state.WatchWithFilter(expr))
+		}
+
 		if req.GetOptions().GetAggregated() {
 			err = server.state.WatchKindAggregated(ctx, resource.NewMetadata(req.GetNamespace(), req.GetType(), "", resource.VersionUndefined), aggregatedCh, opts...)
 		} else {
@@ -0,0 +1,126 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosi-project/runtime/pkg/tracing"
+)
+
+// UnaryTracingInterceptor returns a gRPC interceptor which creates OpenTelemetry spans for the unary State RPCs.
+//
+// Trace context is extracted from the incoming gRPC metadata (W3C Trace Context).
+// Create and Update record the trace context in the trace registry (see WithTraceRegistry),
+// so that the controllers which react to the change link their spans to the RPC span.
+func UnaryTracingInterceptor(tp trace.TracerProvider) grpc.UnaryServerInterceptor {
+	tracer := tracing.Tracer(tp)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := startRPCSpan(ctx, tracer, info.FullMethod)
+
+		if r, ok := req.(interface {
+			GetNamespace() string
+			GetType() string
+			GetId() string
+		}); ok {
+			span.SetAttributes(tracing.ResourceAttributes(r.GetNamespace(), r.GetType(), r.GetId())...)
+		}
+
+		resp, err := handler(ctx, req)
+
+		endRPCSpan(span, err)
+
+		return resp, err
+	}
+}
+
+// StreamTracingInterceptor returns a gRPC interceptor which creates OpenTelemetry spans for the streaming State RPCs.
+//
+// See UnaryTracingInterceptor.
+func StreamTracingInterceptor(tp trace.TracerProvider) grpc.StreamServerInterceptor {
+	tracer := tracing.Tracer(tp)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startRPCSpan(ss.Context(), tracer, info.FullMethod)
+
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+
+		endRPCSpan(span, err)
+
+		return err
+	}
+}
+
+func startRPCSpan(ctx context.Context, tracer trace.Tracer, fullMethod string) (context.Context, trace.Span) { //nolint:ireturn
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = propagation.TraceContext{}.Extract(ctx, metadataCarrier(md))
+	}
+
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	return tracer.Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", method),
+		),
+	)
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+type tracedServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+func (ss *tracedServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (carrier metadataCarrier) Get(key string) string {
+	values := metadata.MD(carrier).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func (carrier metadataCarrier) Set(key, value string) {
+	metadata.MD(carrier).Set(key, value)
+}
+
+func (carrier metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -140,6 +140,19 @@ type TeardownAndDestroyer interface {
 	TeardownAndDestroy(context.Context, resource.Pointer, ...TeardownAndDestroyOption) error
 }
 
+// HistoryReader is an optional interface a CoreState implementation may satisfy
+// to provide access to the previous versions of a resource.
+//
+// [WrapCore]'s State satisfies HistoryReader: the call is delegated to the wrapped
+// CoreState if it supports history, otherwise an unsupported error is returned.
+type HistoryReader interface {
+	// History returns up to n most recent versions of a resource, most recent first.
+	//
+	// If n is zero or negative, all recorded versions are returned.
+	// If the resource has no recorded versions, not found error is returned.
+	History(ctx context.Context, ptr resource.Pointer, n int, opts ...HistoryOption) ([]resource.Resource, error)
+}
+
 // State extends CoreState with additional features which can be implemented on any CoreState.
 type State interface {
 	CoreState
@@ -13,6 +13,8 @@ import (
 	"github.com/cosi-project/runtime/pkg/resource"
 )
 
+var _ HistoryReader = coreWrapper{}
+
 // WrapCore converts CoreState to State.
 func WrapCore(coreState CoreState) State { //nolint:ireturn
 	return coreWrapper{
@@ -294,6 +296,18 @@ func (state coreWrapper) waitFinalizersEmpty(ctx context.Context, resourcePointe
 	}
 }
 
+// History returns up to n most recent versions of a resource, most recent first.
+//
+// If the wrapped CoreState satisfies [HistoryReader], the call is delegated to it;
+// otherwise an unsupported error is returned.
+func (state coreWrapper) History(ctx context.Context, resourcePointer resource.Pointer, n int, opts ...HistoryOption) ([]resource.Resource, error) {
+	if h, ok := state.CoreState.(HistoryReader); ok {
+		return h.History(ctx, resourcePointer, n, opts...)
+	}
+
+	return nil, ErrUnsupportedOperation("history")
+}
+
 // Modify modifies an existing resource or creates a new one.
 //
 // It is a shorthand for Get+UpdateWithConflicts+Create.
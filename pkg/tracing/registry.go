@@ -0,0 +1,130 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// DefaultRegistrySize is the default number of the writes remembered by the Registry.
+const DefaultRegistrySize = 4096
+
+// Registry keeps the trace context of the recent resource writes.
+//
+// Trace context is kept out of band (in memory), so it is never persisted with the resource, and
+// recording it doesn't change the resource. Each entry is bound to the resource version produced by the write,
+// so that a later write which was not traced doesn't inherit the trace context.
+//
+// Registry remembers a limited number of the writes, the oldest entries are evicted first.
+type Registry struct {
+	entries map[registryKey]registryEntry
+	order   []registryKey
+	next    int
+	mu      sync.Mutex
+}
+
+type registryKey struct {
+	Namespace resource.Namespace
+	Type      resource.Type
+	ID        resource.ID
+}
+
+type registryEntry struct {
+	version     string
+	traceParent string
+}
+
+// NewRegistry creates a new Registry which remembers up to size writes.
+func NewRegistry(size int) *Registry {
+	if size <= 0 {
+		size = DefaultRegistrySize
+	}
+
+	return &Registry{
+		entries: make(map[registryKey]registryEntry, size),
+		order:   make([]registryKey, 0, size),
+	}
+}
+
+// Record stores the trace context of the span in the context for the write to the resource.
+//
+// Record should be called before the write, as the watch event might be processed before the write returns.
+// Until the returned commit function is called, the trace context is returned for any version of the resource.
+// Commit binds the trace context to the version produced by the write, or drops it if the write failed.
+//
+// If there is no valid span in the context, Record does nothing.
+func (registry *Registry) Record(ctx context.Context, ptr resource.Pointer) (commit func(version resource.Version, err error)) {
+	if registry == nil {
+		return func(resource.Version, error) {}
+	}
+
+	traceParent := TraceParent(ctx)
+	if traceParent == "" {
+		return func(resource.Version, error) {}
+	}
+
+	key := registryKey{Namespace: ptr.Namespace(), Type: ptr.Type(), ID: ptr.ID()}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.put(key, registryEntry{traceParent: traceParent})
+
+	return func(version resource.Version, err error) {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+
+		// the entry might have been replaced by a concurrent write
+		if entry, ok := registry.entries[key]; !ok || entry.traceParent != traceParent || entry.version != "" {
+			return
+		}
+
+		if err != nil {
+			// keep the entry in the eviction order, but forget the trace context
+			registry.entries[key] = registryEntry{}
+
+			return
+		}
+
+		registry.entries[key] = registryEntry{version: version.String(), traceParent: traceParent}
+	}
+}
+
+func (registry *Registry) put(key registryKey, entry registryEntry) {
+	if _, exists := registry.entries[key]; !exists {
+		if len(registry.order) < cap(registry.order) {
+			registry.order = append(registry.order, key)
+		} else {
+			delete(registry.entries, registry.order[registry.next])
+
+			registry.order[registry.next] = key
+			registry.next = (registry.next + 1) % len(registry.order)
+		}
+	}
+
+	registry.entries[key] = entry
+}
+
+// Lookup returns the trace context of the write which produced the resource version.
+//
+// If the write wasn't recorded (or it was evicted), empty string is returned.
+func (registry *Registry) Lookup(md *resource.Metadata) string {
+	if registry == nil {
+		return ""
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	entry, ok := registry.entries[registryKey{Namespace: md.Namespace(), Type: md.Type(), ID: md.ID()}]
+	if !ok || (entry.version != "" && entry.version != md.Version().String()) {
+		return ""
+	}
+
+	return entry.traceParent
+}
@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package tracing provides helpers for OpenTelemetry instrumentation of the runtime.
+//
+// Trace context is propagated between the writer of a resource and the controllers reacting to the change
+// out of band via the Registry (in the W3C Trace Context format), so it is never stored in the resource.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/cosi-project/runtime/pkg/resource"
+)
+
+// TracerName is the name of the tracer used by the runtime.
+const TracerName = "github.com/cosi-project/runtime"
+
+const traceParentHeader = "traceparent"
+
+// Attribute keys used in the spans.
+const (
+	AttributeController = attribute.Key("cosi.controller")
+	AttributeNamespace  = attribute.Key("cosi.resource.namespace")
+	AttributeType       = attribute.Key("cosi.resource.type")
+	AttributeID         = attribute.Key("cosi.resource.id")
+)
+
+// Tracer returns the runtime tracer from the provider.
+//
+// If the provider is nil, no-op tracer is returned.
+func Tracer(tp trace.TracerProvider) trace.Tracer { //nolint:ireturn
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+
+	return tp.Tracer(TracerName)
+}
+
+// ResourceAttributes returns span attributes describing the resource.
+//
+// Empty values are omitted.
+func ResourceAttributes(ns resource.Namespace, typ resource.Type, id resource.ID) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3)
+
+	for _, attr := range []attribute.KeyValue{
+		AttributeNamespace.String(ns),
+		AttributeType.String(typ),
+		AttributeID.String(id),
+	} {
+		if attr.Value.AsString() != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+// TraceParent returns the W3C traceparent of the span in the context.
+//
+// If there is no valid span in the context, empty string is returned.
+func TraceParent(ctx context.Context) string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ""
+	}
+
+	carrier := propagation.MapCarrier{}
+
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	return carrier.Get(traceParentHeader)
+}
+
+// ParseTraceParent parses the W3C traceparent.
+//
+// If the traceparent is empty or invalid, invalid span context is returned.
+func ParseTraceParent(traceParent string) trace.SpanContext {
+	if traceParent == "" {
+		return trace.SpanContext{}
+	}
+
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{traceParentHeader: traceParent})
+
+	return trace.SpanContextFromContext(ctx)
+}
+
+// Links converts trace parents into span links, skipping invalid ones.
+func Links(traceParents ...string) []trace.Link {
+	var links []trace.Link
+
+	for _, traceParent := range traceParents {
+		if spanCtx := ParseTraceParent(traceParent); spanCtx.IsValid() {
+			links = append(links, trace.Link{SpanContext: spanCtx})
+		}
+	}
+
+	return links
+}
+
+// End the span recording the error.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}